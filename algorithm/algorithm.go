@@ -1,6 +1,7 @@
 package algorithm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,8 +18,31 @@ import (
 
 var algoFactory *AlgorithmFactory
 
+// AlgorithmCreator creates the algorithm of the algorithm type from the config, the algorithm is initialized by Init after created
+type AlgorithmCreator func(conf recconf.AlgoConfig) IAlgorithm
+
+// algorithmCreators are the creators of the algorithm types used by AlgorithmFactory
+var algorithmCreators = make(map[string]AlgorithmCreator)
+
+// RegisterAlgorithmCreator registers the creator of the algorithm type, so the custom algorithm can be configured by Type like the built-in ones,
+// the creator of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterAlgorithmCreator(algoType string, creator AlgorithmCreator) {
+	if creator == nil {
+		panic("AlgorithmCreator is nil, type:" + algoType)
+	}
+
+	algorithmCreators[algoType] = creator
+	recconf.RegisterSchemaEnum("AlgoConfig.Type", algoType)
+}
+
 func init() {
 	algoFactory = NewAlgorithmFactory()
+
+	RegisterAlgorithmCreator("EAS", func(conf recconf.AlgoConfig) IAlgorithm { return eas.NewEasModel(conf.Name) })
+	RegisterAlgorithmCreator("FAISS", func(conf recconf.AlgoConfig) IAlgorithm { return faiss.NewFaissModel(conf.Name) })
+	RegisterAlgorithmCreator("LOOKUP", func(conf recconf.AlgoConfig) IAlgorithm { return NewLookupPolicy() })
+	RegisterAlgorithmCreator("SELDON", func(conf recconf.AlgoConfig) IAlgorithm { return new(seldon.Model) })
+	RegisterAlgorithmCreator("TFSERVING", func(conf recconf.AlgoConfig) IAlgorithm { return tfserving.NewTFservingModel(conf.Name) })
 }
 
 // type AlgoData struct {
@@ -30,6 +54,11 @@ type IAlgorithm interface {
 	Run(algoData interface{}) (interface{}, error)
 }
 
+// IContextAlgorithm is implemented by the algorithm which can be bounded by the request deadline
+type IContextAlgorithm interface {
+	RunWithContext(ctx context.Context, algoData interface{}) (interface{}, error)
+}
+
 type AlgorithmFactory struct {
 	algorithms       map[string]IAlgorithm
 	requestDataFuncs map[string]RequestDataFunc
@@ -46,10 +75,24 @@ func NewAlgorithmFactory() *AlgorithmFactory {
 
 	return &factory
 }
+
+// Init adds the changed algorithms of the configs, the algorithms failed to construct are logged and skipped
 func (a *AlgorithmFactory) Init(algoConfs []recconf.AlgoConfig) {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+	commit, errs := a.Prepare(algoConfs)
+	for _, err := range errs {
+		log.Error(err.Error())
+	}
 
+	commit()
+}
+
+// Prepare builds and initializes the changed algorithms of the configs without adding them, commit adds the algorithms built.
+// The errors of all the algorithms are returned, and nothing should be committed when there are errors.
+func (a *AlgorithmFactory) Prepare(algoConfs []recconf.AlgoConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]IAlgorithm)
+	signs := make(map[string]string)
+
+	a.mutex.RLock()
 	for _, conf := range algoConfs {
 		sign, _ := json.Marshal(conf)
 		if _, ok := a.algorithms[conf.Name]; ok {
@@ -57,51 +100,49 @@ func (a *AlgorithmFactory) Init(algoConfs []recconf.AlgoConfig) {
 				continue
 			}
 		}
-		algo, err := a.initAlgo(conf)
+
+		conf := conf
+		algo, err := recconf.BuildModule("algorithm", conf.Name, conf.Type, func() IAlgorithm {
+			algo, err := a.initAlgo(conf)
+			if err != nil {
+				panic(err)
+			}
+			return algo
+		})
 		if err != nil {
-			log.Error(err.Error())
+			errs = append(errs, err)
 			continue
 		}
-		a.algorithms[conf.Name] = algo
-		a.algorithmSigns[conf.Name] = utils.Md5(string(sign))
+
+		built[conf.Name] = algo
+		signs[conf.Name] = utils.Md5(string(sign))
 	}
-}
-func (a *AlgorithmFactory) initAlgo(conf recconf.AlgoConfig) (IAlgorithm, error) {
-	var algo IAlgorithm
-	if conf.Type == "EAS" {
-		algo = eas.NewEasModel(conf.Name)
-		err := algo.Init(&conf)
-		if err != nil {
-			return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
-		}
-	} else if conf.Type == "FAISS" {
-		algo = faiss.NewFaissModel(conf.Name)
-		err := algo.Init(&conf)
-		if err != nil {
-			return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
-		}
-	} else if conf.Type == "LOOKUP" {
-		algo = NewLookupPolicy()
-		err := algo.Init(&conf)
-		if err != nil {
-			return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
-		}
-	} else if conf.Type == "SELDON" {
-		algo = new(seldon.Model)
-		err := algo.Init(&conf)
-		if err != nil {
-			return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
-		}
-	} else if conf.Type == "TFSERVING" {
-		algo = tfserving.NewTFservingModel(conf.Name)
-		err := algo.Init(&conf)
-		if err != nil {
-			return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
+	a.mutex.RUnlock()
+
+	commit = func() {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+
+		for name, algo := range built {
+			a.algorithms[name] = algo
+			a.algorithmSigns[name] = signs[name]
 		}
+	}
+
+	return commit, errs
+}
 
-	} else {
+func (a *AlgorithmFactory) initAlgo(conf recconf.AlgoConfig) (IAlgorithm, error) {
+	creator, ok := algorithmCreators[conf.Type]
+	if !ok {
 		return nil, fmt.Errorf("algorithm type not support , type:%s", conf.Type)
 	}
+
+	algo := creator(conf)
+	err := algo.Init(&conf)
+	if err != nil {
+		return nil, fmt.Errorf("init algorithm error, name:%s, err:%v", conf.Name, err)
+	}
 	return algo, nil
 }
 func (a *AlgorithmFactory) Run(name string, algoData interface{}) (interface{}, error) {
@@ -119,13 +160,66 @@ func (a *AlgorithmFactory) Run(name string, algoData interface{}) (interface{},
 	return algo.Run(algoData)
 }
 
+// RunWithContext run the algorithm, return early with the ctx error when the ctx is done before the algorithm finished
+func (a *AlgorithmFactory) RunWithContext(ctx context.Context, name string, algoData interface{}) (interface{}, error) {
+	a.mutex.RLock()
+	algo, found := a.algorithms[name]
+	f, funcFound := a.requestDataFuncs[name]
+	a.mutex.RUnlock()
+	if !found {
+		return nil, errors.New("not found algorithm, name:" + name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("run algorithm error, name:%s, err:%w", name, err)
+	}
+	if funcFound {
+		algoData = f(name, algoData)
+	}
+
+	run := func() (interface{}, error) {
+		if ctxAlgo, ok := algo.(IContextAlgorithm); ok {
+			return ctxAlgo.RunWithContext(ctx, algoData)
+		}
+		return algo.Run(algoData)
+	}
+	// no deadline, no need to wait in another goroutine
+	if ctx.Done() == nil {
+		return run()
+	}
+
+	type runResult struct {
+		ret interface{}
+		err error
+	}
+	ch := make(chan runResult, 1)
+	go func() {
+		ret, err := run()
+		ch <- runResult{ret: ret, err: err}
+	}()
+
+	select {
+	case result := <-ch:
+		return result.ret, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("run algorithm error, name:%s, err:%w", name, ctx.Err())
+	}
+}
+
 // init algorithm from the config, and add to the algoFactory
 func Load(config *recconf.RecommendConfig) {
 	algoFactory.Init(config.AlgoConfs)
 }
+
+// Prepare builds the changed algorithms of the config, commit adds them to the algoFactory
+func Prepare(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	return algoFactory.Prepare(config.AlgoConfs)
+}
 func Run(name string, algoData interface{}) (interface{}, error) {
 	return algoFactory.Run(name, algoData)
 }
+func RunWithContext(ctx context.Context, name string, algoData interface{}) (interface{}, error) {
+	return algoFactory.RunWithContext(ctx, name, algoData)
+}
 func AddAlgo(conf recconf.AlgoConfig) {
 	algoFactory.mutex.Lock()
 	defer algoFactory.mutex.Unlock()
@@ -1,6 +1,7 @@
 package eas
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -25,6 +26,12 @@ const (
 	Eas_Processor_LINUCB    = "Linucb"
 )
 
+func init() {
+	processors := []string{Eas_Processor_FM, Eas_Processor_PMML, Eas_Processor_TF, Eas_Processor_TFServing, Eas_Processor_EASYREC, Eas_Processor_LINUCB}
+	recconf.RegisterSchemaEnum("EasConfig.Processor", processors...)
+	recconf.RegisterSchemaEnum("RankConfig.Processor", processors...)
+}
+
 type EasModel struct {
 	retryTimes int
 	name       string
@@ -188,6 +195,11 @@ func (m *EasModel) Init(conf *recconf.AlgoConfig) error {
 	return errors.New("not found eas Processor:" + conf.EasConf.Processor)
 }
 func (m *EasModel) Run(algoData interface{}) (interface{}, error) {
+	return m.RunWithContext(context.Background(), algoData)
+}
+
+// RunWithContext is the same as Run, but it stops retrying when the ctx is done
+func (m *EasModel) RunWithContext(ctx context.Context, algoData interface{}) (interface{}, error) {
 	retryTimes := m.retryTimes
 
 	var (
@@ -196,6 +208,12 @@ func (m *EasModel) Run(algoData interface{}) (interface{}, error) {
 	)
 
 	for {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				err = ctxErr
+			}
+			return data, err
+		}
 		retryTimes--
 		data, err = m.request.Invoke(algoData)
 		if err != nil && retryTimes == 0 {
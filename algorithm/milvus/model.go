@@ -6,37 +6,94 @@ import (
 	"time"
 
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
+// Default_Connect_Timeout is the timeout of connecting the milvus server in milliseconds when MilvusConfig.Timeout is not set
+const Default_Connect_Timeout = 3000
+
+// SearchClient is the part of the milvus client used by MilvusModel, the tests use a fake of it instead of the milvus server
+type SearchClient interface {
+	Search(ctx context.Context, collName string, partitions []string, expr string, outputFields []string, vectors []entity.Vector,
+		vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error)
+}
+
 type MilvusModel struct {
-	name   string
-	client client.Client
+	name    string
+	client  SearchClient
+	timeout time.Duration
 }
 
 func NewMilvusModel(name string) *MilvusModel {
 	return &MilvusModel{name: name}
 }
+
+// NewMilvusModelWithClient creates the model searching by the client, Init is not needed
+func NewMilvusModelWithClient(name string, searchClient SearchClient, timeout time.Duration) *MilvusModel {
+	return &MilvusModel{name: name, client: searchClient, timeout: timeout}
+}
+
 func (m *MilvusModel) Init(conf *recconf.AlgoConfig) error {
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*time.Duration(conf.MilvusConf.Timeout))
+	connectTimeout := conf.MilvusConf.Timeout
+	if connectTimeout <= 0 {
+		connectTimeout = Default_Connect_Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*time.Duration(connectTimeout))
 	defer cancel()
-	client, err := client.NewGrpcClient(ctx, conf.MilvusConf.ServerAddress)
+
+	var (
+		c   client.Client
+		err error
+	)
+	if conf.MilvusConf.Username != "" {
+		c, err = client.NewDefaultGrpcClientWithAuth(ctx, conf.MilvusConf.ServerAddress, conf.MilvusConf.Username, conf.MilvusConf.Password)
+	} else {
+		c, err = client.NewGrpcClient(ctx, conf.MilvusConf.ServerAddress)
+	}
 	if err != nil {
 		return err
 	}
 
-	m.client = client
+	m.client = c
+	m.timeout = time.Millisecond * time.Duration(conf.MilvusConf.Timeout)
 	return nil
-
 }
+
 func (m *MilvusModel) Run(algoData interface{}) (response interface{}, err error) {
+	return m.RunWithContext(context.Background(), algoData)
+}
+
+// RunWithContext searches the vectors of the MilvusRequest, the result of each vector is returned as the MilvusResponse in order.
+// The search is bounded by the timeout of the config besides the ctx.
+func (m *MilvusModel) RunWithContext(ctx context.Context, algoData interface{}) (response interface{}, err error) {
 	request, ok := algoData.(*MilvusRequest)
 	if !ok {
 		err = errors.New("requestData is not MilvusRequest type")
 		return
 	}
+	if m.client == nil {
+		err = errors.New("milvus client not init, name:" + m.name)
+		return
+	}
+
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	var searchParams entity.SearchParam = request.SearchParams
+	if request.SearchParams == nil {
+		searchParams = &MilvusSearchParams{params: map[string]interface{}{}}
+	}
+
+	results, err := m.client.Search(ctx, request.CollectionName, request.Partitions, request.Expr,
+		request.OutputFields, request.Vectors, request.VectorField, request.MetricType, request.TopK, searchParams)
+	if err != nil {
+		return nil, err
+	}
 
-	return m.client.Search(context.Background(), request.CollectionName, request.Partitions, request.Expr,
-		request.OutputFields, request.Vectors, request.VectorField, request.MetricType, request.TopK, request.SearchParams)
+	return NewMilvusResponses(results)
 }
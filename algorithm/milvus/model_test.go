@@ -0,0 +1,81 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type fakeSearchClient struct {
+	collection string
+	partitions []string
+	expr       string
+	metricType entity.MetricType
+	topK       int
+	params     map[string]interface{}
+	results    []client.SearchResult
+	err        error
+}
+
+func (c *fakeSearchClient) Search(ctx context.Context, collName string, partitions []string, expr string, outputFields []string, vectors []entity.Vector,
+	vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error) {
+	c.collection = collName
+	c.partitions = partitions
+	c.expr = expr
+	c.metricType = metricType
+	c.topK = topK
+	c.params = sp.Params()
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("expect the search deadline")
+	}
+
+	return c.results, c.err
+}
+
+func TestMilvusModelRun(t *testing.T) {
+	fake := &fakeSearchClient{
+		results: []client.SearchResult{
+			{ResultCount: 2, IDs: entity.NewColumnInt64("id", []int64{1, 2}), Scores: []float32{0.9, 0.8}},
+			{ResultCount: 1, IDs: entity.NewColumnVarChar("id", []string{"a"}), Scores: []float32{0.7}},
+		},
+	}
+	model := NewMilvusModelWithClient("milvus", fake, time.Second)
+
+	request := NewMilvusRequest()
+	request.CollectionName = "items"
+	request.Partitions = []string{"p1"}
+	request.Expr = "status == 1"
+	request.TopK = 10
+	request.SetMetricType("l2")
+	request.SetSearchParams(map[string]interface{}{"ef": 64})
+	request.AppendVectors([]float32{0.1, 0.2})
+	request.AppendVectors([]float32{0.3, 0.4})
+
+	ret, err := model.Run(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.collection != "items" || !reflect.DeepEqual(fake.partitions, []string{"p1"}) || fake.expr != "status == 1" ||
+		fake.metricType != entity.L2 || fake.topK != 10 || fake.params["ef"] != 64 {
+		t.Errorf("expect the request params passed to the search, got %+v", fake)
+	}
+
+	responses := ret.([]*MilvusResponse)
+	if len(responses) != 2 || !reflect.DeepEqual(responses[0].Ids, []string{"1", "2"}) || !reflect.DeepEqual(responses[1].Ids, []string{"a"}) {
+		t.Errorf("expect the ids of the int64 and varchar primary keys, got %v %v", responses[0], responses[1])
+	}
+
+	fake.err = errors.New("search error")
+	if _, err := model.Run(request); err == nil {
+		t.Error("expect the search error")
+	}
+
+	if _, err := model.Run("invalid request"); err == nil {
+		t.Error("expect the request type error")
+	}
+}
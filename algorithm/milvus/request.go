@@ -1,6 +1,10 @@
 package milvus
 
-import "github.com/milvus-io/milvus-sdk-go/v2/entity"
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
 
 type MilvusRequest struct {
 	TopK           int
@@ -20,13 +24,13 @@ func NewMilvusRequest() *MilvusRequest {
 	}
 }
 
+// SetMetricType sets the metric type of the index, such as IP and L2, IP is used when it is empty
 func (r *MilvusRequest) SetMetricType(metricType string) {
-	switch metricType {
-	case "IP":
-		r.MetricType = entity.IP
-	case "L2":
-		r.MetricType = entity.L2
+	if metricType == "" {
+		return
 	}
+
+	r.MetricType = entity.MetricType(strings.ToUpper(metricType))
 }
 
 func (r *MilvusRequest) SetSearchParams(params map[string]interface{}) {
@@ -0,0 +1,48 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// MilvusResponse is the result of searching a vector, Ids are the primary keys of the entities, and Scores are the distances of them
+type MilvusResponse struct {
+	Ids    []string
+	Scores []float32
+}
+
+// NewMilvusResponses converts the search results, the int64 and varchar primary keys are supported
+func NewMilvusResponses(results []client.SearchResult) ([]*MilvusResponse, error) {
+	responses := make([]*MilvusResponse, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		response := &MilvusResponse{
+			Ids:    make([]string, 0, result.ResultCount),
+			Scores: result.Scores,
+		}
+		switch ids := result.IDs.(type) {
+		case *entity.ColumnInt64:
+			for _, id := range ids.Data() {
+				response.Ids = append(response.Ids, strconv.FormatInt(id, 10))
+			}
+		case *entity.ColumnVarChar:
+			response.Ids = append(response.Ids, ids.Data()...)
+		case nil:
+		default:
+			return nil, fmt.Errorf("milvus primary key type not support, type:%v", result.IDs.Type())
+		}
+		if len(response.Scores) > len(response.Ids) {
+			response.Scores = response.Scores[:len(response.Ids)]
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
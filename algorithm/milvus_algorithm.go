@@ -0,0 +1,15 @@
+//go:build milvus
+
+package algorithm
+
+import (
+	"github.com/alibaba/pairec/v2/algorithm/milvus"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+// The milvus sdk and the lindorm driver both register common.proto to the protobuf registry, so the server built with the milvus tag
+// should run with GOLANG_PROTOBUF_REGISTRATION_CONFLICT=warn, or be built with
+// -ldflags "-X google.golang.org/protobuf/reflect/protoregistry.conflictPolicy=warn".
+func init() {
+	RegisterAlgorithmCreator("MILVUS", func(conf recconf.AlgoConfig) IAlgorithm { return milvus.NewMilvusModel(conf.Name) })
+}
@@ -0,0 +1,29 @@
+//go:build !milvus
+
+package algorithm
+
+import (
+	"errors"
+
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+// Milvus_Disabled_Error is the error of the MILVUS algorithm when the server is built without the milvus tag
+var Milvus_Disabled_Error = errors.New("MILVUS algorithm is not built, build with -tags milvus")
+
+func init() {
+	RegisterAlgorithmCreator("MILVUS", func(conf recconf.AlgoConfig) IAlgorithm { return disabledAlgorithm{err: Milvus_Disabled_Error} })
+}
+
+// disabledAlgorithm is the algorithm not built in the server, it fails to init with the err
+type disabledAlgorithm struct {
+	err error
+}
+
+func (a disabledAlgorithm) Init(conf *recconf.AlgoConfig) error {
+	return a.err
+}
+
+func (a disabledAlgorithm) Run(algoData interface{}) (interface{}, error) {
+	return nil, a.err
+}
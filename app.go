@@ -1,20 +1,42 @@
 package pairec
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
-	"sync"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/alibaba/pairec/v2/middleware/prometheus"
+	"github.com/alibaba/pairec/v2/web"
+	"github.com/alibaba/pairec/v2/web/pairec_web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
+// Default_Shutdown_Timeout is the max time of draining the in-flight requests
+const Default_Shutdown_Timeout = 30 * time.Second
+
 var (
 	PairecApp *App
+
+	// probePaths are served before the app is ready, the other routes return 503 until the app is ready
+	probePaths = map[string]bool{
+		"/ping":    true,
+		"/ready":   true,
+		"/metrics": true,
+	}
 )
 
 func init() {
@@ -24,16 +46,34 @@ func init() {
 type App struct {
 	Handlers *ControllerRegister
 	Server   *http.Server
+
+	// GrpcServer serves the grpc api when the ListenConf.GrpcPort is set
+	GrpcServer       *grpc.Server
+	GrpcInterceptors []grpc.UnaryServerInterceptor
+
+	healthServer *health.Server
+	started      bool
+	ready        atomic.Bool
+	// stopCh receives the error of the server stopped unexpectedly
+	stopCh chan error
 }
 
 func NewApp() *App {
 	cr := NewControllerRegister()
-	app := &App{Handlers: cr, Server: &http.Server{}}
+	app := &App{Handlers: cr, Server: &http.Server{}, stopCh: make(chan error, 2)}
 
 	return app
 }
 
+// Run starts the servers and waits for the shutdown, the app is ready as soon as the servers start
 func (app *App) Run() {
+	app.Start()
+	app.SetReady(true)
+	app.WaitShutdown()
+}
+
+// Start starts the http server and the grpc server without blocking, the app is not ready until SetReady is called
+func (app *App) Start() {
 	mode := os.Getenv("RUN_MODE")
 	if mode == "COMMAND" {
 		return
@@ -74,29 +114,159 @@ func (app *App) Run() {
 		}
 
 		app.Use(p.HandlerFunc)
+		app.UseGrpc(p.UnaryServerInterceptor)
 	}
 	app.Handlers.ApplyMiddlewares()
 
-	app.Server.Handler = app.Handlers
+	app.Server.Handler = app.readyHandler(app.Handlers)
 	app.Server.Addr = addr
 	app.Server.ReadTimeout = 30 * time.Second
 	app.Server.WriteTimeout = 30 * time.Second
 	app.Server.MaxHeaderBytes = 1 << 20
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
 		if err := app.Server.ListenAndServe(); err != http.ErrServerClosed {
 			log.Error(fmt.Sprintf("server stop, err=%v", err))
+			app.stopCh <- err
 		}
 	}()
 
+	if recconf.Config.ListenConf.GrpcPort > 0 {
+		grpcAddr := fmt.Sprintf("%s:%d", recconf.Config.ListenConf.HttpAddr, recconf.Config.ListenConf.GrpcPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			panic(fmt.Sprintf("grpc server listen error:%v", err))
+		}
+
+		// the request id is set first, then the recovery wraps the other interceptors and the handler
+		interceptors := []grpc.UnaryServerInterceptor{web.GrpcRequestIdInterceptor, web.GrpcRecoveryInterceptor, app.grpcReadyInterceptor}
+		interceptors = append(interceptors, app.GrpcInterceptors...)
+
+		app.GrpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+		pairec_web.RegisterPairecServiceServer(app.GrpcServer, web.NewGrpcServer())
+
+		// the grpc health service reports NOT_SERVING until the app is ready
+		app.healthServer = health.NewServer()
+		app.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(app.GrpcServer, app.healthServer)
+
+		go func() {
+			if err := app.GrpcServer.Serve(listener); err != nil {
+				log.Error(fmt.Sprintf("grpc server stop, err=%v", err))
+				app.stopCh <- err
+			}
+		}()
+		fmt.Println("grpc server start")
+	}
+
+	app.started = true
 	fmt.Println("server start")
-	wg.Wait()
+}
+
+// SetReady sets the readiness of the app, which is reported by /ready and the grpc health service
+func (app *App) SetReady(ready bool) {
+	app.ready.Store(ready)
+
+	if app.healthServer != nil {
+		if ready {
+			app.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		} else {
+			app.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+	}
+}
+
+func (app *App) IsReady() bool {
+	return app.ready.Load()
+}
+
+// readyHandler returns 503 for the routes other than the probes until the app is ready,
+// so no request reads the recalls, the algorithms and the daos while they are built by the start hooks
+func (app *App) readyHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !app.IsReady() && !probePaths[r.URL.Path] {
+			Error(w, http.StatusServiceUnavailable, "not ready")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// grpcReadyInterceptor returns Unavailable for the methods other than the health check until the app is ready
+func (app *App) grpcReadyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !app.IsReady() && !strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health/") {
+		return nil, status.Error(codes.Unavailable, "not ready")
+	}
+	return handler(ctx, req)
+}
+
+// WaitShutdown blocks until SIGTERM or SIGINT is received or the server stops unexpectedly.
+// Then the app is not ready, the listeners are kept for the ListenConf.ShutdownDrainDelay,
+// the in-flight requests are drained within the ListenConf.ShutdownTimeout,
+// and the shutdown hooks run in order to flush the log and the data sinks.
+func (app *App) WaitShutdown() {
+	if !app.started {
+		return
+	}
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signalCh)
+
+	select {
+	case sig := <-signalCh:
+		log.Info(fmt.Sprintf("event=shutdown	signal=%v", sig))
+	case err := <-app.stopCh:
+		log.Error(fmt.Sprintf("event=shutdown	error=%v", err))
+	}
+
+	app.Shutdown()
+}
+
+// Shutdown stops the servers gracefully and runs the shutdown hooks
+func (app *App) Shutdown() {
+	app.SetReady(false)
+
+	// the listeners are kept during the drain delay, so the load balancer has time to find the app not ready
+	if delay := time.Duration(recconf.Config.ListenConf.ShutdownDrainDelay) * time.Second; delay > 0 {
+		log.Info(fmt.Sprintf("event=shutdown	drain_delay=%v", delay))
+		time.Sleep(delay)
+	}
+
+	timeout := time.Duration(recconf.Config.ListenConf.ShutdownTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = Default_Shutdown_Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if app.GrpcServer != nil {
+		done := make(chan struct{})
+		go func() {
+			app.GrpcServer.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			app.GrpcServer.Stop()
+		}
+	}
+
+	if err := app.Server.Shutdown(ctx); err != nil {
+		log.Error(fmt.Sprintf("event=shutdown	error=%v", err))
+	}
+
+	runShutdownHook(ctx)
+	log.Info("event=shutdown	msg=server stopped")
 	log.Flush()
 }
 
 func (app *App) Use(middleware ...MiddlewareFunc) {
 	app.Handlers.Middlewares = append(app.Handlers.Middlewares, middleware...)
 }
+
+// UseGrpc adds the interceptors of the grpc server, they run after the request id is set
+func (app *App) UseGrpc(interceptors ...grpc.UnaryServerInterceptor) {
+	app.GrpcInterceptors = append(app.GrpcInterceptors, interceptors...)
+}
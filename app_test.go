@@ -0,0 +1,105 @@
+package pairec
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplyMiddlewaresAfterStart(t *testing.T) {
+	app := NewApp()
+	var count int
+	app.Use(func(next func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			count++
+			next(w, r)
+		}
+	})
+
+	hf := func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "success") }
+	app.Handlers.Register(&RouteInfo{pattern: "/before", hf: hf})
+	app.Handlers.ApplyMiddlewares()
+	app.Handlers.Register(&RouteInfo{pattern: "/after", hf: hf})
+
+	for _, path := range []string{"/before", "/after"} {
+		w := httptest.NewRecorder()
+		app.Handlers.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Body.String() != "success" {
+			t.Errorf("expect success of %s, got %s", path, w.Body.String())
+		}
+	}
+	if count != 2 {
+		t.Errorf("expect the middleware used by the routes registered before and after start, got %d", count)
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	app := NewApp()
+	hf := func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "success") }
+	app.Handlers.Register(&RouteInfo{pattern: "/ping", hf: hf})
+	app.Handlers.Register(&RouteInfo{pattern: "/api/recommend", hf: hf})
+	handler := app.readyHandler(app.Handlers)
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+	if code := serve("/ping"); code != http.StatusOK {
+		t.Errorf("expect /ping served before ready, got %d", code)
+	}
+	if code := serve("/api/recommend"); code != http.StatusServiceUnavailable {
+		t.Errorf("expect /api/recommend 503 before ready, got %d", code)
+	}
+
+	app.SetReady(true)
+	if code := serve("/api/recommend"); code != http.StatusOK {
+		t.Errorf("expect /api/recommend served after ready, got %d", code)
+	}
+}
+
+func TestShutdownHook(t *testing.T) {
+	app := NewApp()
+	if app.IsReady() {
+		t.Error("expect the app not ready before SetReady")
+	}
+	app.SetReady(true)
+	if !app.IsReady() {
+		t.Error("expect the app ready")
+	}
+
+	var order []int
+	AddShutdownHook(func() error {
+		order = append(order, 1)
+		return nil
+	}, func() error {
+		order = append(order, 2)
+		return nil
+	})
+	defer func() { shutdownHooks = shutdownHooks[:0] }()
+
+	app.Shutdown()
+	if app.IsReady() {
+		t.Error("expect the app not ready after shutdown")
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("expect the shutdown hooks run in order, got %v", order)
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	start := time.Now()
+	waitUntil(ctx, "test", func() { <-block })
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("expect the wait bounded by the ctx, cost %v", cost)
+	}
+}
@@ -20,3 +20,21 @@ func (cfg *ProjectConfiguration) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&cfg.Name, "name", "", "",
 		"project name")
 }
+
+type ConfigCheckConfiguration struct {
+	Format string // output format, text or json
+}
+
+func (cfg *ConfigCheckConfiguration) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&cfg.Format, "format", "f", "text",
+		"output format, text or json")
+}
+
+type ConfigSchemaConfiguration struct {
+	Output string // output file of the json schema, stdout if empty
+}
+
+func (cfg *ConfigSchemaConfiguration) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&cfg.Output, "output", "o", "",
+		"output file of the json schema, print to stdout if empty")
+}
@@ -27,6 +27,52 @@ func NewProjectCommand(v *viper.Viper, rootcfg *options.RootConfiguration) *cobr
 	return cmd
 }
 
+func NewConfigCommand(v *viper.Viper, rootcfg *options.RootConfiguration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the config of the pairec application",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Usage()
+		},
+	}
+	cmd.AddCommand(NewConfigCheckCommand(v, rootcfg))
+	cmd.AddCommand(NewConfigSchemaCommand(v, rootcfg))
+	return cmd
+}
+
+func NewConfigCheckCommand(v *viper.Viper, rootcfg *options.RootConfiguration) *cobra.Command {
+	cfg := &options.ConfigCheckConfiguration{}
+	cmd := &cobra.Command{
+		Use:   "check <file>",
+		Short: "Check the config file, exit 1 when the config has errors",
+		Args:  cobra.ExactArgs(1),
+		// the error is printed to the stderr by main, so the stdout keeps only the issues with the json format
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.ConfigCheck(rootcfg, cfg, args[0])
+		},
+	}
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func NewConfigSchemaCommand(v *viper.Viper, rootcfg *options.RootConfiguration) *cobra.Command {
+	cfg := &options.ConfigSchemaConfiguration{}
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Generate the json schema of the config, used by the editors and CI to validate and autocomplete the config files",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := commands.ConfigSchema(rootcfg, cfg); err != nil {
+				log.Error(err.Error())
+			}
+		},
+	}
+	cfg.AddFlags(cmd.Flags())
+	return cmd
+}
+
 func NewPairecCommand() *cobra.Command {
 	cfg := &options.RootConfiguration{}
 	rootcmd := &cobra.Command{
@@ -45,6 +91,7 @@ func NewPairecCommand() *cobra.Command {
 
 	v := viper.New()
 	rootcmd.AddCommand(NewProjectCommand(v, cfg))
+	rootcmd.AddCommand(NewConfigCommand(v, cfg))
 	/**
 	rootcmd.AddCommand(NewUpdateCommand(v, cfg))
 	rootcmd.AddCommand(NewListCommand(v, cfg))
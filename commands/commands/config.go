@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/alibaba/pairec/v2/pairecmd/app/options"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/fatih/color"
+
+	// register the known module types of the json schema
+	_ "github.com/alibaba/pairec/v2/algorithm"
+	_ "github.com/alibaba/pairec/v2/filter"
+	_ "github.com/alibaba/pairec/v2/service/feature"
+	_ "github.com/alibaba/pairec/v2/service/recall"
+	_ "github.com/alibaba/pairec/v2/service/recall/berecall"
+	_ "github.com/alibaba/pairec/v2/sort"
+)
+
+// ConfigCheck checks the config file and prints the errors and the warnings of the modules with the json paths,
+// the error is returned when the config has errors. With the json format only the issues are printed to the stdout,
+// and the status is printed to the stderr.
+func ConfigCheck(rootcfg *options.RootConfiguration, cfg *options.ConfigCheckConfiguration, filePath string) error {
+	data, err := recconf.ReadConfigFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read config file error, %v", err)
+	}
+
+	issues, err := recconf.LintRecommendConfig(string(data))
+	if err != nil {
+		return fmt.Errorf("parse config file error, %v", err)
+	}
+
+	var errorCount, warningCount int
+	for _, issue := range issues {
+		if issue.Level == recconf.Lint_Level_Error {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	if cfg.Format == "json" {
+		if issues == nil {
+			issues = make([]*recconf.LintIssue, 0)
+		}
+		j, _ := json.MarshalIndent(issues, "", "  ")
+		fmt.Println(string(j))
+	} else {
+		for _, issue := range issues {
+			if issue.Level == recconf.Lint_Level_Error {
+				fmt.Printf("[%v] %v\n", color.RedString("ERROR"), issue.String())
+			} else {
+				fmt.Printf("[%v] %v\n", color.YellowString("WARNING"), issue.String())
+			}
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("config check failed, file:%s, errors:%d, warnings:%d", filePath, errorCount, warningCount)
+	}
+
+	statusOut := os.Stdout
+	if cfg.Format == "json" {
+		statusOut = os.Stderr
+	}
+	fmt.Fprintf(statusOut, "[%v] config check passed, file:%s, warnings:%d\n", color.GreenString("SUCCESS"), filePath, warningCount)
+	return nil
+}
+
+// ConfigSchema generates the json schema of the config, with the enums of the module types registered by the factories
+func ConfigSchema(rootcfg *options.RootConfiguration, cfg *options.ConfigSchemaConfiguration) error {
+	data, err := recconf.MarshalJsonSchema()
+	if err != nil {
+		return fmt.Errorf("generate json schema error, %v", err)
+	}
+
+	if cfg.Output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(cfg.Output, data, 0644); err != nil {
+		return fmt.Errorf("write json schema error, %v", err)
+	}
+
+	fmt.Printf("[%v] json schema generated, file:%s\n", color.GreenString("SUCCESS"), cfg.Output)
+	return nil
+}
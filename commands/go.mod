@@ -3,9 +3,11 @@ module github.com/alibaba/pairec/v2/pairecmd
 go 1.16
 
 require (
-	github.com/fatih/color v1.10.0
+	github.com/alibaba/pairec/v2 v2.0.0-00010101000000-000000000000
+	github.com/fatih/color v1.12.0
 	github.com/spf13/cobra v1.1.3
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.1
-	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
 )
+
+replace github.com/alibaba/pairec/v2 => ../
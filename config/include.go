@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Include_Key is the top-level key listing the files merged into the config,
+// the paths are relative to the including file and may be the glob patterns
+const Include_Key = "Includes"
+
+// decoder decodes the file content into the json compatible value
+type decoder func(data []byte) (map[string]interface{}, error)
+
+// decoders of the included files by the file extension, the other extensions are decoded as json
+var decoders = map[string]decoder{
+	".yaml": decodeYaml,
+	".yml":  decodeYaml,
+	".toml": decodeToml,
+}
+
+func decodeJson(data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readWithIncludes reads the file and merges the included files into it, the included files are merged in order before the file itself,
+// so the objects are merged recursively, the lists are concatenated and the scalar values of the including file win.
+func readWithIncludes(filename string, decode decoder) ([]byte, error) {
+	result, err := loadWithIncludes(filename, decode, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(result)
+}
+
+func loadWithIncludes(filename string, decode decoder, stack []string) (map[string]interface{}, error) {
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range stack {
+		if path == absPath {
+			return nil, fmt.Errorf("Config:include cycle, %s", strings.Join(append(stack, absPath), " -> "))
+		}
+	}
+	stack = append(stack, absPath)
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decode(content)
+	if err != nil {
+		return nil, fmt.Errorf("Config:parse file %s error, %v", filename, err)
+	}
+
+	includes, err := includeFiles(filename, data[Include_Key])
+	if err != nil {
+		return nil, err
+	}
+	delete(data, Include_Key)
+
+	result := make(map[string]interface{})
+	for _, file := range includes {
+		includeDecode, ok := decoders[strings.ToLower(filepath.Ext(file))]
+		if !ok {
+			includeDecode = decodeJson
+		}
+		included, err := loadWithIncludes(file, includeDecode, stack)
+		if err != nil {
+			return nil, err
+		}
+		mergeConfigData(result, included)
+	}
+	mergeConfigData(result, data)
+
+	return result, nil
+}
+
+// includeFiles returns the included files of the value of Include_Key, the glob patterns are expanded in the lexical order
+func includeFiles(filename string, value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var patterns []string
+	switch v := value.(type) {
+	case string:
+		patterns = append(patterns, v)
+	case []interface{}:
+		for _, p := range v {
+			pattern, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("Config:%s of %s should be the list of the paths", Include_Key, filename)
+			}
+			patterns = append(patterns, pattern)
+		}
+	default:
+		return nil, fmt.Errorf("Config:%s of %s should be the list of the paths", Include_Key, filename)
+	}
+
+	dir := filepath.Dir(filename)
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Config:invalid include %s of %s, %v", pattern, filename, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, fmt.Errorf("Config:include %s of %s not exist", pattern, filename)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// mergeConfigData merges src into dst, the objects are merged recursively, the lists are concatenated and the other values of src win
+func mergeConfigData(dst, src map[string]interface{}) {
+	for key, srcVal := range src {
+		dstVal, ok := dst[key]
+		if !ok {
+			dst[key] = srcVal
+			continue
+		}
+
+		switch s := srcVal.(type) {
+		case map[string]interface{}:
+			if d, ok := dstVal.(map[string]interface{}); ok {
+				mergeConfigData(d, s)
+				continue
+			}
+		case []interface{}:
+			if d, ok := dstVal.([]interface{}); ok {
+				dst[key] = append(d, s...)
+				continue
+			}
+		}
+		dst[key] = srcVal
+	}
+}
+
+// toJsonValue converts the decoded value to the value decoded by encoding/json, such as the maps with the string keys and the float64 numbers
+func toJsonValue(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJson(data)
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestYamlConfigIncludes(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `
+# main config
+Includes:
+  - recalls/*.yaml
+  - dao.toml
+RunMode: product
+ListenConf:
+  HttpPort: 8000
+RecallConfs:
+  - Name: main_recall
+    RecallCount: 100
+`,
+		"recalls/a.yaml": `
+RecallConfs:
+  - Name: recall_a
+RunMode: daily
+`,
+		"recalls/b.yaml": `
+RecallConfs:
+  - Name: recall_b
+`,
+		"dao.toml": `
+[ListenConf]
+HttpAddr = "0.0.0.0"
+
+[RedisConfs.redis1]
+Host = "127.0.0.1"
+Port = 6379
+`,
+	})
+
+	configer, err := NewConfig("yaml", filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(configer.RawData(), &result)
+
+	expect := map[string]interface{}{
+		"RunMode": "product",
+		"ListenConf": map[string]interface{}{
+			"HttpAddr": "0.0.0.0",
+			"HttpPort": float64(8000),
+		},
+		"RecallConfs": []interface{}{
+			map[string]interface{}{"Name": "recall_a"},
+			map[string]interface{}{"Name": "recall_b"},
+			map[string]interface{}{"Name": "main_recall", "RecallCount": float64(100)},
+		},
+		"RedisConfs": map[string]interface{}{
+			"redis1": map[string]interface{}{"Host": "127.0.0.1", "Port": float64(6379)},
+		},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("expect %v, got %v", expect, result)
+	}
+	if v, _ := configer.String("RunMode"); v != "product" {
+		t.Errorf("expect RunMode product, got %s", v)
+	}
+}
+
+func TestTomlConfig(t *testing.T) {
+	os.Setenv("PAIREC_TEST_REDIS_PASSWORD", "secret")
+	defer os.Unsetenv("PAIREC_TEST_REDIS_PASSWORD")
+
+	dir := writeConfigFiles(t, map[string]string{
+		"config.toml": `
+RunMode = "product"
+
+[[RecallConfs]]
+Name = "recall_a"
+
+[RedisConfs.redis1]
+Password = "${PAIREC_TEST_REDIS_PASSWORD}"
+`,
+	})
+
+	configer, err := NewConfig("toml", filepath.Join(dir, "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `{"RecallConfs":[{"Name":"recall_a"}],"RedisConfs":{"redis1":{"Password":"secret"}},"RunMode":"product"}`
+	if string(configer.RawData()) != expect {
+		t.Errorf("expect %s, got %s", expect, configer.RawData())
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.yaml":       "Includes: [b.yaml]",
+		"b.yaml":       "Includes: [a.yaml]",
+		"missing.yaml": "Includes: [not_exist.yaml]",
+	})
+
+	if _, err := NewConfig("yaml", filepath.Join(dir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("expect the include cycle error, got %v", err)
+	}
+	if _, err := NewConfig("yaml", filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "not exist") {
+		t.Errorf("expect the include not exist error, got %v", err)
+	}
+}
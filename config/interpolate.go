@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// interpolationRegexp matches ${ENV_VAR} and ${file:/path}
+var interpolationRegexp = regexp.MustCompile(`\$\{(file:[^}]+|[A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolate replaces ${ENV_VAR} with the value of the environment variable and ${file:/path} with the content of the file,
+// the trailing newlines of the file are trimmed. The values are escaped as the json string, so the placeholders should be in the json strings.
+// ${ENV_VAR} of the unset environment variable is kept as it is, because the same syntax is used by the sql templates such as ${time}.
+func Interpolate(data []byte) ([]byte, error) {
+	var err error
+	result := interpolationRegexp.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := string(match[2 : len(match)-1])
+
+		var value string
+		if strings.HasPrefix(name, "file:") {
+			path := strings.TrimPrefix(name, "file:")
+			content, e := os.ReadFile(path)
+			if e != nil {
+				if err == nil {
+					err = fmt.Errorf("config interpolation read file error, path:%s, error:%v", path, e)
+				}
+				return match
+			}
+			value = strings.TrimRight(string(content), "\r\n")
+		} else {
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				return match
+			}
+			value = v
+		}
+
+		escaped, _ := json.Marshal(value)
+		return escaped[1 : len(escaped)-1]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secretFile, []byte("pass\"word\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PAIREC_TEST_ACCESS_KEY", "key")
+
+	data := `{"AccessKey":"${PAIREC_TEST_ACCESS_KEY}","Password":"${file:` + secretFile + `}","Where":"time > ${PAIREC_TEST_NOT_SET}"}`
+	configer, err := (&JsonConfig{}).ParseData([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := configer.String("AccessKey"); v != "key" {
+		t.Errorf("expect the environment variable interpolated, got %s", v)
+	}
+	if v, _ := configer.String("Password"); v != "pass\"word" {
+		t.Errorf("expect the file content interpolated, got %s", v)
+	}
+	if v, _ := configer.String("Where"); v != "time > ${PAIREC_TEST_NOT_SET}" {
+		t.Errorf("expect the unset environment variable kept, got %s", v)
+	}
+
+	if _, err := Interpolate([]byte(`{"Password":"${file:/not/exist}"}`)); err == nil {
+		t.Error("expect the error of the file not exist")
+	}
+}
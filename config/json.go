@@ -35,12 +35,18 @@ func (js *JsonConfig) ParseFile(filename string) (Configer, error) {
 	return js.ParseData(content)
 }
 
+// ParseData parses the json data after the ${ENV_VAR} and ${file:/path} are interpolated
 func (js *JsonConfig) ParseData(data []byte) (Configer, error) {
+	data, err := Interpolate(data)
+	if err != nil {
+		return nil, err
+	}
+
 	x := &JsonConfigContainer{
 		rawData: data,
 		data:    make(map[string]interface{}),
 	}
-	err := json.Unmarshal(data, &x.data)
+	err = json.Unmarshal(data, &x.data)
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +41,18 @@ func (js *PairecConfig) ParseFile(configName string) (config.Configer, error) {
 	return js.ParseData([]byte(configData))
 }
 
+// ParseData parses the json data after the ${ENV_VAR} and ${file:/path} are interpolated
 func (js *PairecConfig) ParseData(data []byte) (config.Configer, error) {
+	data, err := config.Interpolate(data)
+	if err != nil {
+		return nil, err
+	}
+
 	x := &PairecConfigContainer{
 		rawData: data,
 		data:    make(map[string]interface{}),
 	}
-	err := json.Unmarshal(data, &x.data)
+	err = json.Unmarshal(data, &x.data)
 	if err != nil {
 		return nil, err
 	}
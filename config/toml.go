@@ -0,0 +1,31 @@
+package config
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+func init() {
+	Register("toml", &TomlConfig{})
+}
+
+// TomlConfig parses the toml file into the same container as JsonConfig, RawData returns the json of the config,
+// so the toml file produces the same RecommendConfig as the json file. The files listed by Includes are merged at load time.
+type TomlConfig struct{}
+
+func (tc *TomlConfig) ParseFile(filename string) (Configer, error) {
+	data, err := readWithIncludes(filename, decodeToml)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&JsonConfig{}).ParseData(data)
+}
+
+func decodeToml(data []byte) (map[string]interface{}, error) {
+	value := make(map[string]interface{})
+	if err := toml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return toJsonValue(value)
+}
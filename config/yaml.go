@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register("yaml", &YamlConfig{})
+	Register("yml", &YamlConfig{})
+}
+
+// YamlConfig parses the yaml file into the same container as JsonConfig, RawData returns the json of the config,
+// so the yaml file produces the same RecommendConfig as the json file. The files listed by Includes are merged at load time.
+type YamlConfig struct{}
+
+func (yc *YamlConfig) ParseFile(filename string) (Configer, error) {
+	data, err := readWithIncludes(filename, decodeYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&JsonConfig{}).ParseData(data)
+}
+
+func decodeYaml(data []byte) (map[string]interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	return toJsonValue(normalizeYaml(value))
+}
+
+// normalizeYaml converts the maps with the non-string keys, which can't be marshaled as json
+func normalizeYaml(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = normalizeYaml(val)
+		}
+		return m
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = normalizeYaml(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = normalizeYaml(val)
+		}
+		return v
+	}
+
+	return value
+}
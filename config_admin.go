@@ -0,0 +1,144 @@
+package pairec
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+// adminAuth protects the admin routes by the ListenConf.AdminToken, the routes are forbidden when the token is not set
+func adminAuth(hf handleFunc) handleFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := recconf.Config.ListenConf.AdminToken
+		if token == "" {
+			Error(w, http.StatusForbidden, "admin api disabled")
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+			log.Warning(fmt.Sprintf("event=adminAuth\turi=%s\taddress=%s\terror=unauthorized", r.URL.Path, r.RemoteAddr))
+			Error(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		hf(w, r)
+	}
+}
+
+// configVersionsHandler lists the config versions kept in memory, the latest one is the version in use
+func configVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, recconf.GetConfigHistory().Versions())
+}
+
+// configDiffHandler returns the structural diff between the versions, from defaults to the version before to,
+// and to defaults to the latest version
+func configDiffHandler(w http.ResponseWriter, r *http.Request) {
+	history := recconf.GetConfigHistory()
+
+	to := history.Latest()
+	if v := r.URL.Query().Get("to"); v != "" {
+		to = getConfigVersion(w, v)
+		if to == nil {
+			return
+		}
+	}
+	if to == nil {
+		Error(w, http.StatusNotFound, "no config version")
+		return
+	}
+
+	from := history.Get(to.Version - 1)
+	if v := r.URL.Query().Get("from"); v != "" {
+		from = getConfigVersion(w, v)
+		if from == nil {
+			return
+		}
+	}
+
+	var fromConf *recconf.RecommendConfig
+	if from != nil {
+		fromConf = from.Config
+	}
+
+	writeJson(w, recconf.DiffConfig(fromConf, to.Config))
+}
+
+// configRollbackHandler republishes the config of the version through the same reload path of the config loaders,
+// the rollback is recorded as a new version
+func configRollbackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	version := getConfigVersion(w, r.URL.Query().Get("version"))
+	if version == nil {
+		return
+	}
+
+	if err := reloadConfig(version.Config); err != nil {
+		log.Error(fmt.Sprintf("event=configRollback\tversion=%d\terror=%v", version.Version, err))
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	recconf.UpdateConfWithSource(version.Config, fmt.Sprintf("rollback:%d", version.Version))
+	log.Info(fmt.Sprintf("event=configRollback\tversion=%d", version.Version))
+
+	writeJson(w, recconf.GetConfigHistory().Latest())
+}
+
+// configScenesHandler returns the resolved configs of the scenes after the scene inheritance, the scene param limits the scenes,
+// and the version param inspects the config of the version kept in the history instead of the config in use
+func configScenesHandler(w http.ResponseWriter, r *http.Request) {
+	conf := recconf.Config
+	if v := r.URL.Query().Get("version"); v != "" {
+		version := getConfigVersion(w, v)
+		if version == nil {
+			return
+		}
+		conf = version.Config
+	}
+
+	scene := r.URL.Query().Get("scene")
+	views := conf.SceneViews(scene)
+	if scene != "" && len(views) == 0 {
+		Error(w, http.StatusNotFound, fmt.Sprintf("scene not found:%s", scene))
+		return
+	}
+
+	writeJson(w, views)
+}
+
+// getConfigVersion returns the version kept in the history, the error is written when the version is invalid or not kept
+func getConfigVersion(w http.ResponseWriter, value string) *recconf.ConfigVersion {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		Error(w, http.StatusBadRequest, fmt.Sprintf("invalid version:%s", value))
+		return nil
+	}
+
+	version := recconf.GetConfigHistory().Get(v)
+	if version == nil {
+		Error(w, http.StatusNotFound, fmt.Sprintf("version not found:%d", v))
+		return nil
+	}
+
+	return version
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	d, _ := json.Marshal(v)
+	w.Header().Add("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, string(d))
+}
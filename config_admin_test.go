@@ -0,0 +1,104 @@
+package pairec
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func TestConfigRollback(t *testing.T) {
+	history := recconf.GetConfigHistory()
+
+	first, _ := recconf.ParseConfig([]byte(`{"RunMode":"daily"}`))
+	recconf.UpdateConfWithSource(first, "test")
+	version := history.Latest().Version
+	second, _ := recconf.ParseConfig([]byte(`{"RunMode":"product"}`))
+	recconf.UpdateConfWithSource(second, "test")
+
+	w := httptest.NewRecorder()
+	configDiffHandler(w, httptest.NewRequest("GET", "/admin/config/diff", nil))
+	var diff recconf.ConfigDiff
+	json.Unmarshal(w.Body.Bytes(), &diff)
+	if len(diff.Others) != 1 || diff.Others[0] != "RunMode" {
+		t.Errorf("expect RunMode changed, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	configRollbackHandler(w, httptest.NewRequest("GET", "/admin/config/rollback?version=1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expect the rollback only by POST, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	configRollbackHandler(w, httptest.NewRequest("POST", "/admin/config/rollback?version=-1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expect the version not found, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	configRollbackHandler(w, httptest.NewRequest("POST", "/admin/config/rollback?version="+strconv.Itoa(version), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect the rollback success, got %d %s", w.Code, w.Body.String())
+	}
+	if recconf.Config.RunMode != "daily" || history.Latest().Source == "test" {
+		t.Errorf("expect the config rolled back, got %s %s", recconf.Config.RunMode, history.Latest().Source)
+	}
+}
+
+func TestConfigScenes(t *testing.T) {
+	conf, err := recconf.ParseConfig([]byte(`{"SceneConfs":{"base":{"default":{"RecallNames":["r1"]}},"home":{"Extends":"base"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recconf.UpdateConfWithSource(conf, "test")
+
+	w := httptest.NewRecorder()
+	configScenesHandler(w, httptest.NewRequest("GET", "/admin/config/scenes?scene=home", nil))
+	var views map[string]*recconf.SceneView
+	json.Unmarshal(w.Body.Bytes(), &views)
+	if views["home"] == nil || views["home"].Extends != "base" || len(views["home"].Categories["default"].RecallNames) != 1 {
+		t.Errorf("expect the resolved scene, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	configScenesHandler(w, httptest.NewRequest("GET", "/admin/config/scenes?scene=unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expect the scene not found, got %d", w.Code)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	hf := adminAuth(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	serve := func(auth string) int {
+		r := httptest.NewRequest("POST", "/admin/config/rollback", nil)
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		hf(w, r)
+		return w.Code
+	}
+
+	token := recconf.Config.ListenConf.AdminToken
+	defer func() { recconf.Config.ListenConf.AdminToken = token }()
+
+	recconf.Config.ListenConf.AdminToken = ""
+	if code := serve("Bearer secret"); code != http.StatusForbidden {
+		t.Errorf("expect the admin api disabled without the token, got %d", code)
+	}
+
+	recconf.Config.ListenConf.AdminToken = "secret"
+	if code := serve(""); code != http.StatusUnauthorized {
+		t.Errorf("expect unauthorized without the header, got %d", code)
+	}
+	if code := serve("Bearer wrong"); code != http.StatusUnauthorized {
+		t.Errorf("expect unauthorized with the wrong token, got %d", code)
+	}
+	if code := serve("Bearer secret"); code != http.StatusOK {
+		t.Errorf("expect authorized with the token, got %d", code)
+	}
+}
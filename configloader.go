@@ -1,42 +1,17 @@
 package pairec
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alibaba/pairec/v2/abtest"
-	"github.com/alibaba/pairec/v2/algorithm"
 	"github.com/alibaba/pairec/v2/config"
 	"github.com/alibaba/pairec/v2/config/pairec_config"
-	"github.com/alibaba/pairec/v2/datasource/beengine"
-	"github.com/alibaba/pairec/v2/datasource/datahub"
-	"github.com/alibaba/pairec/v2/datasource/graph"
-	"github.com/alibaba/pairec/v2/datasource/ha3engine"
-	"github.com/alibaba/pairec/v2/datasource/hbase"
-	"github.com/alibaba/pairec/v2/datasource/hbase_thrift"
-	"github.com/alibaba/pairec/v2/datasource/kafka"
-	"github.com/alibaba/pairec/v2/datasource/opensearch"
-	"github.com/alibaba/pairec/v2/datasource/sls"
-	"github.com/alibaba/pairec/v2/filter"
 	"github.com/alibaba/pairec/v2/log"
-	"github.com/alibaba/pairec/v2/persist/clickhouse"
-	"github.com/alibaba/pairec/v2/persist/fs"
-	"github.com/alibaba/pairec/v2/persist/holo"
-	"github.com/alibaba/pairec/v2/persist/lindorm"
-	"github.com/alibaba/pairec/v2/persist/mysqldb"
-	"github.com/alibaba/pairec/v2/persist/redisdb"
-	"github.com/alibaba/pairec/v2/persist/tablestoredb"
 	"github.com/alibaba/pairec/v2/recconf"
-	"github.com/alibaba/pairec/v2/service"
-	"github.com/alibaba/pairec/v2/service/fallback"
-	"github.com/alibaba/pairec/v2/service/feature"
-	"github.com/alibaba/pairec/v2/service/general_rank"
-	"github.com/alibaba/pairec/v2/service/pipeline"
-	"github.com/alibaba/pairec/v2/service/recall/berecall"
-	"github.com/alibaba/pairec/v2/sort"
 )
 
 var (
@@ -70,51 +45,40 @@ func (l *ConfigLoader) loopLoadConfig() {
 
 			l.configVersionValue = version
 			log.Info(fmt.Sprintf("config version changed, version:%s, reload config", l.configVersionValue))
-			func() {
-				defer func() {
-					if err := recover(); err != nil {
-						log.Error(fmt.Sprintf("reload config error, error:%v", err))
-					}
-				}()
-
-				l.reloadConfig(config)
-			}()
-			recconf.UpdateConf(config)
+			if err := l.reloadConfig(config); err != nil {
+				log.Error(fmt.Sprintf("reload config error, keep the running config, version:%s, error:%v", version, err))
+				continue
+			}
+			recconf.UpdateConfWithSource(config, "config_server:"+version)
 		}
 	}
 }
 
-func (l *ConfigLoader) reloadConfig(config *recconf.RecommendConfig) {
-
-	mysqldb.Load(config)
-	redisdb.Load(config)
-	tablestoredb.Load(config)
-	sls.Load(config)
-	kafka.Load(config)
-	datahub.Load(config)
-	beengine.Load(config)
-	graph.Load(config)
-	ha3engine.Load(recconf.Config)
-	opensearch.Load(recconf.Config)
-	hbase.Load(config)
-	holo.Load(config)
-	lindorm.Load(config)
-	hbase_thrift.Load(config)
-	fs.Load(config)
-	clickhouse.Load(config)
-	algorithm.Load(config) // holo must be loaded before loading some algorithm
-	register(config)
-
-	filter.RegisterFilterWithConfig(config)
-	filter.Load(config)
-	berecall.RegisterFilterWithConfig(config)
-	sort.Load(config)
-	service.Load(config)
-	feature.UserLoadFeatureConfig(config)
-	feature.LoadFeatureConfig(config)
-	general_rank.LoadGeneralRankWithConfig(config)
-	pipeline.LoadPipelineConfigs(config)
-	fallback.LoadFallbackConfig(config)
+func (l *ConfigLoader) reloadConfig(config *recconf.RecommendConfig) error {
+	return reloadConfig(config)
+}
+
+// reloadMu serializes the reloads of the config loaders and the rollback
+var reloadMu sync.Mutex
+
+// reloadConfig stages the new datasources and rebuilds the changed modules of the config, the config is rejected atomically
+// with the errors of all the modules when any module fails to construct, and the modules in use are not changed
+func reloadConfig(config *recconf.RecommendConfig) (err error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("reload config error, error:%v", e)
+		}
+	}()
+
+	if err := applyConfig(config); err != nil {
+		logConfigError("reloadConfig", err)
+		return err
+	}
+
+	return nil
 }
 
 func (l *ConfigLoader) loadConfigFromConfigServer() (*recconf.RecommendConfig, error) {
@@ -130,7 +94,7 @@ func (l *ConfigLoader) loadConfigFromConfigServer() (*recconf.RecommendConfig, e
 	data = strings.ReplaceAll(data, "${AccessSecret}", accessSecret)
 
 	configD := &recconf.RecommendConfig{}
-	err = json.Unmarshal([]byte(data), configD)
+	err = recconf.UnmarshalConfig([]byte(data), configD)
 	if err != nil {
 		return nil, err
 	}
@@ -158,10 +122,10 @@ func ListenConfig(configName string) {
 		panic(err)
 	}
 
-	recconf.UpdateConf(config)
-
 	version := loader.loadConfigVersion()
 
+	recconf.UpdateConfWithSource(config, "config_server:"+version)
+
 	loader.configVersionValue = version
 
 	go loader.loopLoadConfig()
@@ -1,10 +1,12 @@
 package context
 
 import (
+	gocontext "context"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
@@ -26,6 +28,12 @@ type RecommendContext struct {
 	Log              []string
 	mu               sync.RWMutex
 	contexParams     map[string]interface{}
+
+	// ctx carries the deadline of the whole recommend request
+	ctx    gocontext.Context
+	cancel gocontext.CancelFunc
+	// parent is the context of the incoming request, its cancellation is propagated to ctx
+	parent gocontext.Context
 }
 
 func NewRecommendContext() *RecommendContext {
@@ -36,6 +44,91 @@ func (r *RecommendContext) GetParameter(name string) interface{} {
 	return r.Param.GetParameter(name)
 }
 
+// Context returns the go context of the request, it is never nil.
+// DAOs and algorithm clients should derive their own context from it, so the request-level deadline is respected.
+func (r *RecommendContext) Context() gocontext.Context {
+	if r.ctx == nil {
+		return gocontext.Background()
+	}
+	return r.ctx
+}
+
+// SetContext sets the context of the incoming request, such as the http request context,
+// so the client cancellation is propagated. It should be called before SetTimeout.
+func (r *RecommendContext) SetContext(ctx gocontext.Context) {
+	if ctx == nil {
+		return
+	}
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.parent = ctx
+	r.ctx = ctx
+}
+
+// SetTimeout sets the request-level budget, the deadline is counted from now.
+// A timeout <= 0 means no deadline other than the one of the incoming request.
+func (r *RecommendContext) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	parent := r.parent
+	if parent == nil {
+		parent = gocontext.Background()
+	}
+	r.ctx, r.cancel = gocontext.WithTimeout(parent, timeout)
+}
+
+// WithTimeout returns a child context of the request context, its deadline is the earlier one of
+// the request deadline and the timeout.
+func (r *RecommendContext) WithTimeout(timeout time.Duration) (gocontext.Context, gocontext.CancelFunc) {
+	return gocontext.WithTimeout(r.Context(), timeout)
+}
+
+// Deadline returns the deadline of the request, ok is false when no deadline is set.
+func (r *RecommendContext) Deadline() (deadline time.Time, ok bool) {
+	return r.Context().Deadline()
+}
+
+// Err returns non nil error when the request deadline is exceeded or the request is canceled.
+func (r *RecommendContext) Err() error {
+	return r.Context().Err()
+}
+
+// NewFallbackContext returns a copy of the request context for the fallback, its go context is bounded by the timeout
+// and is not done with the request, so the fallback still serves the request whose deadline is exceeded.
+// Cancel of the copy should be called when the fallback is finished.
+func (r *RecommendContext) NewFallbackContext(timeout time.Duration) *RecommendContext {
+	fallbackContext := NewRecommendContext()
+	fallbackContext.Debug = r.Debug
+	fallbackContext.Size = r.Size
+	fallbackContext.Param = r.Param
+	fallbackContext.Config = r.Config
+	fallbackContext.ExperimentResult = r.ExperimentResult
+	fallbackContext.RecommendId = r.RecommendId
+	fallbackContext.ExpId = r.ExpId
+
+	r.mu.RLock()
+	for name, value := range r.contexParams {
+		fallbackContext.contexParams[name] = value
+	}
+	r.mu.RUnlock()
+
+	fallbackContext.SetTimeout(timeout)
+	return fallbackContext
+}
+
+// Cancel releases the resources of the request context, it should be called when the request is finished.
+func (r *RecommendContext) Cancel() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
 func (r *RecommendContext) GetParameterByPath(path string) interface{} {
 	if strings.Contains(path, ".") {
 		pos := strings.Index(path, ".")
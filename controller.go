@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	"github.com/alibaba/pairec/v2/log"
 )
@@ -14,7 +15,10 @@ type ControllerInterface interface {
 	Process(http.ResponseWriter, *http.Request)
 }
 type ControllerRegister struct {
-	routeInfos map[string]*RouteInfo
+	// the server is started before the start hooks finish, so the routes may be registered while serving
+	mu                 sync.RWMutex
+	routeInfos         map[string]*RouteInfo
+	middlewaresApplied bool
 
 	Middlewares []MiddlewareFunc
 }
@@ -43,7 +47,11 @@ func (c *ControllerRegister) ServeHTTP(rw http.ResponseWriter, req *http.Request
 		uri = req.RequestURI[:index]
 	}
 
-	if info, exist := c.routeInfos[uri]; exist {
+	c.mu.RLock()
+	info, exist := c.routeInfos[uri]
+	c.mu.RUnlock()
+
+	if exist {
 
 		if info.initialize != nil {
 			c := info.initialize()
@@ -64,10 +72,18 @@ func (c *ControllerRegister) ServeHTTP(rw http.ResponseWriter, req *http.Request
 }
 
 func (c *ControllerRegister) Register(routeInfo *RouteInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the route registered after the server started also uses the middlewares
+	if c.middlewaresApplied {
+		routeInfo = applyMiddleware(routeInfo, c.Middlewares...)
+	}
 	c.routeInfos[routeInfo.pattern] = routeInfo
 }
 
 func (c *ControllerRegister) GetRoutePath() (paths []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	for p := range c.routeInfos {
 		paths = append(paths, p)
 	}
@@ -75,9 +91,12 @@ func (c *ControllerRegister) GetRoutePath() (paths []string) {
 }
 
 func (c *ControllerRegister) ApplyMiddlewares() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for p, r := range c.routeInfos {
 		c.routeInfos[p] = applyMiddleware(r, c.Middlewares...)
 	}
+	c.middlewaresApplied = true
 }
 
 func Error(rw http.ResponseWriter, code int, msg string) {
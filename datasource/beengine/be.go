@@ -2,9 +2,9 @@ package beengine
 
 import (
 	"fmt"
-	"sync"
 
 	be "github.com/aliyun/aliyun-be-go-sdk"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -13,25 +13,19 @@ type BeClient struct {
 	productReleased bool
 }
 
-var (
-	mu          sync.RWMutex
-	beInstances = make(map[string]*BeClient)
-)
+var beInstances = registry.New[*BeClient]()
 
 func GetBeClient(name string) (*BeClient, error) {
-	mu.RLock()
-	defer mu.RUnlock()
-	if _, ok := beInstances[name]; !ok {
+	client, ok := beInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("BeClient not found, name:%s", name)
 	}
 
-	return beInstances[name], nil
+	return client, nil
 }
 func RegisterBeClient(name string, client *BeClient) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := beInstances[name]; !ok {
-		beInstances[name] = client
+	if !beInstances.Has(name) {
+		beInstances.Set(name, client)
 	}
 }
 
@@ -55,7 +49,7 @@ func (d *BeClient) IsProductReleased() bool {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.BEConfs {
-		if _, ok := beInstances[name]; ok {
+		if beInstances.Has(name) {
 			continue
 		}
 		m := NewBeClient(conf.Username, conf.Password, conf.Endpoint)
@@ -67,6 +61,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		RegisterBeClient(name, m)
+		beInstances.Stage(name, m)
 	}
 }
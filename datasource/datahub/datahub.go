@@ -3,11 +3,11 @@ package datahub
 import (
 	"encoding/base64"
 	"fmt"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/module"
 	"github.com/alibaba/pairec/v2/recconf"
@@ -33,35 +33,27 @@ type Datahub struct {
 	syncLog *synclog.SyncLog
 }
 
-var (
-	mu               sync.RWMutex
-	datahubInstances = make(map[string]*Datahub)
-)
+var datahubInstances = registry.New[*Datahub]()
 
 func GetDatahub(name string) (*Datahub, error) {
-	mu.RLock()
-	defer mu.RUnlock()
-	if _, ok := datahubInstances[name]; !ok {
+	dh, ok := datahubInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("Datahub not found, name:%s", name)
 	}
 
-	return datahubInstances[name], nil
+	return dh, nil
 }
 func RegisterDatahub(name string, dh *Datahub) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := datahubInstances[name]; !ok {
-		datahubInstances[name] = dh
+	if !datahubInstances.Has(name) {
 		dh.name = name
+		datahubInstances.Set(name, dh)
 	}
 }
 
 func RemoveDatahub(name string) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := datahubInstances[name]; ok {
-		datahubInstances[name].StopLoopListShards()
-		delete(datahubInstances, name)
+	if dh, ok := datahubInstances.Get(name); ok {
+		dh.StopLoopListShards()
+		datahubInstances.Delete(name)
 	}
 }
 
@@ -330,9 +322,21 @@ func (d *Datahub) doSendSingleMessage(message map[string]interface{}) error {
 	return nil
 }
 
+// Close stops all the datahubs and closes the sync logs of the messages failed to put
+func Close() {
+	datahubInstances.Range(func(_ string, d *Datahub) {
+		d.StopLoopListShards()
+		if d.syncLog != nil {
+			if err := d.syncLog.Close(); err != nil {
+				log.Error(fmt.Sprintf("project=%s\ttopic=%s\terror=close sync log error(%v)", d.projectName, d.topicName, err))
+			}
+		}
+	})
+}
+
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.DatahubConfs {
-		if _, ok := datahubInstances[name]; ok {
+		if datahubInstances.Has(name) {
 			continue
 		}
 		m := NewDatahub(conf.AccessId, conf.AccessKey, conf.Endpoint, conf.ProjectName, conf.TopicName, conf.Schemas)
@@ -341,7 +345,7 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		datahubInstances[name] = m
+		datahubInstances.Stage(name, m)
 	}
 }
 
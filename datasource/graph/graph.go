@@ -3,33 +3,27 @@ package graph
 import (
 	"fmt"
 	igraph "github.com/aliyun/aliyun-igraph-go-sdk"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
-	"sync"
 )
 
 type GraphClient struct {
 	GraphClient *igraph.Client
 }
 
-var (
-	mu             sync.RWMutex
-	graphInstances = make(map[string]*GraphClient)
-)
+var graphInstances = registry.New[*GraphClient]()
 
 func GetGraphClient(name string) (*GraphClient, error) {
-	mu.RLock()
-	defer mu.RUnlock()
-	if _, ok := graphInstances[name]; !ok {
+	client, ok := graphInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("GraphClient not found, name:%s", name)
 	}
 
-	return graphInstances[name], nil
+	return client, nil
 }
 func RegisterGraphClient(name string, client *GraphClient) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := graphInstances[name]; !ok {
-		graphInstances[name] = client
+	if !graphInstances.Has(name) {
+		graphInstances.Set(name, client)
 	}
 }
 
@@ -46,7 +40,7 @@ func (d *GraphClient) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.GraphConfs {
-		if _, ok := graphInstances[name]; ok {
+		if graphInstances.Has(name) {
 			continue
 		}
 		m := NewGraphClient(conf.Host, conf.UserName, conf.Passwd)
@@ -55,6 +49,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		RegisterGraphClient(name, m)
+		graphInstances.Stage(name, m)
 	}
 }
@@ -2,11 +2,11 @@ package ha3engine
 
 import (
 	"fmt"
-	"sync"
 
 	util "github.com/alibabacloud-go/tea-utils/service"
 	"github.com/alibabacloud-go/tea/tea"
 	"github.com/alibaba/pairec/v2/datasource/ha3engine/ha3client"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -15,25 +15,19 @@ type Ha3EngineClient struct {
 	runtime   *util.RuntimeOptions
 }
 
-var (
-	mu           sync.RWMutex
-	ha3Instances = make(map[string]*Ha3EngineClient)
-)
+var ha3Instances = registry.New[*Ha3EngineClient]()
 
 func GetHa3EngineClient(name string) (*Ha3EngineClient, error) {
-	mu.RLock()
-	defer mu.RUnlock()
-	if _, ok := ha3Instances[name]; !ok {
+	client, ok := ha3Instances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("ha3EngineClient not found, name:%s", name)
 	}
 
-	return ha3Instances[name], nil
+	return client, nil
 }
 func RegisterHa3EngineClient(name string, client *Ha3EngineClient) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := ha3Instances[name]; !ok {
-		ha3Instances[name] = client
+	if !ha3Instances.Has(name) {
+		ha3Instances.Set(name, client)
 	}
 }
 
@@ -69,7 +63,7 @@ func (d *Ha3EngineClient) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.Ha3EngineConfs {
-		if _, ok := ha3Instances[name]; ok {
+		if ha3Instances.Has(name) {
 			continue
 		}
 		m := NewHa3EngineClient(conf.Username, conf.Password, conf.Endpoint, conf.InstanceId)
@@ -78,6 +72,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		RegisterHa3EngineClient(name, m)
+		ha3Instances.Stage(name, m)
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/tsuna/gohbase"
 	"github.com/tsuna/gohbase/hrpc"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -15,14 +16,15 @@ type HBase struct {
 	Timeout  int
 }
 
-var hbaseInstances = make(map[string]*HBase)
+var hbaseInstances = registry.New[*HBase]()
 
 func GetHBase(name string) (*HBase, error) {
-	if _, ok := hbaseInstances[name]; !ok {
+	h, ok := hbaseInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("Hbase not found, name:%s", name)
 	}
 
-	return hbaseInstances[name], nil
+	return h, nil
 }
 func NewHBase(zkquorum string, timeout int) *HBase {
 	h := &HBase{
@@ -70,7 +72,7 @@ func (h *HBase) Get(table, key, columnFamily, qualifier string) (*hrpc.Result, e
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.HBaseConfs {
-		if _, ok := hbaseInstances[name]; ok {
+		if hbaseInstances.Has(name) {
 			continue
 		}
 		d := NewHBase(conf.ZKQuorum, 100)
@@ -78,6 +80,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		hbaseInstances[name] = d
+		hbaseInstances.Stage(name, d)
 	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/alibaba/pairec/v2/datasource/hbase_thrift/gen-go/hbase"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -24,10 +25,10 @@ type HBaseThriftPool struct {
 	client  *HBaseThrift
 }
 
-var hbaseThriftInstances = make(map[string]*HBaseThriftPool)
+var hbaseThriftInstances = registry.New[*HBaseThriftPool]()
 
 func GetHBaseThrift(name string) (*HBaseThrift, error) {
-	pool, ok := hbaseThriftInstances[name]
+	pool, ok := hbaseThriftInstances.Get(name)
 	if !ok {
 		return nil, fmt.Errorf("hbase not found, name:%s", name)
 	}
@@ -51,7 +52,7 @@ func GetHBaseThrift(name string) (*HBaseThrift, error) {
 	return client, err
 }
 func PutHBaseThrift(name string, client *HBaseThrift) {
-	pool, ok := hbaseThriftInstances[name]
+	pool, ok := hbaseThriftInstances.Get(name)
 	if !ok {
 		return
 	}
@@ -93,7 +94,7 @@ func (h *HBaseThrift) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.HBaseThriftConfs {
-		if _, ok := hbaseThriftInstances[name]; ok {
+		if hbaseThriftInstances.Has(name) {
 			continue
 		}
 		pool := &HBaseThriftPool{client: &HBaseThrift{
@@ -113,6 +114,6 @@ func Load(config *recconf.RecommendConfig) {
 			pool.clients = append(pool.clients, client)
 
 		}
-		hbaseThriftInstances[name] = pool
+		hbaseThriftInstances.Stage(name, pool)
 	}
 }
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 	kafka "github.com/segmentio/kafka-go"
@@ -24,14 +25,15 @@ type KafkaProducer struct {
 	Producer         *kafka.Writer
 }
 
-var kafkaProducerInstances = make(map[string]*KafkaProducer)
+var kafkaProducerInstances = registry.New[*KafkaProducer]()
 
 func GetKafkaProducer(name string) (*KafkaProducer, error) {
-	if _, ok := kafkaProducerInstances[name]; !ok {
+	producer, ok := kafkaProducerInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("KafkaProducer not found, name:%s", name)
 	}
 
-	return kafkaProducerInstances[name], nil
+	return producer, nil
 }
 
 func NewKafkaProducer(bootstrapServers, topic string) *KafkaProducer {
@@ -88,9 +90,16 @@ func (k *KafkaProducer) Close() {
 	}
 }
 
+// Close closes all the producers, the messages written asynchronously are flushed
+func Close() {
+	kafkaProducerInstances.Range(func(_ string, producer *KafkaProducer) {
+		producer.Close()
+	})
+}
+
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.KafkaConfs {
-		if _, ok := kafkaProducerInstances[name]; ok {
+		if kafkaProducerInstances.Has(name) {
 			continue
 		}
 		m := &KafkaProducer{
@@ -101,6 +110,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		kafkaProducerInstances[name] = m
+		kafkaProducerInstances.Stage(name, m)
 	}
 }
@@ -2,9 +2,9 @@ package opensearch
 
 import (
 	"fmt"
-	"sync"
 
 	openSearchClient "github.com/alibaba/pairec/v2/datasource/opensearch/client"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 	util "github.com/alibabacloud-go/tea-utils/service"
 	"github.com/alibabacloud-go/tea/tea"
@@ -15,25 +15,19 @@ type OpenSearchClient struct {
 	Runtime          *util.RuntimeOptions
 }
 
-var (
-	mu                  sync.RWMutex
-	opensearchInstances = make(map[string]*OpenSearchClient)
-)
+var opensearchInstances = registry.New[*OpenSearchClient]()
 
 func GetOpenSearchClient(name string) (*OpenSearchClient, error) {
-	mu.RLock()
-	defer mu.RUnlock()
-	if _, ok := opensearchInstances[name]; !ok {
+	client, ok := opensearchInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("opensearchClient not found, name:%s", name)
 	}
 
-	return opensearchInstances[name], nil
+	return client, nil
 }
 func RegisterOpenSearchClient(name string, client *OpenSearchClient) {
-	mu.Lock()
-	defer mu.Unlock()
-	if _, ok := opensearchInstances[name]; !ok {
-		opensearchInstances[name] = client
+	if !opensearchInstances.Has(name) {
+		opensearchInstances.Set(name, client)
 	}
 }
 
@@ -67,7 +61,7 @@ func (d *OpenSearchClient) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.OpenSearchConfs {
-		if _, ok := opensearchInstances[name]; ok {
+		if opensearchInstances.Has(name) {
 			continue
 		}
 		m := NewOpenSearchClient(conf.EndPoint, conf.AccessKeyId, conf.AccessKeySecret)
@@ -76,6 +70,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		RegisterOpenSearchClient(name, m)
+		opensearchInstances.Stage(name, m)
 	}
 }
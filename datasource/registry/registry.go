@@ -0,0 +1,156 @@
+package registry
+
+import (
+	"io"
+	"sync"
+)
+
+// transaction is the staged part of a registry, the staged instances are committed or discarded together
+type transaction interface {
+	commit()
+	discard()
+}
+
+var (
+	mu         sync.Mutex
+	registries []transaction
+	onCommits  []func()
+)
+
+// Registry keeps the instances of a datasource by name.
+// The instances built while a config is applied are staged, so the modules built from the same config can get them,
+// they are added by Commit when the config is accepted, and closed by Discard when the config is rejected.
+type Registry[T any] struct {
+	mu        sync.RWMutex
+	instances map[string]T
+	staged    map[string]T
+}
+
+// New returns an empty registry, it is committed and discarded with the other registries
+func New[T any]() *Registry[T] {
+	r := &Registry[T]{
+		instances: make(map[string]T),
+		staged:    make(map[string]T),
+	}
+
+	mu.Lock()
+	registries = append(registries, r)
+	mu.Unlock()
+
+	return r
+}
+
+// Get returns the instance of the name, the staged instance is returned first
+func (r *Registry[T]) Get(name string) (T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if v, ok := r.staged[name]; ok {
+		return v, true
+	}
+	v, ok := r.instances[name]
+	return v, ok
+}
+
+// Has returns true when the name is added or staged
+func (r *Registry[T]) Has(name string) bool {
+	_, ok := r.Get(name)
+	return ok
+}
+
+// Stage keeps the instance until Commit or Discard
+func (r *Registry[T]) Stage(name string, v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.staged[name] = v
+}
+
+// Set adds the instance directly, it is used by the instances registered by code
+func (r *Registry[T]) Set(name string, v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.instances[name] = v
+}
+
+// Delete removes the instance added
+func (r *Registry[T]) Delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.instances, name)
+}
+
+// Range calls f for each instance added, the staged instances are not included
+func (r *Registry[T]) Range(f func(name string, v T)) {
+	r.mu.RLock()
+	instances := make(map[string]T, len(r.instances))
+	for name, v := range r.instances {
+		instances[name] = v
+	}
+	r.mu.RUnlock()
+
+	for name, v := range instances {
+		f(name, v)
+	}
+}
+
+func (r *Registry[T]) commit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, v := range r.staged {
+		r.instances[name] = v
+	}
+	r.staged = make(map[string]T)
+}
+
+func (r *Registry[T]) discard() {
+	r.mu.Lock()
+	staged := r.staged
+	r.staged = make(map[string]T)
+	r.mu.Unlock()
+
+	for _, v := range staged {
+		switch closer := any(v).(type) {
+		case io.Closer:
+			closer.Close()
+		case interface{ Close() }:
+			closer.Close()
+		}
+	}
+}
+
+// OnCommit stages the change of the instances in use, it runs by Commit and is dropped by Discard
+func OnCommit(f func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	onCommits = append(onCommits, f)
+}
+
+// Commit adds the staged instances of all the registries and runs the staged changes
+func Commit() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, r := range registries {
+		r.commit()
+	}
+	for _, f := range onCommits {
+		f()
+	}
+	onCommits = nil
+}
+
+// Discard closes the staged instances of all the registries and drops the staged changes
+func Discard() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, r := range registries {
+		r.discard()
+	}
+	onCommits = nil
+}
@@ -0,0 +1,62 @@
+package registry
+
+import "testing"
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() {
+	c.closed = true
+}
+
+func TestCommit(t *testing.T) {
+	r := New[*closer]()
+	committed := false
+	r.Stage("a", &closer{})
+	OnCommit(func() { committed = true })
+
+	if !r.Has("a") {
+		t.Error("expect the staged instance is got before commit")
+	}
+	count := 0
+	r.Range(func(string, *closer) { count++ })
+	if count != 0 {
+		t.Errorf("expect no instance ranged before commit, got %d", count)
+	}
+
+	Commit()
+	if !committed {
+		t.Error("expect the staged change runs by commit")
+	}
+	if c, ok := r.Get("a"); !ok || c.closed {
+		t.Error("expect the instance is added by commit")
+	}
+
+	// it does nothing after commit
+	Discard()
+	if c, ok := r.Get("a"); !ok || c.closed {
+		t.Error("expect the committed instance is not closed by discard")
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	r := New[*closer]()
+	c := &closer{}
+	committed := false
+	r.Stage("a", c)
+	OnCommit(func() { committed = true })
+
+	Discard()
+	if r.Has("a") {
+		t.Error("expect the staged instance is dropped by discard")
+	}
+	if !c.closed {
+		t.Error("expect the staged instance is closed by discard")
+	}
+
+	Commit()
+	if committed {
+		t.Error("expect the staged change is dropped by discard")
+	}
+}
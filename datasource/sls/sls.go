@@ -6,6 +6,7 @@ import (
 
 	alisls "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/aliyun/aliyun-log-go-sdk/producer"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/utils"
 )
@@ -24,11 +25,11 @@ type SlsClient struct {
 	callback *Callback
 }
 
-var slsclientInstances = make(map[string]*SlsClient)
+var slsclientInstances = registry.New[*SlsClient]()
 
 func GetSlsClient(name string) (*SlsClient, error) {
 
-	client, ok := slsclientInstances[name]
+	client, ok := slsclientInstances.Get(name)
 	if !ok {
 		return nil, fmt.Errorf("slsclient not found, name:%s", name)
 	}
@@ -71,14 +72,28 @@ func (s *SlsClient) SendLog(data map[string]string) {
 		fmt.Println(err)
 	}
 }
+// Close closes the producer after the logs are sent
+func (s *SlsClient) Close() {
+	if s.Producer != nil {
+		s.Producer.SafeClose()
+	}
+}
+
+// Close closes all the sls clients
+func Close() {
+	slsclientInstances.Range(func(_ string, client *SlsClient) {
+		client.Close()
+	})
+}
+
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.SlsConfs {
-		if _, ok := slsclientInstances[name]; ok {
+		if slsclientInstances.Has(name) {
 			continue
 		}
 		s := NewSlsClient(conf.AccessKeyId, conf.AccessKeySecret, conf.EndPoint, conf.ProjectName, conf.LogstoreName)
 		s.Init()
-		slsclientInstances[name] = s
+		slsclientInstances.Stage(name, s)
 	}
 }
 
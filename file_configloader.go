@@ -0,0 +1,103 @@
+package pairec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+var fileLoader *FileConfigLoader
+
+// FileConfigLoader watches the local config file, when the content changes the new config is checked,
+// the changed modules are rebuilt and the config is published. The running config stays in place if the new config is invalid.
+type FileConfigLoader struct {
+	filePath string
+	interval time.Duration
+	// sign is the md5 of the file content loaded last time
+	sign string
+}
+
+func NewFileConfigLoader(filePath string, data []byte) *FileConfigLoader {
+	l := &FileConfigLoader{
+		filePath: filePath,
+		interval: time.Second * 10,
+		sign:     utils.Md5(string(data)),
+	}
+
+	return l
+}
+
+func (l *FileConfigLoader) loopLoadConfig() {
+	for {
+		time.Sleep(l.interval)
+		if err := l.loadConfig(); err != nil {
+			log.Error(fmt.Sprintf("event=FileConfigLoader\tfile=%s\terror=%v", l.filePath, err))
+		}
+	}
+}
+
+// loadConfig reloads the config if the content of the file changed
+func (l *FileConfigLoader) loadConfig() (err error) {
+	data, err := recconf.ReadConfigFile(l.filePath)
+	if err != nil {
+		return err
+	}
+
+	sign := utils.Md5(string(data))
+	if sign == l.sign {
+		return nil
+	}
+	// the same content is not reloaded again even if it is invalid, until the file changes
+	l.sign = sign
+
+	config, err := checkConfigData(data)
+	if err != nil {
+		return fmt.Errorf("config is invalid, keep the running config, %w", err)
+	}
+
+	log.Info(fmt.Sprintf("event=FileConfigLoader\tfile=%s\tsign=%s\tmsg=config file changed, reload config", l.filePath, sign))
+	if err := reloadConfig(config); err != nil {
+		return fmt.Errorf("reload config error, keep the running config, %w", err)
+	}
+	recconf.UpdateConfWithSource(config, "file:"+l.filePath)
+
+	return nil
+}
+
+// checkConfigData checks the config data with recconf.CheckRecommendConfig and parses the config
+func checkConfigData(data []byte) (*recconf.RecommendConfig, error) {
+	results, err := recconf.CheckRecommendConfig(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for index, result := range results {
+		if len(result.Issues) > 0 {
+			errs = append(errs, fmt.Errorf("module=%s\tname=%s\tissues=%s", index.Type, index.Name, strings.Join(result.Issues, ";")))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return recconf.ParseConfig(data)
+}
+
+// WatchConfigFile starts the FileConfigLoader of the local config file
+func WatchConfigFile(filePath string) error {
+	data, err := recconf.ReadConfigFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	fileLoader = NewFileConfigLoader(filePath, data)
+	go fileLoader.loopLoadConfig()
+
+	return nil
+}
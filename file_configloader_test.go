@@ -0,0 +1,50 @@
+package pairec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func TestCheckConfigData(t *testing.T) {
+	invalid := `{"RecallConfs":[{"Name":"redis_recall","DaoConf":{"AdapterType":"redis","RedisName":"not_exist"}}]}`
+	if _, err := checkConfigData([]byte(invalid)); err == nil {
+		t.Error("expect the error of the undefined redis")
+	}
+
+	config, err := checkConfigData([]byte(`{"RunMode":"product"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RunMode != "product" || config.ListenConf.HttpPort != 8000 {
+		t.Errorf("expect the config parsed with the default values, got %v", config.ListenConf)
+	}
+}
+
+func TestFileConfigLoaderKeepRunningConfig(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	data := []byte(`{"RunMode":"product"}`)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runningConfig := recconf.Config
+	loader := NewFileConfigLoader(filePath, data)
+	if err := loader.loadConfig(); err != nil || recconf.Config != runningConfig {
+		t.Errorf("expect the unchanged file not reloaded, error:%v", err)
+	}
+
+	invalid := []byte(`{"RecallConfs":[{"Name":"redis_recall","DaoConf":{"AdapterType":"redis","RedisName":"not_exist"}}]}`)
+	if err := os.WriteFile(filePath, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.loadConfig(); err == nil || recconf.Config != runningConfig {
+		t.Errorf("expect the invalid config rejected and the running config kept, error:%v", err)
+	}
+	// the invalid content is not checked again until the file changes
+	if err := loader.loadConfig(); err != nil {
+		t.Errorf("expect the same content skipped, error:%v", err)
+	}
+}
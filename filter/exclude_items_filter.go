@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alibaba/pairec/v2/module"
+)
+
+// ExcludeItemsFilter removes the items of the request param exclude_items.
+// It is a built-in filter, applied before the configured filters of the scene and the pipeline.
+type ExcludeItemsFilter struct {
+}
+
+func NewExcludeItemsFilter() *ExcludeItemsFilter {
+	return &ExcludeItemsFilter{}
+}
+
+func (f *ExcludeItemsFilter) Filter(filterData *FilterData) error {
+	if _, ok := filterData.Data.([]*module.Item); !ok {
+		return errors.New("filter data type error")
+	}
+
+	return f.doFilter(filterData)
+}
+
+func (f *ExcludeItemsFilter) doFilter(filterData *FilterData) error {
+	excludeItems, _ := filterData.Context.GetParameter("exclude_items").([]string)
+	if len(excludeItems) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	excludeMap := make(map[module.ItemId]bool, len(excludeItems))
+	for _, id := range excludeItems {
+		excludeMap[module.ItemId(id)] = true
+	}
+
+	items := filterData.Data.([]*module.Item)
+	newItems := make([]*module.Item, 0, len(items))
+	for _, item := range items {
+		if !excludeMap[item.Id] {
+			newItems = append(newItems, item)
+		}
+	}
+
+	filterData.Data = newItems
+	filterInfoLog(filterData, "ExcludeItemsFilter", "", len(newItems), start)
+	return nil
+}
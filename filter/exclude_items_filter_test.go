@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+)
+
+type testParam map[string]interface{}
+
+func (p testParam) GetParameter(name string) interface{} {
+	return p[name]
+}
+
+func TestExcludeItemsFilter(t *testing.T) {
+	var items []*module.Item
+	for _, id := range []string{"1", "2", "3", "4"} {
+		items = append(items, module.NewItem(id))
+	}
+
+	ctx := context.NewRecommendContext()
+	ctx.Param = testParam{"exclude_items": []string{"2", "4", "5"}}
+	filterData := &FilterData{Data: items, Context: ctx}
+	if err := NewExcludeItemsFilter().Filter(filterData); err != nil {
+		t.Fatal(err)
+	}
+
+	result := filterData.Data.([]*module.Item)
+	if len(result) != 2 || result[0].Id != "1" || result[1].Id != "3" {
+		t.Errorf("expect items 1 and 3, got %v", result)
+	}
+
+	ctx.Param = testParam{}
+	filterData = &FilterData{Data: items, Context: ctx}
+	NewExcludeItemsFilter().Filter(filterData)
+	if len(filterData.Data.([]*module.Item)) != 4 {
+		t.Error("expect no item removed without exclude_items")
+	}
+}
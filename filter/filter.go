@@ -2,6 +2,7 @@ package filter
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,9 +18,40 @@ var filterSigns = make(map[string]string)
 
 var filterService *FilterService
 
+// FilterFactory creates the filter of the FilterType from the config
+type FilterFactory func(config recconf.FilterConfig) IFilter
+
+// filterFactories are the factories of the filter types used by RegisterFilterWithConfig
+var filterFactories = make(map[string]FilterFactory)
+
+// RegisterFilterFactory registers the factory of the filter type, so the custom filter can be configured by FilterType like the built-in ones,
+// the factory of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterFilterFactory(filterType string, factory FilterFactory) {
+	if factory == nil {
+		panic("FilterFactory is nil, type:" + filterType)
+	}
+
+	filterFactories[filterType] = factory
+	recconf.RegisterSchemaEnum("FilterConfig.FilterType", filterType)
+}
+
 func init() {
 	filterService = &FilterService{}
 	filterService.Filters = make(map[string][]IFilter)
+
+	RegisterFilterFactory("User2ItemExposureFilter", func(config recconf.FilterConfig) IFilter { return NewUser2ItemExposureFilter(config) })
+	RegisterFilterFactory("User2ItemCustomFilter", func(config recconf.FilterConfig) IFilter { return NewUser2ItemCustomFilter(config) })
+	RegisterFilterFactory("AdjustCountFilter", func(config recconf.FilterConfig) IFilter { return NewAdjustCountFilter(config) })
+	RegisterFilterFactory("PriorityAdjustCountFilter", func(config recconf.FilterConfig) IFilter { return NewPriorityAdjustCountFilter(config) })
+	RegisterFilterFactory("PriorityAdjustCountFilterV2", func(config recconf.FilterConfig) IFilter { return NewPriorityAdjustCountFilterV2(config) })
+	RegisterFilterFactory("ItemStateFilter", func(config recconf.FilterConfig) IFilter { return NewItemStateFilter(config) })
+	RegisterFilterFactory("ItemCustomFilter", func(config recconf.FilterConfig) IFilter { return NewItemCustomFilter(config) })
+	RegisterFilterFactory("CompletelyFairFilter", func(config recconf.FilterConfig) IFilter { return NewCompletelyFairCountFilter(config) })
+	RegisterFilterFactory("GroupWeightCountFilter", func(config recconf.FilterConfig) IFilter { return NewGroupWeightCountFilter(config) })
+	RegisterFilterFactory("DimensionFieldUniqueFilter", func(config recconf.FilterConfig) IFilter { return NewDimensionFieldUniqueFilter(config) })
+	RegisterFilterFactory("User2ItemExposureWithConditionFilter", func(config recconf.FilterConfig) IFilter { return NewUser2ItemExposureWithConditionFilter(config) })
+	RegisterFilterFactory("ConditionFilter", func(config recconf.FilterConfig) IFilter { return NewConditionFilter(config) })
+	RegisterFilterFactory("DiversityAdjustCountFilter", func(config recconf.FilterConfig) IFilter { return NewDiversityAdjustCountFilter(config) })
 }
 
 type FilterData struct {
@@ -123,52 +155,54 @@ func (fs *FilterService) Filter(filterData *FilterData, tag string) {
 	}
 }
 
-func RegisterFilterWithConfig(config *recconf.RecommendConfig) {
+// PrepareFilterWithConfig builds the changed filters of the config without registering them, commit registers the filters built.
+// The construction errors of all the filters are returned, and nothing should be committed when there are errors.
+func PrepareFilterWithConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]IFilter)
+	signs := make(map[string]string)
 	for _, conf := range config.FilterConfs {
+		sign, _ := json.Marshal(&conf)
 		if _, ok := filterMapping[conf.Name]; ok {
-			sign, _ := json.Marshal(&conf)
 			if utils.Md5(string(sign)) == filterSigns[conf.Name] {
 				continue
 			}
 		}
 
-		var f IFilter
-		if conf.FilterType == "User2ItemExposureFilter" {
-			f = NewUser2ItemExposureFilter(conf)
-		} else if conf.FilterType == "User2ItemCustomFilter" {
-			f = NewUser2ItemCustomFilter(conf)
-		} else if conf.FilterType == "AdjustCountFilter" {
-			f = NewAdjustCountFilter(conf)
-		} else if conf.FilterType == "PriorityAdjustCountFilter" {
-			f = NewPriorityAdjustCountFilter(conf)
-		} else if conf.FilterType == "PriorityAdjustCountFilterV2" {
-			f = NewPriorityAdjustCountFilterV2(conf)
-		} else if conf.FilterType == "ItemStateFilter" {
-			f = NewItemStateFilter(conf)
-		} else if conf.FilterType == "ItemCustomFilter" {
-			f = NewItemCustomFilter(conf)
-		} else if conf.FilterType == "CompletelyFairFilter" {
-			f = NewCompletelyFairCountFilter(conf)
-		} else if conf.FilterType == "GroupWeightCountFilter" {
-			f = NewGroupWeightCountFilter(conf)
-		} else if conf.FilterType == "DimensionFieldUniqueFilter" {
-			f = NewDimensionFieldUniqueFilter(conf)
-		} else if conf.FilterType == "User2ItemExposureWithConditionFilter" {
-			f = NewUser2ItemExposureWithConditionFilter(conf)
-		} else if conf.FilterType == "ConditionFilter" {
-			f = NewConditionFilter(conf)
-		} else if conf.FilterType == "DiversityAdjustCountFilter" {
-			f = NewDiversityAdjustCountFilter(conf)
+		factory, ok := filterFactories[conf.FilterType]
+		if !ok {
+			errs = append(errs, recconf.NewModuleError("filter", conf.Name, conf.FilterType, errors.New("filter type not registered")))
+			continue
 		}
 
-		if f == nil {
-			panic("Filter is nil, name:" + conf.Name)
+		conf := conf
+		f, err := recconf.BuildModule("filter", conf.Name, conf.FilterType, func() IFilter { return factory(conf) })
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		sign, _ := json.Marshal(&conf)
-		registerFilterWithSign(conf.Name, f, utils.Md5(string(sign)))
+		built[conf.Name] = f
+		signs[conf.Name] = utils.Md5(string(sign))
+	}
 
+	commit = func() {
+		for name, f := range built {
+			registerFilterWithSign(name, f, signs[name])
+		}
 	}
+
+	return commit, errs
+}
+
+// RegisterFilterWithConfig registers the changed filters of the config, it panics with the errors of all the filters
+// when any filter fails to construct, and no filter is registered in that case
+func RegisterFilterWithConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareFilterWithConfig(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
+	}
+
+	commit()
 }
 
 func Load(config *recconf.RecommendConfig) {
@@ -186,6 +220,7 @@ func Load(config *recconf.RecommendConfig) {
 }
 
 func Filter(filterData *FilterData, tag string) {
+	NewExcludeItemsFilter().Filter(filterData)
 	filterService.Filter(filterData, tag)
 }
 
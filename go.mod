@@ -19,7 +19,7 @@ require (
 	github.com/goburrow/cache v0.1.3
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
-	github.com/golang/protobuf v1.5.3
+	github.com/golang/protobuf v1.5.4
 	github.com/golang/snappy v0.0.3
 	github.com/gomodule/redigo v2.0.0+incompatible
 	github.com/huandu/go-sqlbuilder v1.21.0
@@ -41,6 +41,7 @@ require (
 
 require (
 	fortio.org/assert v1.2.1
+	github.com/BurntSushi/toml v1.2.1
 	github.com/alibabacloud-go/opensearch-util v1.0.1
 	github.com/aliyun/aliyun-pai-featurestore-go-sdk/v2 v2.3.4-0.20250612074337-3c6e7b95b667
 	github.com/aliyun/aliyun-pairec-config-go-sdk/v2 v2.0.8-0.20250424093335-55b7b4793287
@@ -49,6 +50,7 @@ require (
 	github.com/bruceding/go-antlr-valuate v0.0.3
 	github.com/google/uuid v1.3.0
 	go.uber.org/automaxprocs v1.5.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
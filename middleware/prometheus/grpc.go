@@ -0,0 +1,48 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const grpcMethod = "GRPC"
+
+// UnaryServerInterceptor defines the grpc interceptor recording the same metrics as HandlerFunc,
+// the code is the grpc status code, the method is GRPC and the url is the full method of the request
+func (p *Prometheus) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+
+	url := info.FullMethod
+	var host string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(":authority"); len(values) > 0 {
+			host = values[0]
+		}
+	}
+
+	statusStr := status.Code(err).String()
+
+	p.reqDur.WithLabelValues(statusStr, grpcMethod, host, url).Observe(elapsed)
+	p.reqCnt.WithLabelValues(statusStr, grpcMethod, host, url).Inc()
+	p.reqSz.WithLabelValues(statusStr, grpcMethod, host, url).Observe(float64(messageSize(req)))
+	p.resSz.WithLabelValues(statusStr, grpcMethod, host, url).Observe(float64(messageSize(resp)))
+
+	return resp, err
+}
+
+func messageSize(m interface{}) int {
+	if message, ok := m.(proto.Message); ok {
+		return proto.Size(message)
+	}
+
+	return 0
+}
@@ -33,8 +33,7 @@ type ColdStartRecallFeatureStoreDao struct {
 func NewColdStartRecallFeatureStoreDao(config recconf.RecallConfig) *ColdStartRecallFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.ColdStartDaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &ColdStartRecallFeatureStoreDao{
@@ -34,8 +34,7 @@ type ColdStartRecallHologresDao struct {
 func NewColdStartRecallHologresDao(config recconf.RecallConfig) *ColdStartRecallHologresDao {
 	hologres, err := holo.GetPostgres(config.ColdStartDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &ColdStartRecallHologresDao{
@@ -7,7 +7,6 @@ import (
 	"github.com/goburrow/cache"
 	"github.com/huandu/go-sqlbuilder"
 	pctx "github.com/alibaba/pairec/v2/context"
-	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/persist/holo"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/utils"
@@ -28,8 +27,7 @@ type DiversityHologresDao struct {
 func NewDiversityHologresDao(config recconf.FilterConfig) *DiversityHologresDao {
 	pg, err := holo.GetPostgres(config.DiversityDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	cacheTime := 70
 	if config.DiversityDaoConf.CacheTimeInMinutes > 0 {
@@ -69,8 +69,7 @@ func NewFeatureBeDao(config recconf.FeatureDaoConfig) *FeatureBeDao {
 		for _, winTok := range rtCntWinToks {
 			tWin := utils.ToInt(winTok, -1)
 			if tWin <= 0 {
-				log.Error(fmt.Sprintf("invalid rtCntWins: %s", winTok))
-				return nil
+				panic(fmt.Errorf("invalid rtCntWins: %s", winTok))
 			}
 			rtCntWins = append(rtCntWins, tWin)
 		}
@@ -123,8 +122,7 @@ func NewFeatureBeDao(config recconf.FeatureDaoConfig) *FeatureBeDao {
 					strings.TrimSpace(tmpAlias))
 			}
 			if len(beRTCntFields) != len(outRTCntFieldAlias) {
-				log.Error(fmt.Sprintf("len(beRTCntFields) != len(outRTCntFieldAlias): %d vs %d", len(beRTCntFields), len(outRTCntFieldAlias)))
-				return nil
+				panic(fmt.Errorf("len(beRTCntFields) != len(outRTCntFieldAlias): %d vs %d", len(beRTCntFields), len(outRTCntFieldAlias)))
 			}
 		}
 	}
@@ -187,8 +185,7 @@ func NewFeatureBeDao(config recconf.FeatureDaoConfig) *FeatureBeDao {
 
 	client, err := beengine.GetBeClient(config.BeName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.beClient = client.BeClient
 	if config.NoUsePlayTimeField {
@@ -44,8 +44,7 @@ func NewFeatureClickHouseDao(config recconf.FeatureDaoConfig) *FeatureClickHouse
 	}
 	clickHouseDB, err := clickhouse.GetClickHouse(config.ClickHouseName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = clickHouseDB.DB
 	return dao
@@ -105,7 +104,7 @@ func (d *FeatureClickHouseDao) userFeatureFetch(user *User, context *context.Rec
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -229,7 +228,7 @@ func (d *FeatureClickHouseDao) itemsFeatureFetch(items []*Item, context *context
 				}
 
 				rowsChannel := make(chan *sql.Rows, 1)
-				ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+				ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 				defer cancel()
 				// async invoke sql query
 				go func() {
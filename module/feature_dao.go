@@ -41,7 +41,7 @@ func (d *EmptyFeatureDao) FeatureFetch(user *User, items []*Item, context *conte
 }
 func (d *EmptyFeatureDao) userFeatureFetch(user *User, context *context.RecommendContext) {
 	if d.loadFromCacheFeaturesName != "" {
-		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 150*time.Millisecond)
+		ctx, cancel := gocontext.WithTimeout(context.Context(), 150*time.Millisecond)
 		defer cancel()
 		select {
 		case <-user.FeatureAsyncLoadCh():
@@ -55,31 +55,43 @@ func (d *EmptyFeatureDao) userFeatureFetch(user *User, context *context.Recommen
 	}
 }
 
+// FeatureDaoFactory creates the FeatureDao of the AdapterType from the config
+type FeatureDaoFactory func(config recconf.FeatureDaoConfig) FeatureDao
+
+// featureDaoFactories are the factories of the adapter types used by NewFeatureDao
+var featureDaoFactories = make(map[string]FeatureDaoFactory)
+
+// RegisterFeatureDaoFactory registers the factory of the adapter type, so the custom FeatureDao can be configured by AdapterType like the built-in ones,
+// the factory of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterFeatureDaoFactory(adapterType string, factory FeatureDaoFactory) {
+	if factory == nil {
+		panic("FeatureDaoFactory is nil, type:" + adapterType)
+	}
+
+	featureDaoFactories[adapterType] = factory
+	recconf.RegisterSchemaEnum("DaoConfig.AdapterType", adapterType)
+}
+
+func init() {
+	RegisterFeatureDaoFactory(recconf.DaoConf_Adapter_Redis, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureRedisDao(config) })
+	RegisterFeatureDaoFactory(recconf.DaoConf_Adapter_Hologres, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureHologresDao(config) })
+	RegisterFeatureDaoFactory(recconf.DaoConf_Adapter_TableStore, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureTablestoreDao(config) })
+	RegisterFeatureDaoFactory(recconf.DaoConf_Adapter_Mysql, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureMysqlDao(config) })
+	RegisterFeatureDaoFactory(recconf.DataSource_Type_ClickHouse, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureClickHouseDao(config) })
+	RegisterFeatureDaoFactory(recconf.DataSource_Type_FeatureStore, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureFeatureStoreDao(config) })
+	RegisterFeatureDaoFactory(recconf.DataSource_Type_BE, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureBeDao(config) })
+	RegisterFeatureDaoFactory(recconf.DataSource_Type_Lindorm, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureLindormDao(config) })
+	RegisterFeatureDaoFactory(recconf.DataSource_Type_HBase_Thrift, func(config recconf.FeatureDaoConfig) FeatureDao { return NewFeatureHBaseThriftDao(config) })
+}
+
 // NewFeatureDao create FeatureDao from config
-// config.AdapterType is decide the implement
+// config.AdapterType is decide the implement, the EmptyFeatureDao is used when the type is not registered
 func NewFeatureDao(config recconf.FeatureDaoConfig) FeatureDao {
-	if config.AdapterType == recconf.DaoConf_Adapter_Redis {
-		return NewFeatureRedisDao(config)
-	} else if config.AdapterType == recconf.DaoConf_Adapter_Hologres {
-		return NewFeatureHologresDao(config)
-	} else if config.AdapterType == recconf.DaoConf_Adapter_TableStore {
-		return NewFeatureTablestoreDao(config)
-	} else if config.AdapterType == recconf.DaoConf_Adapter_Mysql {
-		return NewFeatureMysqlDao(config)
-	} else if config.AdapterType == recconf.DataSource_Type_ClickHouse {
-		return NewFeatureClickHouseDao(config)
-	} else if config.AdapterType == recconf.DataSource_Type_FeatureStore {
-		return NewFeatureFeatureStoreDao(config)
-	} else if config.AdapterType == recconf.DataSource_Type_BE {
-		return NewFeatureBeDao(config)
-	} else if config.AdapterType == recconf.DataSource_Type_Lindorm {
-		return NewFeatureLindormDao(config)
-	} else if config.AdapterType == recconf.DataSource_Type_HBase_Thrift {
-		return NewFeatureHBaseThriftDao(config)
-	} else {
-		return NewEmptyFeatureDao(config)
+	if factory, ok := featureDaoFactories[config.AdapterType]; ok {
+		return factory(config)
 	}
 
+	return NewEmptyFeatureDao(config)
 }
 
 type FeatureBaseDao struct {
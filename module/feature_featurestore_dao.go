@@ -34,8 +34,7 @@ func NewFeatureFeatureStoreDao(config recconf.FeatureDaoConfig) *FeatureFeatureS
 	}
 	client, err := fs.GetFeatureStoreClient(config.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.client = client
 	return dao
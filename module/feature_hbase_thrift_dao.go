@@ -1,7 +1,6 @@
 package module
 
 import (
-	gocontext "context"
 	"fmt"
 	"math"
 	"strings"
@@ -90,7 +89,7 @@ func (d *FeatureHBaseThriftDao) userFeatureFetch(user *User, context *context.Re
 		return
 	}
 
-	defaultCtx := gocontext.Background()
+	defaultCtx := context.Context()
 	client, _ := hbase_thrift.GetHBaseThrift(d.hBaseName)
 	result, err := client.Client.Get(defaultCtx, []byte(d.table), &hbase.TGet{Row: []byte(key), Columns: d.userColumns})
 
@@ -178,7 +177,7 @@ func (d *FeatureHBaseThriftDao) itemsFeatureFetch(items []*Item, context *contex
 				for _, rowKey := range keys {
 					gets = append(gets, &hbase.TGet{Row: []byte(rowKey), Columns: d.itemColumns})
 				}
-				defaultCtx := gocontext.Background()
+				defaultCtx := context.Context()
 				client, _ := hbase_thrift.GetHBaseThrift(d.hBaseName)
 				result, err := client.Client.GetMultiple(defaultCtx, []byte(d.table), gets)
 
@@ -59,8 +59,7 @@ func NewFeatureHologresDao(config recconf.FeatureDaoConfig) *FeatureHologresDao
 	}
 	hologres, err := holo.GetPostgres(config.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 	if config.NoUsePlayTimeField {
@@ -154,7 +153,7 @@ func (d *FeatureHologresDao) userFeatureFetch(user *User, context *context.Recom
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -299,7 +298,7 @@ func (d *FeatureHologresDao) userSequenceFeatureFetch(user *User, context *conte
 				d.mu.Unlock()
 			}
 		}
-		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+		ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 		defer cancel()
 		rows, err := d.onlineSequenceStmt.QueryContext(ctx, args...)
 		if err != nil {
@@ -372,7 +371,7 @@ func (d *FeatureHologresDao) userSequenceFeatureFetch(user *User, context *conte
 				d.mu.Unlock()
 			}
 		}
-		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+		ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 		defer cancel()
 		rows, err := d.offlineSequenceStmt.QueryContext(ctx, args...)
 		if err != nil {
@@ -599,7 +598,7 @@ func (d *FeatureHologresDao) itemsFeatureFetch(items []*Item, context *context.R
 				}
 
 				rowsChannel := make(chan *sql.Rows, 1)
-				ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+				ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 				defer cancel()
 				// async invoke sql query
 				go func() {
@@ -47,8 +47,7 @@ func NewFeatureLindormDao(config recconf.FeatureDaoConfig) *FeatureLindormDao {
 	}
 	lindorm, err := lindorm.GetLindorm(config.LindormName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = lindorm.DB
 	return dao
@@ -104,7 +103,7 @@ func (d *FeatureLindormDao) userFeatureFetch(user *User, context *context.Recomm
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -237,7 +236,7 @@ func (d *FeatureLindormDao) itemsFeatureFetch(items []*Item, context *context.Re
 				}
 
 				rowsChannel := make(chan *sql.Rows, 1)
-				ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+				ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 				defer cancel()
 				// async invoke sql query
 				go func() {
@@ -48,8 +48,7 @@ func NewFeatureMysqlDao(config recconf.FeatureDaoConfig) *FeatureMysqlDao {
 	}
 	mysql, err := mysqldb.GetMysql(config.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	return dao
@@ -105,7 +104,7 @@ func (d *FeatureMysqlDao) userFeatureFetch(user *User, context *context.Recommen
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -230,7 +229,7 @@ func (d *FeatureMysqlDao) itemsFeatureFetch(items []*Item, context *context.Reco
 				}
 
 				rowsChannel := make(chan *sql.Rows, 1)
-				ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+				ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 				defer cancel()
 				// async invoke sql query
 				go func() {
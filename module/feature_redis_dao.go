@@ -45,8 +45,7 @@ func NewFeatureRedisDao(config recconf.FeatureDaoConfig) *FeatureRedisDao {
 	}
 	redis, err := redisdb.GetRedis(config.RedisName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.redis = redis
 	if dao.redisDelimeter == "" {
@@ -111,7 +110,11 @@ func (d *FeatureRedisDao) userFeatureFetch(user *User, context *context.Recommen
 		}
 	}
 
-	conn := d.redis.Get()
+	conn, err := d.redis.GetContext(context.Context())
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tuid=%s\terror=get redis conn error(%v)", context.RecommendId, user.Id, err))
+		return
+	}
 	defer conn.Close()
 	if d.redisDataType == REDIS_DATA_TYPE_STRING {
 		d.userFeatureFetchByString(user, context, conn, key)
@@ -120,7 +123,7 @@ func (d *FeatureRedisDao) userFeatureFetch(user *User, context *context.Recommen
 	}
 }
 func (d *FeatureRedisDao) userFeatureFetchByString(user *User, context *context.RecommendContext, conn redis.Conn, key string) error {
-	str, err := redis.String(conn.Do("GET", key))
+	str, err := redis.String(d.redis.DoContext(context.Context(), conn, "GET", key))
 	if err != nil {
 		if errors.Is(err, redis.ErrNil) {
 			log.Info(fmt.Sprintf("requestId=%s\tuid=%s\tmsg=user feature empty", context.RecommendId, user.Id))
@@ -165,7 +168,7 @@ func (d *FeatureRedisDao) userFeatureFetchByHash(user *User, context *context.Re
 	properties := make(map[string]interface{})
 	if len(d.userSelectFields) == 0 {
 		// get all fields
-		strs, err := redis.Strings(conn.Do("HGETALL", key))
+		strs, err := redis.Strings(d.redis.DoContext(context.Context(), conn, "HGETALL", key))
 		if err != nil {
 			log.Error(fmt.Sprintf("requestId=%s\tuid=%s\terror=get user feature error(%v)", context.RecommendId, user.Id, err))
 			return err
@@ -178,7 +181,7 @@ func (d *FeatureRedisDao) userFeatureFetchByHash(user *User, context *context.Re
 		var params []interface{}
 		params = append(params, key)
 		params = append(params, d.userSelectFields...)
-		strs, err := redis.Strings(conn.Do("HMGET", params...))
+		strs, err := redis.Strings(d.redis.DoContext(context.Context(), conn, "HMGET", params...))
 		if err != nil {
 			log.Error(fmt.Sprintf("requestId=%s\tuid=%s\terror=get user feature error(%v)", context.RecommendId, user.Id, err))
 			return err
@@ -260,7 +263,11 @@ func (d *FeatureRedisDao) itemsFeatureFetch(items []*Item, context *context.Reco
 					return
 				}
 
-				conn := d.redis.Get()
+				conn, err := d.redis.GetContext(context.Context())
+				if err != nil {
+					log.Error(fmt.Sprintf("requestId=%s\tmodule=FeatureRedisDao\terror=%v", context.RecommendId, err))
+					return
+				}
 				defer conn.Close()
 				if d.redisDataType == REDIS_DATA_TYPE_STRING {
 					d.itemFeatureFetchByString(itemlist, context, conn, keys)
@@ -275,7 +282,7 @@ func (d *FeatureRedisDao) itemsFeatureFetch(items []*Item, context *context.Reco
 }
 
 func (d *FeatureRedisDao) itemFeatureFetchByString(items []*Item, context *context.RecommendContext, conn redis.Conn, keys []interface{}) error {
-	values, err := redis.Strings(conn.Do("MGET", keys...))
+	values, err := redis.Strings(d.redis.DoContext(context.Context(), conn, "MGET", keys...))
 	if err != nil {
 		log.Error(fmt.Sprintf("requestId=%s\tmodule=FeatureRedisDao\terror=%v", context.RecommendId, err))
 		return err
@@ -331,7 +338,7 @@ func (d *FeatureRedisDao) itemFeatureFetchByHash(items []*Item, context *context
 		conn.Flush()
 
 		for i, key := range keys {
-			strs, err := redis.Strings(conn.Receive())
+			strs, err := redis.Strings(d.redis.ReceiveContext(context.Context(), conn))
 			if err != nil {
 				log.Error(fmt.Sprintf("requestId=%s\tmodule=FeatureRedisDao\terror=%v", context.RecommendId, err))
 				continue
@@ -356,7 +363,7 @@ func (d *FeatureRedisDao) itemFeatureFetchByHash(items []*Item, context *context
 		}
 		conn.Flush()
 		for i, key := range keys {
-			strs, err := redis.Strings(conn.Receive())
+			strs, err := redis.Strings(d.redis.ReceiveContext(context.Context(), conn))
 			if err != nil {
 				log.Error(fmt.Sprintf("requestId=%s\tmodule=FeatureRedisDao\terror=%v", context.RecommendId, err))
 				continue
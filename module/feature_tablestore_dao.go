@@ -48,8 +48,7 @@ func NewFeatureTablestoreDao(config recconf.FeatureDaoConfig) *FeatureTablestore
 	}
 	tablestore, err := tablestoredb.GetTableStore(config.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	return dao
@@ -0,0 +1,339 @@
+package module
+
+import (
+	"bufio"
+	gocontext "context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/huandu/go-sqlbuilder"
+
+	"github.com/alibaba/pairec/v2/persist/holo"
+	"github.com/alibaba/pairec/v2/persist/redisdb"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const Default_Geo_Precision = 6
+
+// GeoItem is the item with the location, Timestamp is 0 when the item has no time
+type GeoItem struct {
+	Id        string
+	Latitude  float64
+	Longitude float64
+	Timestamp int64
+}
+
+// GeoItemDao fetches the items of the geohash cells
+type GeoItemDao interface {
+	GeoItems(ctx gocontext.Context, geohashes []string) (map[string][]*GeoItem, error)
+}
+
+// NewGeoItemDao creates the dao of the config, the local index is loaded when InMemory is true
+func NewGeoItemDao(config recconf.GeoRecallConfig) GeoItemDao {
+	if config.InMemory {
+		dao := NewGeoItemMemoryDao(config.Precision, newGeoItemLoader(config))
+		if err := dao.Load(); err != nil {
+			panic(err)
+		}
+		return dao
+	}
+
+	switch config.DaoConf.AdapterType {
+	case recconf.DaoConf_Adapter_Redis:
+		return NewGeoItemRedisDao(config)
+	case recconf.DaoConf_Adapter_Hologres:
+		return NewGeoItemHologresDao(config)
+	}
+
+	panic("not found GeoItemDao implement")
+}
+
+// newGeoItemLoader returns the loader of all the items of the local index, from FilePath or the hologres table
+func newGeoItemLoader(config recconf.GeoRecallConfig) func() ([]*GeoItem, error) {
+	if config.FilePath != "" {
+		return func() ([]*GeoItem, error) {
+			return loadGeoItemFile(config.FilePath)
+		}
+	}
+
+	if config.DaoConf.AdapterType == recconf.DaoConf_Adapter_Hologres {
+		dao := NewGeoItemHologresDao(config)
+		return dao.AllItems
+	}
+
+	panic("GeoItemMemoryDao needs FilePath or the hologres table")
+}
+
+// parseGeoItem parses the item id, latitude, longitude and the optional timestamp
+func parseGeoItem(fields []string) (*GeoItem, error) {
+	if len(fields) < 3 {
+		return nil, errors.New("expect the item id, latitude and longitude")
+	}
+
+	item := &GeoItem{Id: strings.TrimSpace(fields[0])}
+	var err error
+	if item.Latitude, err = strconv.ParseFloat(strings.TrimSpace(fields[1]), 64); err != nil {
+		return nil, fmt.Errorf("invalid latitude: %w", err)
+	}
+	if item.Longitude, err = strconv.ParseFloat(strings.TrimSpace(fields[2]), 64); err != nil {
+		return nil, fmt.Errorf("invalid longitude: %w", err)
+	}
+	if len(fields) > 3 && strings.TrimSpace(fields[3]) != "" {
+		if item.Timestamp, err = strconv.ParseInt(strings.TrimSpace(fields[3]), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid timestamp: %w", err)
+		}
+	}
+
+	return item, nil
+}
+
+func loadGeoItemFile(filePath string) ([]*GeoItem, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var items []*GeoItem
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		item, err := parseGeoItem(strings.Split(line, "\t"))
+		if err != nil {
+			return nil, fmt.Errorf("geo item file %s line %d: %v", filePath, lineNum, err)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GeoItemMemoryDao keeps the items in the local index by the geohash of the precision, Load replaces the index when all the items are loaded
+type GeoItemMemoryDao struct {
+	precision int
+	loader    func() ([]*GeoItem, error)
+	index     atomic.Pointer[map[string][]*GeoItem]
+}
+
+func NewGeoItemMemoryDao(precision int, loader func() ([]*GeoItem, error)) *GeoItemMemoryDao {
+	if precision <= 0 {
+		precision = Default_Geo_Precision
+	}
+
+	return &GeoItemMemoryDao{precision: precision, loader: loader}
+}
+
+// Load loads all the items and builds the new index, the index in use is kept when loading fails
+func (d *GeoItemMemoryDao) Load() error {
+	items, err := d.loader()
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string][]*GeoItem)
+	for _, item := range items {
+		geohash, _ := utils.GeoHashEncode(item.Latitude, item.Longitude, d.precision)
+		index[geohash] = append(index[geohash], item)
+	}
+	d.index.Store(&index)
+
+	return nil
+}
+
+func (d *GeoItemMemoryDao) GeoItems(ctx gocontext.Context, geohashes []string) (map[string][]*GeoItem, error) {
+	index := d.index.Load()
+	if index == nil {
+		return nil, errors.New("geo item index not loaded")
+	}
+
+	ret := make(map[string][]*GeoItem, len(geohashes))
+	for _, geohash := range geohashes {
+		if items, ok := (*index)[geohash]; ok {
+			ret[geohash] = items
+		}
+	}
+
+	return ret, nil
+}
+
+// GeoItemRedisDao fetches the items of the cells by the keys RedisPrefix+geohash, the value is the items separated by the comma,
+// each item is the item id, latitude, longitude and the optional timestamp separated by the colon
+type GeoItemRedisDao struct {
+	redis  *redisdb.Redis
+	prefix string
+}
+
+func NewGeoItemRedisDao(config recconf.GeoRecallConfig) *GeoItemRedisDao {
+	redis, err := redisdb.GetRedis(config.DaoConf.RedisName)
+	if err != nil {
+		panic(err)
+	}
+
+	return &GeoItemRedisDao{redis: redis, prefix: config.DaoConf.RedisPrefix}
+}
+
+func (d *GeoItemRedisDao) GeoItems(ctx gocontext.Context, geohashes []string) (map[string][]*GeoItem, error) {
+	if len(geohashes) == 0 {
+		return nil, nil
+	}
+
+	conn := d.redis.Get()
+	defer conn.Close()
+
+	keys := make([]interface{}, 0, len(geohashes))
+	for _, geohash := range geohashes {
+		keys = append(keys, d.prefix+geohash)
+	}
+	values, err := redis.Strings(conn.Do("MGET", keys...))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]*GeoItem, len(geohashes))
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		for _, str := range strings.Split(value, ",") {
+			if item, err := parseGeoItem(strings.Split(str, ":")); err == nil {
+				ret[geohashes[i]] = append(ret[geohashes[i]], item)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// GeoItemHologresDao fetches the items of the cells by the geohash field of the table, the geohash field has the precision of the config
+type GeoItemHologresDao struct {
+	db             *sql.DB
+	table          string
+	itemIdField    string
+	geohashField   string
+	latitudeField  string
+	longitudeField string
+	timestampField string
+}
+
+func NewGeoItemHologresDao(config recconf.GeoRecallConfig) *GeoItemHologresDao {
+	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
+	if err != nil {
+		panic(err)
+	}
+
+	dao := &GeoItemHologresDao{
+		db:             hologres.DB,
+		table:          config.DaoConf.HologresTableName,
+		itemIdField:    config.DaoConf.ItemIdField,
+		geohashField:   config.GeoHashField,
+		latitudeField:  config.LatitudeField,
+		longitudeField: config.LongitudeField,
+		timestampField: config.TimestampField,
+	}
+	if dao.itemIdField == "" {
+		dao.itemIdField = "item_id"
+	}
+	if dao.geohashField == "" {
+		dao.geohashField = "geohash"
+	}
+	if dao.latitudeField == "" {
+		dao.latitudeField = "latitude"
+	}
+	if dao.longitudeField == "" {
+		dao.longitudeField = "longitude"
+	}
+
+	return dao
+}
+
+// selectBuilder selects the item fields, and the geohash field first when withGeohash is true
+func (d *GeoItemHologresDao) selectBuilder(withGeohash bool) *sqlbuilder.SelectBuilder {
+	fields := []string{d.itemIdField, d.latitudeField, d.longitudeField}
+	if withGeohash {
+		fields = append([]string{d.geohashField}, fields...)
+	}
+	if d.timestampField != "" {
+		fields = append(fields, d.timestampField)
+	}
+
+	builder := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	builder.Select(fields...)
+	builder.From(d.table)
+
+	return builder
+}
+
+func (d *GeoItemHologresDao) query(ctx gocontext.Context, builder *sqlbuilder.SelectBuilder, withGeohash bool) (map[string][]*GeoItem, error) {
+	query, args := builder.Build()
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make(map[string][]*GeoItem)
+	for rows.Next() {
+		var geohash, itemId string
+		var latitude, longitude float64
+		var timestamp sql.NullInt64
+		dest := []interface{}{&itemId, &latitude, &longitude}
+		if withGeohash {
+			dest = append([]interface{}{&geohash}, dest...)
+		}
+		if d.timestampField != "" {
+			dest = append(dest, &timestamp)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		ret[geohash] = append(ret[geohash], &GeoItem{Id: itemId, Latitude: latitude, Longitude: longitude, Timestamp: timestamp.Int64})
+	}
+
+	return ret, rows.Err()
+}
+
+func (d *GeoItemHologresDao) GeoItems(ctx gocontext.Context, geohashes []string) (map[string][]*GeoItem, error) {
+	if len(geohashes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(geohashes))
+	for _, geohash := range geohashes {
+		args = append(args, geohash)
+	}
+	builder := d.selectBuilder(true)
+	builder.Where(builder.In(d.geohashField, args...))
+
+	return d.query(ctx, builder, true)
+}
+
+// AllItems loads all the items of the table for the local index
+func (d *GeoItemHologresDao) AllItems() ([]*GeoItem, error) {
+	cells, err := d.query(gocontext.Background(), d.selectBuilder(false), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*GeoItem
+	for _, cellItems := range cells {
+		items = append(items, cellItems...)
+	}
+
+	return items, nil
+}
@@ -310,3 +310,9 @@ func (t *Item) DeepClone() *Item {
 	item.algoScores = algoScores
 	return item
 }
+
+// PinnedItem is the item of the request which must appear at the position, position starts from 1
+type PinnedItem struct {
+	ItemId   string `json:"item_id"`
+	Position int    `json:"position"`
+}
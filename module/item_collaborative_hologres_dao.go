@@ -30,8 +30,7 @@ type ItemCollaborativeHologresDao struct {
 func NewItemCollaborativeHologresDao(config recconf.RecallConfig) *ItemCollaborativeHologresDao {
 	hologres, err := holo.GetPostgres(config.ItemCollaborativeDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &ItemCollaborativeHologresDao{
@@ -71,7 +70,7 @@ func (d *ItemCollaborativeHologresDao) ListItemsByItem(user *User, context *cont
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
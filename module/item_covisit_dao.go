@@ -0,0 +1,365 @@
+package module
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+const (
+	Covisit_Algorithm_Swing   = "swing"
+	Covisit_Algorithm_Covisit = "covisit"
+
+	Default_Covisit_Swing_Alpha     = 1
+	Default_Covisit_Session_Window  = 1800
+	Default_Covisit_Half_Life       = 86400
+	Default_Covisit_Expire          = 604800
+	Default_Covisit_User_Item_Count = 50
+	Default_Covisit_Item_User_Count = 50
+	Default_Covisit_Max_Neighbors   = 200
+	Default_Covisit_Shard_Count     = 32
+)
+
+// ItemCovisitEvent is the item the user acts on at the timestamp in seconds
+type ItemCovisitEvent struct {
+	ItemId    string
+	Timestamp int64
+}
+
+// ItemCovisitStorage keeps the recent items of the users, the recent users of the items and the decayed scores of the item pairs
+type ItemCovisitStorage interface {
+	// UserItems returns the recent items of the users in the order of the time
+	UserItems(userIds []string) (map[string][]*ItemCovisitEvent, error)
+	// ItemUsers returns the recent users of the items
+	ItemUsers(itemIds []string) (map[string][]string, error)
+	// AddEvents appends the events to the recent items of the user and the user to the recent users of the items
+	AddEvents(userId string, events []*ItemCovisitEvent) error
+	// IncrPairs adds the weights of the neighbors of the items at the timestamp
+	IncrPairs(pairs map[string]map[string]float64, timestamp int64) error
+	// Neighbors returns at most size neighbors of each item with the scores decayed to the timestamp
+	Neighbors(itemIds []string, size int, timestamp int64) (map[string]map[string]float64, error)
+}
+
+// ItemCovisitDao computes the i2i statistics online by swing or session co-visitation from the items the users act on
+type ItemCovisitDao struct {
+	storage       ItemCovisitStorage
+	algorithm     string
+	swingAlpha    float64
+	sessionWindow int64
+	// memoryStorageKey is the key of the shared local memory storage, it is empty for the other storages
+	memoryStorageKey string
+}
+
+var (
+	covisitMemoryStorages   = make(map[string]*ItemCovisitMemoryStorage)
+	covisitMemoryStoragesMu sync.Mutex
+)
+
+// NewItemCovisitDao creates the dao of the config, the local memory storage is shared by the name and the fields of the storage,
+// so the statistics are kept when the config of the recall is reloaded, and the storage is recreated when its fields change
+func NewItemCovisitDao(name string, config recconf.OnlineI2IConfig) *ItemCovisitDao {
+	config = defaultOnlineI2IConfig(config)
+
+	var storage ItemCovisitStorage
+	var memoryStorageKey string
+	switch config.DaoConf.AdapterType {
+	case "":
+		memoryStorageKey = itemCovisitMemoryStorageKey(name, config)
+		covisitMemoryStoragesMu.Lock()
+		memoryStorage, ok := covisitMemoryStorages[memoryStorageKey]
+		if !ok {
+			memoryStorage = NewItemCovisitMemoryStorage(config)
+			covisitMemoryStorages[memoryStorageKey] = memoryStorage
+		}
+		covisitMemoryStoragesMu.Unlock()
+		storage = memoryStorage
+	case recconf.DaoConf_Adapter_Redis:
+		storage = NewItemCovisitRedisStorage(config)
+	default:
+		panic(fmt.Sprintf("ItemCovisitDao not support adapter type, adapter:%s", config.DaoConf.AdapterType))
+	}
+
+	dao := NewItemCovisitDaoWithStorage(storage, config)
+	dao.memoryStorageKey = memoryStorageKey
+	return dao
+}
+
+// itemCovisitMemoryStorageKey returns the key of the memory storage by the name and the fields the storage is created with
+func itemCovisitMemoryStorageKey(name string, config recconf.OnlineI2IConfig) string {
+	return fmt.Sprintf("%s#%d#%d#%d#%d#%d#%d", name, config.ShardCount, config.HalfLife, config.Expire,
+		config.MaxNeighbors, config.UserItemCount, config.ItemUserCount)
+}
+
+// RetainItemCovisitMemoryStorages keeps the local memory storages of the daos, and releases the others,
+// such as the storages of the recalls removed from the config or the ones replaced by the new fields
+func RetainItemCovisitMemoryStorages(daos []*ItemCovisitDao) {
+	keys := make(map[string]bool, len(daos))
+	for _, dao := range daos {
+		if dao.memoryStorageKey != "" {
+			keys[dao.memoryStorageKey] = true
+		}
+	}
+
+	covisitMemoryStoragesMu.Lock()
+	defer covisitMemoryStoragesMu.Unlock()
+	for key := range covisitMemoryStorages {
+		if !keys[key] {
+			delete(covisitMemoryStorages, key)
+		}
+	}
+}
+
+func NewItemCovisitDaoWithStorage(storage ItemCovisitStorage, config recconf.OnlineI2IConfig) *ItemCovisitDao {
+	config = defaultOnlineI2IConfig(config)
+	switch config.Algorithm {
+	case Covisit_Algorithm_Swing, Covisit_Algorithm_Covisit:
+	default:
+		panic(fmt.Sprintf("ItemCovisitDao not support algorithm, algorithm:%s", config.Algorithm))
+	}
+
+	return &ItemCovisitDao{
+		storage:       storage,
+		algorithm:     config.Algorithm,
+		swingAlpha:    config.SwingAlpha,
+		sessionWindow: int64(config.SessionWindow),
+	}
+}
+
+func defaultOnlineI2IConfig(config recconf.OnlineI2IConfig) recconf.OnlineI2IConfig {
+	if config.Algorithm == "" {
+		config.Algorithm = Covisit_Algorithm_Covisit
+	}
+	if config.SwingAlpha <= 0 {
+		config.SwingAlpha = Default_Covisit_Swing_Alpha
+	}
+	if config.SessionWindow <= 0 {
+		config.SessionWindow = Default_Covisit_Session_Window
+	}
+	if config.HalfLife <= 0 {
+		config.HalfLife = Default_Covisit_Half_Life
+	}
+	if config.Expire <= 0 {
+		config.Expire = Default_Covisit_Expire
+	}
+	if config.UserItemCount <= 0 {
+		config.UserItemCount = Default_Covisit_User_Item_Count
+	}
+	if config.ItemUserCount <= 0 {
+		config.ItemUserCount = Default_Covisit_Item_User_Count
+	}
+	if config.MaxNeighbors <= 0 {
+		config.MaxNeighbors = Default_Covisit_Max_Neighbors
+	}
+	if config.ShardCount <= 0 {
+		config.ShardCount = Default_Covisit_Shard_Count
+	}
+
+	return config
+}
+
+// AddUserItems records the items the user acts on at the timestamp, and adds the weights of the pairs of the items
+// and the items of the user within the session window. The weight of covisit is 1/log(2+n) where n is the count of the session items,
+// and the weight of swing is the sum of 1/(alpha+|Iu∩Iv|) of the other users v acting on both items.
+func (d *ItemCovisitDao) AddUserItems(userId string, itemIds []string, timestamp int64) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	userItems, err := d.storage.UserItems([]string{userId})
+	if err != nil {
+		return err
+	}
+	history := userItems[userId]
+
+	// the session items are the recent items within the window followed by the new items, each item once
+	var session []string
+	inSession := make(map[string]bool)
+	for _, event := range history {
+		if timestamp-event.Timestamp <= d.sessionWindow && !inSession[event.ItemId] {
+			inSession[event.ItemId] = true
+			session = append(session, event.ItemId)
+		}
+	}
+	var newItems []string
+	events := make([]*ItemCovisitEvent, 0, len(itemIds))
+	for _, itemId := range itemIds {
+		if itemId == "" {
+			continue
+		}
+		events = append(events, &ItemCovisitEvent{ItemId: itemId, Timestamp: timestamp})
+		if !inSession[itemId] {
+			inSession[itemId] = true
+			newItems = append(newItems, itemId)
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	previous := len(session)
+	session = append(session, newItems...)
+
+	var pairs map[string]map[string]float64
+	if d.algorithm == Covisit_Algorithm_Swing {
+		pairs, err = d.swingPairs(userId, history, session, previous)
+		if err != nil {
+			return err
+		}
+	} else {
+		pairs = d.covisitPairs(session, previous)
+	}
+
+	if err := d.storage.AddEvents(userId, events); err != nil {
+		return err
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	return d.storage.IncrPairs(pairs, timestamp)
+}
+
+// covisitPairs returns the weights of the new items of the session paired with the items before them
+func (d *ItemCovisitDao) covisitPairs(session []string, previous int) map[string]map[string]float64 {
+	pairs := make(map[string]map[string]float64)
+	weight := 1 / math.Log(2+float64(len(session)))
+	for i := previous; i < len(session); i++ {
+		for j := 0; j < i; j++ {
+			addCovisitPair(pairs, session[i], session[j], weight)
+		}
+	}
+
+	return pairs
+}
+
+// swingPairs returns the swing weights of the new items of the session paired with the items before them,
+// the pair is weighted by the other users who have acted on both items
+func (d *ItemCovisitDao) swingPairs(userId string, history []*ItemCovisitEvent, session []string, previous int) (map[string]map[string]float64, error) {
+	itemUsers, err := d.storage.ItemUsers(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var otherUserIds []string
+	otherUsers := make(map[string]bool)
+	for _, users := range itemUsers {
+		for _, user := range users {
+			if user != userId && !otherUsers[user] {
+				otherUsers[user] = true
+				otherUserIds = append(otherUserIds, user)
+			}
+		}
+	}
+	if len(otherUserIds) == 0 {
+		return nil, nil
+	}
+
+	otherItems, err := d.storage.UserItems(otherUserIds)
+	if err != nil {
+		return nil, err
+	}
+
+	userItems := make(map[string]bool, len(history)+len(session))
+	for _, event := range history {
+		userItems[event.ItemId] = true
+	}
+	for _, itemId := range session {
+		userItems[itemId] = true
+	}
+
+	// the overlap of the items of the user and each other user
+	overlaps := make(map[string]int, len(otherItems))
+	otherItemSets := make(map[string]map[string]bool, len(otherItems))
+	for user, events := range otherItems {
+		items := make(map[string]bool, len(events))
+		for _, event := range events {
+			if !items[event.ItemId] {
+				items[event.ItemId] = true
+				if userItems[event.ItemId] {
+					overlaps[user]++
+				}
+			}
+		}
+		otherItemSets[user] = items
+	}
+
+	pairs := make(map[string]map[string]float64)
+	for i := previous; i < len(session); i++ {
+		for j := 0; j < i; j++ {
+			var weight float64
+			for _, user := range itemUsers[session[j]] {
+				if user == userId || !otherItemSets[user][session[i]] {
+					continue
+				}
+				weight += 1 / (d.swingAlpha + float64(overlaps[user]))
+			}
+			if weight > 0 {
+				addCovisitPair(pairs, session[i], session[j], weight)
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+func addCovisitPair(pairs map[string]map[string]float64, a, b string, weight float64) {
+	if pairs[a] == nil {
+		pairs[a] = make(map[string]float64)
+	}
+	if pairs[b] == nil {
+		pairs[b] = make(map[string]float64)
+	}
+	pairs[a][b] += weight
+	pairs[b][a] += weight
+}
+
+// Neighbors returns at most size neighbors of each item with the scores decayed to the timestamp
+func (d *ItemCovisitDao) Neighbors(itemIds []string, size int, timestamp int64) (map[string]map[string]float64, error) {
+	return d.storage.Neighbors(itemIds, size, timestamp)
+}
+
+// UserItems returns the recent items of the user in the order of the time
+func (d *ItemCovisitDao) UserItems(userId string) ([]*ItemCovisitEvent, error) {
+	userItems, err := d.storage.UserItems([]string{userId})
+	if err != nil {
+		return nil, err
+	}
+
+	return userItems[userId], nil
+}
+
+// covisitDecay returns the decay of the score after the seconds by the half life
+func covisitDecay(seconds int64, halfLife float64) float64 {
+	if seconds <= 0 {
+		return 1
+	}
+
+	return math.Exp2(-float64(seconds) / halfLife)
+}
+
+// topCovisitScores returns at most size scores of the highest ones
+func topCovisitScores(scores map[string]float64, size int) map[string]float64 {
+	if size <= 0 || len(scores) <= size {
+		return scores
+	}
+
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] == scores[ids[j]] {
+			return ids[i] < ids[j]
+		}
+		return scores[ids[i]] > scores[ids[j]]
+	})
+
+	ret := make(map[string]float64, size)
+	for _, id := range ids[:size] {
+		ret[id] = scores[id]
+	}
+
+	return ret
+}
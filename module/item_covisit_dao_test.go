@@ -0,0 +1,121 @@
+package module
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func TestItemCovisitDaoCovisit(t *testing.T) {
+	config := recconf.OnlineI2IConfig{SessionWindow: 100, HalfLife: 10, ShardCount: 4}
+	dao := NewItemCovisitDaoWithStorage(NewItemCovisitMemoryStorage(config), config)
+
+	if err := dao.AddUserItems("u1", []string{"a", "b"}, 1000); err != nil {
+		t.Fatal(err)
+	}
+	// c is out of the session window of a and b
+	if err := dao.AddUserItems("u1", []string{"c"}, 1200); err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.AddUserItems("u2", []string{"a"}, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.AddUserItems("u2", []string{"c", "a"}, 1010); err != nil {
+		t.Fatal(err)
+	}
+
+	neighbors, err := dao.Neighbors([]string{"a", "b"}, 10, 1010)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abWeight := 1 / math.Log(4)
+	acWeight := 1 / math.Log(4)
+	if got := neighbors["b"]["a"]; math.Abs(got-abWeight*0.5) > 1e-9 {
+		t.Errorf("expect the score of b-a decayed by the half life %v, got %v", abWeight*0.5, got)
+	}
+	if got := neighbors["a"]["c"]; math.Abs(got-acWeight) > 1e-9 {
+		t.Errorf("expect the score of a-c %v, got %v", acWeight, got)
+	}
+	if _, ok := neighbors["b"]["c"]; ok {
+		t.Error("expect c out of the session window of b")
+	}
+
+	events, err := dao.UserItems("u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].ItemId != "c" || events[1].ItemId != "a" {
+		t.Errorf("expect the recent items c and a of u2, got %v", events)
+	}
+}
+
+func TestItemCovisitDaoSwing(t *testing.T) {
+	config := recconf.OnlineI2IConfig{Algorithm: Covisit_Algorithm_Swing, ShardCount: 4}
+	dao := NewItemCovisitDaoWithStorage(NewItemCovisitMemoryStorage(config), config)
+
+	for _, add := range []struct {
+		user  string
+		items []string
+	}{
+		{"v1", []string{"a", "b"}},
+		{"v2", []string{"a", "c"}},
+		{"u", []string{"a"}},
+		{"u", []string{"b"}},
+	} {
+		if err := dao.AddUserItems(add.user, add.items, 1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	neighbors, err := dao.Neighbors([]string{"b", "c"}, 10, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// v1 acts on both a and b, and the overlap of u and v1 is 2
+	if got := neighbors["b"]["a"]; math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("expect the swing score of b-a 1/3, got %v", got)
+	}
+	if len(neighbors["c"]) != 0 {
+		t.Errorf("expect no neighbor of c without the other users, got %v", neighbors["c"])
+	}
+}
+
+func TestItemCovisitMemoryStorageMaxNeighbors(t *testing.T) {
+	storage := NewItemCovisitMemoryStorage(recconf.OnlineI2IConfig{MaxNeighbors: 2, ShardCount: 1})
+	if err := storage.IncrPairs(map[string]map[string]float64{"a": {"b": 3, "c": 1, "d": 2}}, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	neighbors, _ := storage.Neighbors([]string{"a"}, 10, 1000)
+	if len(neighbors["a"]) != 2 || neighbors["a"]["b"] != 3 || neighbors["a"]["d"] != 2 {
+		t.Errorf("expect the neighbors b and d, got %v", neighbors["a"])
+	}
+
+	neighbors, _ = storage.Neighbors([]string{"a"}, 1, 1000)
+	if len(neighbors["a"]) != 1 || neighbors["a"]["b"] != 3 {
+		t.Errorf("expect the top neighbor b, got %v", neighbors["a"])
+	}
+}
+
+func TestItemCovisitDaoMemoryStorage(t *testing.T) {
+	dao := NewItemCovisitDao("covisit_storage_test", recconf.OnlineI2IConfig{})
+	if dao.storage != NewItemCovisitDao("covisit_storage_test", recconf.OnlineI2IConfig{}).storage {
+		t.Error("expect the memory storage shared by the name")
+	}
+
+	newDao := NewItemCovisitDao("covisit_storage_test", recconf.OnlineI2IConfig{MaxNeighbors: 10})
+	if newDao.storage == dao.storage {
+		t.Error("expect the memory storage recreated after the fields changed")
+	}
+
+	RetainItemCovisitMemoryStorages([]*ItemCovisitDao{newDao})
+	covisitMemoryStoragesMu.Lock()
+	_, oldExist := covisitMemoryStorages[dao.memoryStorageKey]
+	_, newExist := covisitMemoryStorages[newDao.memoryStorageKey]
+	covisitMemoryStoragesMu.Unlock()
+	if oldExist || !newExist {
+		t.Errorf("expect only the storage of the retained dao kept, old:%v, new:%v", oldExist, newExist)
+	}
+}
@@ -0,0 +1,236 @@
+package module
+
+import (
+	"sync"
+
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// covisitCleanupCount is the count of the writes of the shard between the cleanups of the expired entries
+const covisitCleanupCount = 4096
+
+type covisitScore struct {
+	score     float64
+	timestamp int64
+}
+
+type covisitItemUser struct {
+	userId    string
+	timestamp int64
+}
+
+type covisitShard struct {
+	mu        sync.RWMutex
+	userItems map[string][]*ItemCovisitEvent
+	itemUsers map[string][]*covisitItemUser
+	pairs     map[string]map[string]*covisitScore
+	writes    int
+}
+
+// ItemCovisitMemoryStorage keeps the statistics in the local memory sharded by the keys, the scores are decayed lazily by the half life,
+// and the entries not updated in the expire seconds are removed
+type ItemCovisitMemoryStorage struct {
+	shards        []*covisitShard
+	halfLife      float64
+	expire        int64
+	userItemCount int
+	itemUserCount int
+	maxNeighbors  int
+}
+
+func NewItemCovisitMemoryStorage(config recconf.OnlineI2IConfig) *ItemCovisitMemoryStorage {
+	config = defaultOnlineI2IConfig(config)
+	storage := &ItemCovisitMemoryStorage{
+		shards:        make([]*covisitShard, config.ShardCount),
+		halfLife:      float64(config.HalfLife),
+		expire:        int64(config.Expire),
+		userItemCount: config.UserItemCount,
+		itemUserCount: config.ItemUserCount,
+		maxNeighbors:  config.MaxNeighbors,
+	}
+	for i := range storage.shards {
+		storage.shards[i] = &covisitShard{
+			userItems: make(map[string][]*ItemCovisitEvent),
+			itemUsers: make(map[string][]*covisitItemUser),
+			pairs:     make(map[string]map[string]*covisitScore),
+		}
+	}
+
+	return storage
+}
+
+func (s *ItemCovisitMemoryStorage) shard(key string) *covisitShard {
+	return s.shards[utils.HashValue(key)%uint64(len(s.shards))]
+}
+
+func (s *ItemCovisitMemoryStorage) UserItems(userIds []string) (map[string][]*ItemCovisitEvent, error) {
+	ret := make(map[string][]*ItemCovisitEvent, len(userIds))
+	for _, userId := range userIds {
+		shard := s.shard(userId)
+		shard.mu.RLock()
+		if events, ok := shard.userItems[userId]; ok {
+			ret[userId] = append([]*ItemCovisitEvent(nil), events...)
+		}
+		shard.mu.RUnlock()
+	}
+
+	return ret, nil
+}
+
+func (s *ItemCovisitMemoryStorage) ItemUsers(itemIds []string) (map[string][]string, error) {
+	ret := make(map[string][]string, len(itemIds))
+	for _, itemId := range itemIds {
+		shard := s.shard(itemId)
+		shard.mu.RLock()
+		for _, user := range shard.itemUsers[itemId] {
+			ret[itemId] = append(ret[itemId], user.userId)
+		}
+		shard.mu.RUnlock()
+	}
+
+	return ret, nil
+}
+
+func (s *ItemCovisitMemoryStorage) AddEvents(userId string, events []*ItemCovisitEvent) error {
+	shard := s.shard(userId)
+	shard.mu.Lock()
+	userItems := shard.userItems[userId]
+	for _, event := range events {
+		userItems = removeCovisitEvent(userItems, event.ItemId)
+		userItems = append(userItems, event)
+	}
+	if len(userItems) > s.userItemCount {
+		userItems = append([]*ItemCovisitEvent(nil), userItems[len(userItems)-s.userItemCount:]...)
+	}
+	shard.userItems[userId] = userItems
+	s.written(shard, events[len(events)-1].Timestamp)
+	shard.mu.Unlock()
+
+	for _, event := range events {
+		shard := s.shard(event.ItemId)
+		shard.mu.Lock()
+		users := shard.itemUsers[event.ItemId]
+		for i, user := range users {
+			if user.userId == userId {
+				users = append(users[:i], users[i+1:]...)
+				break
+			}
+		}
+		users = append(users, &covisitItemUser{userId: userId, timestamp: event.Timestamp})
+		if len(users) > s.itemUserCount {
+			users = append([]*covisitItemUser(nil), users[len(users)-s.itemUserCount:]...)
+		}
+		shard.itemUsers[event.ItemId] = users
+		s.written(shard, event.Timestamp)
+		shard.mu.Unlock()
+	}
+
+	return nil
+}
+
+func removeCovisitEvent(events []*ItemCovisitEvent, itemId string) []*ItemCovisitEvent {
+	for i, event := range events {
+		if event.ItemId == itemId {
+			return append(events[:i], events[i+1:]...)
+		}
+	}
+
+	return events
+}
+
+func (s *ItemCovisitMemoryStorage) IncrPairs(pairs map[string]map[string]float64, timestamp int64) error {
+	for itemId, neighbors := range pairs {
+		shard := s.shard(itemId)
+		shard.mu.Lock()
+		scores, ok := shard.pairs[itemId]
+		if !ok {
+			scores = make(map[string]*covisitScore, len(neighbors))
+			shard.pairs[itemId] = scores
+		}
+		for neighbor, weight := range neighbors {
+			score, ok := scores[neighbor]
+			if !ok {
+				score = &covisitScore{timestamp: timestamp}
+				scores[neighbor] = score
+			}
+			score.score = score.score*covisitDecay(timestamp-score.timestamp, s.halfLife) + weight
+			if timestamp > score.timestamp {
+				score.timestamp = timestamp
+			}
+		}
+		if len(scores) > s.maxNeighbors {
+			s.truncate(scores, timestamp)
+		}
+		s.written(shard, timestamp)
+		shard.mu.Unlock()
+	}
+
+	return nil
+}
+
+// truncate removes the neighbors of the lowest decayed scores until the count is maxNeighbors
+func (s *ItemCovisitMemoryStorage) truncate(scores map[string]*covisitScore, timestamp int64) {
+	decayed := make(map[string]float64, len(scores))
+	for neighbor, score := range scores {
+		decayed[neighbor] = score.score * covisitDecay(timestamp-score.timestamp, s.halfLife)
+	}
+	kept := topCovisitScores(decayed, s.maxNeighbors)
+	for neighbor := range scores {
+		if _, ok := kept[neighbor]; !ok {
+			delete(scores, neighbor)
+		}
+	}
+}
+
+func (s *ItemCovisitMemoryStorage) Neighbors(itemIds []string, size int, timestamp int64) (map[string]map[string]float64, error) {
+	ret := make(map[string]map[string]float64, len(itemIds))
+	for _, itemId := range itemIds {
+		shard := s.shard(itemId)
+		shard.mu.RLock()
+		scores := shard.pairs[itemId]
+		decayed := make(map[string]float64, len(scores))
+		for neighbor, score := range scores {
+			decayed[neighbor] = score.score * covisitDecay(timestamp-score.timestamp, s.halfLife)
+		}
+		shard.mu.RUnlock()
+
+		if len(decayed) > 0 {
+			ret[itemId] = topCovisitScores(decayed, size)
+		}
+	}
+
+	return ret, nil
+}
+
+// written counts the write of the shard, and removes the expired entries of the shard by covisitCleanupCount writes,
+// the shard should be locked by the caller
+func (s *ItemCovisitMemoryStorage) written(shard *covisitShard, timestamp int64) {
+	shard.writes++
+	if shard.writes < covisitCleanupCount {
+		return
+	}
+	shard.writes = 0
+
+	deadline := timestamp - s.expire
+	for userId, events := range shard.userItems {
+		if len(events) == 0 || events[len(events)-1].Timestamp < deadline {
+			delete(shard.userItems, userId)
+		}
+	}
+	for itemId, users := range shard.itemUsers {
+		if len(users) == 0 || users[len(users)-1].timestamp < deadline {
+			delete(shard.itemUsers, itemId)
+		}
+	}
+	for itemId, scores := range shard.pairs {
+		for neighbor, score := range scores {
+			if score.timestamp < deadline {
+				delete(scores, neighbor)
+			}
+		}
+		if len(scores) == 0 {
+			delete(shard.pairs, itemId)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package module
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/alibaba/pairec/v2/persist/redisdb"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+// covisitEpochHalfLives is the count of the half lives of the epoch of the keys of the pair scores
+const covisitEpochHalfLives = 16
+
+// ItemCovisitRedisStorage keeps the statistics in the sorted sets of redis. The pair scores are decayed forward,
+// the weight is added as weight*2^((t-epochStart)/halfLife) to the key of the epoch, so the order of the sorted set is the order of the decayed scores,
+// and the neighbors are merged from the keys of the current and the previous epoch, the older ones are expired.
+type ItemCovisitRedisStorage struct {
+	redis         *redisdb.Redis
+	prefix        string
+	halfLife      int64
+	expire        int64
+	userItemCount int
+	itemUserCount int
+	maxNeighbors  int
+}
+
+func NewItemCovisitRedisStorage(config recconf.OnlineI2IConfig) *ItemCovisitRedisStorage {
+	config = defaultOnlineI2IConfig(config)
+	redis, err := redisdb.GetRedis(config.DaoConf.RedisName)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ItemCovisitRedisStorage{
+		redis:         redis,
+		prefix:        config.DaoConf.RedisPrefix,
+		halfLife:      int64(config.HalfLife),
+		expire:        int64(config.Expire),
+		userItemCount: config.UserItemCount,
+		itemUserCount: config.ItemUserCount,
+		maxNeighbors:  config.MaxNeighbors,
+	}
+}
+
+func (s *ItemCovisitRedisStorage) userKey(userId string) string {
+	return s.prefix + "u_" + userId
+}
+
+func (s *ItemCovisitRedisStorage) itemUserKey(itemId string) string {
+	return s.prefix + "iu_" + itemId
+}
+
+func (s *ItemCovisitRedisStorage) epochLength() int64 {
+	return s.halfLife * covisitEpochHalfLives
+}
+
+func (s *ItemCovisitRedisStorage) pairKey(itemId string, epoch int64) string {
+	return fmt.Sprintf("%si2i_%s_%d", s.prefix, itemId, epoch)
+}
+
+func (s *ItemCovisitRedisStorage) UserItems(userIds []string) (map[string][]*ItemCovisitEvent, error) {
+	conn := s.redis.Get()
+	defer conn.Close()
+
+	for _, userId := range userIds {
+		if err := conn.Send("ZRANGE", s.userKey(userId), 0, -1, "WITHSCORES"); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]*ItemCovisitEvent, len(userIds))
+	for _, userId := range userIds {
+		values, err := redis.Strings(conn.Receive())
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i+1 < len(values); i += 2 {
+			timestamp, _ := strconv.ParseInt(values[i+1], 10, 64)
+			ret[userId] = append(ret[userId], &ItemCovisitEvent{ItemId: values[i], Timestamp: timestamp})
+		}
+	}
+
+	return ret, nil
+}
+
+func (s *ItemCovisitRedisStorage) ItemUsers(itemIds []string) (map[string][]string, error) {
+	conn := s.redis.Get()
+	defer conn.Close()
+
+	for _, itemId := range itemIds {
+		if err := conn.Send("ZRANGE", s.itemUserKey(itemId), 0, -1); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]string, len(itemIds))
+	for _, itemId := range itemIds {
+		users, err := redis.Strings(conn.Receive())
+		if err != nil {
+			return nil, err
+		}
+		if len(users) > 0 {
+			ret[itemId] = users
+		}
+	}
+
+	return ret, nil
+}
+
+func (s *ItemCovisitRedisStorage) AddEvents(userId string, events []*ItemCovisitEvent) error {
+	conn := s.redis.Get()
+	defer conn.Close()
+
+	userKey := s.userKey(userId)
+	commands := 0
+	for _, event := range events {
+		conn.Send("ZADD", userKey, event.Timestamp, event.ItemId)
+		itemUserKey := s.itemUserKey(event.ItemId)
+		conn.Send("ZADD", itemUserKey, event.Timestamp, userId)
+		conn.Send("ZREMRANGEBYRANK", itemUserKey, 0, -(s.itemUserCount + 1))
+		conn.Send("EXPIRE", itemUserKey, s.expire)
+		commands += 4
+	}
+	conn.Send("ZREMRANGEBYRANK", userKey, 0, -(s.userItemCount + 1))
+	conn.Send("EXPIRE", userKey, s.expire)
+	commands += 2
+
+	return receiveCovisitReplies(conn, commands)
+}
+
+func (s *ItemCovisitRedisStorage) IncrPairs(pairs map[string]map[string]float64, timestamp int64) error {
+	conn := s.redis.Get()
+	defer conn.Close()
+
+	epoch := timestamp / s.epochLength()
+	scale := math.Exp2(float64(timestamp-epoch*s.epochLength()) / float64(s.halfLife))
+	commands := 0
+	for itemId, neighbors := range pairs {
+		key := s.pairKey(itemId, epoch)
+		for neighbor, weight := range neighbors {
+			conn.Send("ZINCRBY", key, weight*scale, neighbor)
+			commands++
+		}
+		conn.Send("ZREMRANGEBYRANK", key, 0, -(s.maxNeighbors + 1))
+		conn.Send("EXPIRE", key, 2*s.epochLength())
+		commands += 2
+	}
+
+	return receiveCovisitReplies(conn, commands)
+}
+
+func (s *ItemCovisitRedisStorage) Neighbors(itemIds []string, size int, timestamp int64) (map[string]map[string]float64, error) {
+	conn := s.redis.Get()
+	defer conn.Close()
+
+	epoch := timestamp / s.epochLength()
+	epochs := []int64{epoch, epoch - 1}
+	for _, itemId := range itemIds {
+		for _, e := range epochs {
+			if err := conn.Send("ZREVRANGE", s.pairKey(itemId, e), 0, size-1, "WITHSCORES"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]map[string]float64, len(itemIds))
+	for _, itemId := range itemIds {
+		scores := make(map[string]float64)
+		for _, e := range epochs {
+			values, err := redis.Strings(conn.Receive())
+			if err != nil {
+				return nil, err
+			}
+			scale := math.Exp2(float64(timestamp-e*s.epochLength()) / float64(s.halfLife))
+			for i := 0; i+1 < len(values); i += 2 {
+				score, err := strconv.ParseFloat(values[i+1], 64)
+				if err != nil {
+					continue
+				}
+				scores[values[i]] += score / scale
+			}
+		}
+		if len(scores) > 0 {
+			ret[itemId] = topCovisitScores(scores, size)
+		}
+	}
+
+	return ret, nil
+}
+
+// receiveCovisitReplies flushes the commands sent and receives the replies, the first error is returned
+func receiveCovisitReplies(conn redis.Conn, commands int) error {
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+
+	var ret error
+	for i := 0; i < commands; i++ {
+		if _, err := conn.Receive(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+
+	return ret
+}
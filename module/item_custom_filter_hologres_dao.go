@@ -23,8 +23,7 @@ type ItemCustomFilterHoloDao struct {
 func NewItemCustomFilterHoloDao(config recconf.FilterConfig) *ItemCustomFilterHoloDao {
 	postgres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &ItemCustomFilterHoloDao{
 		db:           postgres.DB,
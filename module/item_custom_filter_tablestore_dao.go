@@ -23,8 +23,7 @@ func NewItemCustomFilterTableStoreDao(config recconf.FilterConfig) *ItemCustomFi
 	}
 	tablestore, err := tablestoredb.GetTableStore(config.DaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	dao.table = config.DaoConf.TableStoreTableName
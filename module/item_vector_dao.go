@@ -0,0 +1,143 @@
+package module
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+
+	"github.com/alibaba/pairec/v2/persist/holo"
+	"github.com/alibaba/pairec/v2/persist/mysqldb"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// ItemVector is the vector of the item loaded by the ItemVectorDao
+type ItemVector struct {
+	Id     string
+	Vector []float32
+}
+
+// ItemVectorDao loads the vectors of all the items, such as building the in-process vector index
+type ItemVectorDao interface {
+	ItemVectors() ([]*ItemVector, error)
+}
+
+// NewItemVectorDao creates the dao of the local file when filePath is set, otherwise the dao of the table of the config
+func NewItemVectorDao(filePath string, config recconf.VectorDaoConfig) ItemVectorDao {
+	if filePath != "" {
+		return NewItemVectorFileDao(filePath)
+	}
+
+	switch config.AdapterType {
+	case recconf.DaoConf_Adapter_Hologres:
+		hologres, err := holo.GetPostgres(config.HologresName)
+		if err != nil {
+			panic(err)
+		}
+		return NewItemVectorSqlDao(hologres.DB, sqlbuilder.PostgreSQL, config.HologresTableName, config.KeyField, config.EmbeddingField)
+	case recconf.DaoConf_Adapter_Mysql:
+		mysql, err := mysqldb.GetMysql(config.MysqlName)
+		if err != nil {
+			panic(err)
+		}
+		return NewItemVectorSqlDao(mysql.DB, sqlbuilder.MySQL, config.MysqlTable, config.KeyField, config.EmbeddingField)
+	}
+
+	panic("not found ItemVectorDao implement")
+}
+
+// ItemVectorFileDao loads the item vectors of the local file, each line is the item id and the vector separated by the tab
+type ItemVectorFileDao struct {
+	filePath string
+}
+
+func NewItemVectorFileDao(filePath string) *ItemVectorFileDao {
+	return &ItemVectorFileDao{filePath: filePath}
+}
+
+func (d *ItemVectorFileDao) ItemVectors() ([]*ItemVector, error) {
+	file, err := os.Open(d.filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var vectors []*ItemVector
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("item vector file %s line %d: expect the item id and the vector separated by the tab", d.filePath, lineNum)
+		}
+		vector, err := utils.ParseVector(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("item vector file %s line %d: %v", d.filePath, lineNum, err)
+		}
+
+		vectors = append(vectors, &ItemVector{Id: fields[0], Vector: vector})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
+
+// ItemVectorSqlDao loads the item vectors of the table, the embedding field is the text of the vector or the float array of hologres
+type ItemVectorSqlDao struct {
+	db             *sql.DB
+	flavor         sqlbuilder.Flavor
+	table          string
+	keyField       string
+	embeddingField string
+}
+
+func NewItemVectorSqlDao(db *sql.DB, flavor sqlbuilder.Flavor, table, keyField, embeddingField string) *ItemVectorSqlDao {
+	return &ItemVectorSqlDao{
+		db:             db,
+		flavor:         flavor,
+		table:          table,
+		keyField:       keyField,
+		embeddingField: embeddingField,
+	}
+}
+
+func (d *ItemVectorSqlDao) ItemVectors() ([]*ItemVector, error) {
+	builder := d.flavor.NewSelectBuilder()
+	builder.Select(d.keyField, d.embeddingField)
+	builder.From(d.table)
+
+	query, args := builder.Build()
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vectors []*ItemVector
+	for rows.Next() {
+		var id, embedding string
+		if err := rows.Scan(&id, &embedding); err != nil {
+			return nil, err
+		}
+		vector, err := utils.ParseVector(embedding)
+		if err != nil {
+			return nil, fmt.Errorf("item vector of %s: %v", id, err)
+		}
+
+		vectors = append(vectors, &ItemVector{Id: id, Vector: vector})
+	}
+
+	return vectors, rows.Err()
+}
@@ -53,16 +53,14 @@ func NewRealtimeUser2ItemBeDao(config recconf.RecallConfig) *RealtimeUser2ItemBe
 
 	client, err := beengine.GetBeClient(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.BeName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.beClient = client.BeClient
 
 	expression, err := govaluate.NewEvaluableExpressionWithFunctions(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.WeightExpression,
 		govaluateFunctions)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao.weightEvaluableExpression = expression
@@ -69,16 +69,14 @@ func NewRealtimeUser2ItemHologresDao(config recconf.RecallConfig) *RealtimeUser2
 
 	hologres, err := holo.GetPostgres(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 
 	expression, err := govaluate.NewEvaluableExpressionWithFunctions(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.WeightExpression,
 		govaluateFunctions)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao.weightEvaluableExpression = expression
@@ -152,7 +150,7 @@ func (d *RealtimeUser2ItemHologresDao) ListItemsByUser(user *User, context *cont
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -247,7 +245,7 @@ func (d *RealtimeUser2ItemHologresDao) GetTriggerInfos(user *User, context *cont
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
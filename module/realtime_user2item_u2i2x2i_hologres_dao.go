@@ -58,16 +58,14 @@ func NewRealtimeUser2Item2X2ItemHologresDao(config recconf.RecallConfig) *Realti
 
 	hologres, err := holo.GetPostgres(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 
 	expression, err := govaluate.NewEvaluableExpressionWithFunctions(config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.WeightExpression,
 		govaluateFunctions)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao.weightEvaluableExpression = expression
@@ -140,7 +138,7 @@ func (d *RealtimeUser2Item2X2ItemHologresDao) ListItemsByUser(user *User, contex
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 
 	rows, err := stmt.QueryContext(ctx, args...)
@@ -216,7 +214,7 @@ func (d *RealtimeUser2Item2X2ItemHologresDao) ListItemsByUser(user *User, contex
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel = gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel = gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 
 	rows, err = stmt.QueryContext(ctx, args...)
@@ -306,7 +304,7 @@ func (d *RealtimeUser2Item2X2ItemHologresDao) GetTriggerInfos(user *User, contex
 			d.mu.Unlock()
 		}
 	}
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.userStmt.QueryContext(ctx, args...)
 	if err != nil {
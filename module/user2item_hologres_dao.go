@@ -29,8 +29,7 @@ type User2ItemHologresDao struct {
 func NewUser2ItemHologresDao(config recconf.RecallConfig) *User2ItemHologresDao {
 	hologres, err := holo.GetPostgres(config.User2ItemDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &User2ItemHologresDao{
 		db:         hologres.DB,
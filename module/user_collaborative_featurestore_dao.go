@@ -26,8 +26,7 @@ type UserCollaborativeFeatureStoreDao struct {
 func NewUserCollaborativeFeatureStoreDao(config recconf.RecallConfig) *UserCollaborativeFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.UserCollaborativeDaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &UserCollaborativeFeatureStoreDao{
 		fsClient:   fsclient,
@@ -36,8 +36,7 @@ func NewUserCollaborativeHologresDao(config recconf.RecallConfig) *UserCollabora
 	}
 	hologres, err := holo.GetPostgres(config.UserCollaborativeDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 	dao.userTable = config.UserCollaborativeDaoConf.User2ItemTable
@@ -161,7 +160,7 @@ func (d *UserCollaborativeHologresDao) ListItemsByUser(user *User, context *cont
 							d.mu.Unlock()
 						}
 					}
-					ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 200*time.Millisecond)
+					ctx, cancel := gocontext.WithTimeout(context.Context(), 200*time.Millisecond)
 					defer cancel()
 					rows, err := stmt.QueryContext(ctx, args...)
 					if err != nil {
@@ -32,8 +32,7 @@ func NewUserCollaborativeMysqlDao(config recconf.RecallConfig) *UserCollaborativ
 	dao := &UserCollaborativeMysqlDao{}
 	mysql, err := mysqldb.GetMysql(config.UserCollaborativeDaoConf.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	dao.userTable = config.UserCollaborativeDaoConf.User2ItemTable
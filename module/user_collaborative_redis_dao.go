@@ -24,8 +24,7 @@ type UserCollaborativeRedisDao struct {
 func NewUserCollaborativeRedisDao(config recconf.RecallConfig) *UserCollaborativeRedisDao {
 	redisIns, err := redisdb.GetRedis(config.DaoConf.RedisName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserCollaborativeRedisDao{
@@ -42,11 +41,15 @@ func NewUserCollaborativeRedisDao(config recconf.RecallConfig) *UserCollaborativ
 }
 
 func (d *UserCollaborativeRedisDao) ListItemsByUser(user *User, context *context.RecommendContext) (ret []*Item) {
-	conn := d.redis.Get()
-	defer conn.Close()
 	uid := string(user.Id)
+	conn, err := d.redis.GetContext(context.Context())
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=UserCollaborativeRedisDao\tuid=%s\terror=%v", context.RecommendId, uid, err))
+		return
+	}
+	defer conn.Close()
 	key := d.prefix + uid
-	value, err := redis.String(conn.Do("GET", key))
+	value, err := redis.String(d.redis.DoContext(context.Context(), conn, "GET", key))
 	if err != nil {
 		log.Error(fmt.Sprintf("requestId=%s\tmodule=UserCollaborativeRedisDao\tuid=%s\terror=%v", context.RecommendId, uid, err))
 		return
@@ -104,7 +107,7 @@ func (d *UserCollaborativeRedisDao) ListItemsByUser(user *User, context *context
 			for {
 				select {
 				case ids := <-itemIdCh:
-					res, err := conn.Do("MGET", ids...)
+					res, err := d.redis.DoContext(context.Context(), conn, "MGET", ids...)
 					if err != nil {
 						log.Error(fmt.Sprintf("requestId=%s\tmodule=UserCollaborativeRedisDao\tuid=%s\terror=%v", context.RecommendId, uid, err))
 						goto LOOP
@@ -158,11 +161,15 @@ func (d *UserCollaborativeRedisDao) GetTriggers(user *User, context *context.Rec
 }
 
 func (d *UserCollaborativeRedisDao) GetTriggerInfos(user *User, context *context.RecommendContext) (triggerInfos []*TriggerInfo) {
-	conn := d.redis.Get()
-	defer conn.Close()
 	uid := string(user.Id)
+	conn, err := d.redis.GetContext(context.Context())
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=UserCollaborativeRedisDao\tuid=%s\terror=%v", context.RecommendId, uid, err))
+		return
+	}
+	defer conn.Close()
 	key := d.prefix + uid
-	value, err := redis.String(conn.Do("GET", key))
+	value, err := redis.String(d.redis.DoContext(context.Context(), conn, "GET", key))
 	if err != nil {
 		log.Error(fmt.Sprintf("requestId=%s\tmodule=UserCollaborativeRedisDao\tuid=%s\terror=%v", context.RecommendId, uid, err))
 		return
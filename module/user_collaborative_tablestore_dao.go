@@ -28,8 +28,7 @@ func NewUserCollaborativeTableStoreDao(config recconf.RecallConfig) *UserCollabo
 	dao := &UserCollaborativeTableStoreDao{}
 	tablestore, err := tablestoredb.GetTableStore(config.UserCollaborativeDaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	dao.userTable = config.UserCollaborativeDaoConf.User2ItemTable
@@ -41,8 +41,7 @@ func NewUserU2I2X2IHologresDao(config recconf.RecallConfig) *UserU2I2X2IHologres
 	}
 	hologres, err := holo.GetPostgres(config.UserCollaborativeDaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 	dao.userTable = config.UserCollaborativeDaoConf.User2ItemTable
@@ -178,7 +177,7 @@ func (d *UserU2I2X2IHologresDao) ListItemsByUser(user *User, context *context.Re
 							d.mu.Unlock()
 						}
 					}
-					ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 200*time.Millisecond)
+					ctx, cancel := gocontext.WithTimeout(context.Context(), 200*time.Millisecond)
 					defer cancel()
 					rows, err := stmt.QueryContext(ctx, args...)
 					if err != nil {
@@ -287,7 +286,7 @@ func (d *UserU2I2X2IHologresDao) ListItemsByUser(user *User, context *context.Re
 							d.mu.Unlock()
 						}
 					}
-					ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 200*time.Millisecond)
+					ctx, cancel := gocontext.WithTimeout(context.Context(), 200*time.Millisecond)
 					defer cancel()
 					rows, err := stmt.QueryContext(ctx, args...)
 					if err != nil {
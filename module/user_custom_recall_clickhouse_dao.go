@@ -28,8 +28,7 @@ type UserCustomRecallClickHouseDao struct {
 func NewUserCustomRecallClickHouseDao(config recconf.RecallConfig) *UserCustomRecallClickHouseDao {
 	clickhouseDB, err := clickhouse.GetClickHouse(config.DaoConf.ClickHouseName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserCustomRecallClickHouseDao{
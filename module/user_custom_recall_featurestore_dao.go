@@ -23,8 +23,7 @@ type UserCustomRecallFeatureStoreDao struct {
 func NewUserCustomRecallFeatureStoreDao(config recconf.RecallConfig) *UserCustomRecallFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.DaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserCustomRecallFeatureStoreDao{
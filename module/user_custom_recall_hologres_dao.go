@@ -28,8 +28,7 @@ type UserCustomRecallHologresDao struct {
 func NewUserCustomRecallHologresDao(config recconf.RecallConfig) *UserCustomRecallHologresDao {
 	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserCustomRecallHologresDao{
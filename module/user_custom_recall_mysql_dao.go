@@ -30,8 +30,7 @@ func NewUserCustomRecallMysqlDao(config recconf.RecallConfig) *UserCustomRecallM
 	}
 	mysql, err := mysqldb.GetMysql(config.DaoConf.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	dao.table = config.DaoConf.MysqlTable
@@ -24,8 +24,7 @@ type UserCustomRecallRedisDao struct {
 func NewUserCustomRecallRedisDao(config recconf.RecallConfig) *UserCustomRecallRedisDao {
 	redis, err := redisdb.GetRedis(config.DaoConf.RedisName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserCustomRecallRedisDao{
@@ -27,8 +27,7 @@ func NewUserCustomRecallTableStoreDao(config recconf.RecallConfig) *UserCustomRe
 	}
 	tablestore, err := tablestoredb.GetTableStore(config.DaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	dao.table = config.DaoConf.TableStoreTableName
@@ -26,8 +26,7 @@ type UserGlobalHotRecallFeatureStoreDao struct {
 func NewUserGlobalHotRecallFeatureStoreDao(config recconf.RecallConfig) *UserGlobalHotRecallFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.DaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserGlobalHotRecallFeatureStoreDao{
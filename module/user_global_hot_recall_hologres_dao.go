@@ -31,8 +31,7 @@ type UserGlobalHotRecallHologresDao struct {
 func NewUserGlobalHotRecallHologresDao(config recconf.RecallConfig) *UserGlobalHotRecallHologresDao {
 	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserGlobalHotRecallHologresDao{
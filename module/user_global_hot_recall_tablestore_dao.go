@@ -25,8 +25,7 @@ type UserGlobalHotRecallTableStoreDao struct {
 func NewUserGlobalHotRecallTableStoreDao(config recconf.RecallConfig) *UserGlobalHotRecallTableStoreDao {
 	tablestore, err := tablestoredb.GetTableStore(config.DaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserGlobalHotRecallTableStoreDao{
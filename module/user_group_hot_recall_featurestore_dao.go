@@ -24,8 +24,7 @@ type UserGroupHotRecallFeatureStoreDao struct {
 func NewUserGroupHotRecallFeatureStoreDao(config recconf.RecallConfig) *UserGroupHotRecallFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.DaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserGroupHotRecallFeatureStoreDao{
@@ -29,8 +29,7 @@ type UserGroupHotRecallHologresDao struct {
 func NewUserGroupHotRecallHologresDao(config recconf.RecallConfig) *UserGroupHotRecallHologresDao {
 	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao := &UserGroupHotRecallHologresDao{
@@ -18,8 +18,7 @@ type User2ItemCustomFilterFeatureStoreDao struct {
 func NewUser2ItemCustomFilterFeatureStoreDao(config recconf.FilterConfig) *User2ItemCustomFilterFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.DaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &User2ItemCustomFilterFeatureStoreDao{
 		fsClient: fsclient,
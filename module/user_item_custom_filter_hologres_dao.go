@@ -26,8 +26,7 @@ type User2ItemCustomFilterHologresDao struct {
 func NewUser2ItemCustomFilterHologresDao(config recconf.FilterConfig) *User2ItemCustomFilterHologresDao {
 	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &User2ItemCustomFilterHologresDao{
 		db:    hologres.DB,
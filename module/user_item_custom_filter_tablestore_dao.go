@@ -20,8 +20,7 @@ func NewUser2ItemCustomFilterTableStoreDao(config recconf.FilterConfig) *User2It
 	dao := &User2ItemCustomFilterTableStoreDao{}
 	tablestore, err := tablestoredb.GetTableStore(config.DaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	dao.table = config.DaoConf.TableStoreTableName
@@ -32,8 +32,7 @@ func NewUser2ItemExposureBeDao(config recconf.FilterConfig) *User2ItemExposureBe
 	}
 	client, err := beengine.GetBeClient(config.DaoConf.BeName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.beClient = client.BeClient
 	dao.table = config.DaoConf.BeTableName
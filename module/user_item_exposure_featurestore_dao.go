@@ -25,8 +25,7 @@ type User2ItemExposureFeatureStoreDao struct {
 func NewUser2ItemExposureFeatureStoreDao(config recconf.FilterConfig) *User2ItemExposureFeatureStoreDao {
 	fsclient, err := fs.GetFeatureStoreClient(config.DaoConf.FeatureStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("error=%v", err))
-		return nil
+		panic(err)
 	}
 	dao := &User2ItemExposureFeatureStoreDao{
 		timeInterval:             0,
@@ -61,8 +61,7 @@ func NewUser2ItemExposureHologresDao(config recconf.FilterConfig) *User2ItemExpo
 	}
 	hologres, err := holo.GetPostgres(config.DaoConf.HologresName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = hologres.DB
 	dao.table = config.DaoConf.HologresTableName
@@ -248,7 +247,7 @@ func (d *User2ItemExposureHologresDao) GetExposureItemIds(user *User, context *c
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.selectStmt.QueryContext(ctx, args...)
 	if err != nil {
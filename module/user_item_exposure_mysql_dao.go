@@ -35,15 +35,13 @@ func NewUser2ItemExposureMysqlDao(config recconf.FilterConfig) *User2ItemExposur
 	}
 	mysql, err := mysqldb.GetMysql(config.DaoConf.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	dao.table = config.DaoConf.MysqlTable
 	if len(config.DaoConf.Config) > 0 {
 		if err := json.Unmarshal([]byte(config.DaoConf.Config), &dao.configs); err != nil {
-			log.Error(fmt.Sprintf("error=%v", err))
-			return nil
+			panic(err)
 		}
 		if _, ok := dao.configs["table_prefix"]; ok {
 			dao.tablePrefix = dao.configs["table_prefix"].(string)
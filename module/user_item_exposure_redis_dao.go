@@ -31,8 +31,7 @@ func NewUser2ItemExposureRedisDao(config recconf.FilterConfig) *User2ItemExposur
 	}
 	redis, err := redisdb.GetRedis(config.DaoConf.RedisName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 
 	dao.redis = redis
@@ -164,10 +163,14 @@ func (d *User2ItemExposureRedisDao) GetExposureItemIds(user *User, context *cont
 	uid := string(user.Id)
 	prefix := d.prefix
 	key := prefix + string(uid)
-	conn := d.redis.Get()
+	conn, err := d.redis.GetContext(context.Context())
+	if err != nil {
+		log.Error(fmt.Sprintf("module=User2ItemExposureRedisDao\tuid=%s\terr=%v", uid, err))
+		return
+	}
 	defer conn.Close()
 
-	bytes, err := redis.ByteSlices(conn.Do("LRANGE", key, 0, d.maxItems-1))
+	bytes, err := redis.ByteSlices(d.redis.DoContext(context.Context(), conn, "LRANGE", key, 0, d.maxItems-1))
 	if err != nil {
 		log.Error(fmt.Sprintf("module=User2ItemExposureRedisDao\tuid=%s\terr=%v", uid, err))
 		return
@@ -32,8 +32,7 @@ func NewUser2ItemExposureTableStoreDao(config recconf.FilterConfig) *User2ItemEx
 	}
 	tablestore, err := tablestoredb.GetTableStore(config.DaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	dao.table = config.DaoConf.TableStoreTableName
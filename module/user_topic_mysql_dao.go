@@ -42,8 +42,7 @@ func NewUserTopicMysqlDao(config recconf.RecallConfig) *UserTopicMysqlDao {
 	}
 	mysql, err := mysqldb.GetMysql(config.UserTopicDaoConf.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	return dao
@@ -40,8 +40,7 @@ func NewUserTopicTableStoreDao(config recconf.RecallConfig) *UserTopicTableStore
 	}
 	tablestore, err := tablestoredb.GetTableStore(config.UserTopicDaoConf.TableStoreName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.tablestore = tablestore
 	return dao
@@ -32,8 +32,7 @@ func NewUserVideoCollaborativeMysqlDao(config recconf.RecallConfig) *UserVideoCo
 	dao := &UserVideoCollaborativeMysqlDao{}
 	mysql, err := mysqldb.GetMysql(config.UserCollaborativeDaoConf.MysqlName)
 	if err != nil {
-		log.Error(fmt.Sprintf("%v", err))
-		return nil
+		panic(err)
 	}
 	dao.db = mysql.DB
 	dao.userTable = config.UserCollaborativeDaoConf.User2ItemTable
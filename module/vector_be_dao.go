@@ -5,7 +5,6 @@ import (
 
 	be "github.com/aliyun/aliyun-be-go-sdk"
 	"github.com/alibaba/pairec/v2/datasource/beengine"
-	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -19,8 +18,7 @@ type VectorBeDao struct {
 func NewVectorBeDao(config recconf.RecallConfig) *VectorBeDao {
 	client, err := beengine.GetBeClient(config.VectorDaoConf.BeName)
 	if err != nil {
-		log.Error(fmt.Sprintf("get beclient error:%v", err))
-		return nil
+		panic(fmt.Errorf("get beclient error:%w", err))
 	}
 
 	dao := &VectorBeDao{
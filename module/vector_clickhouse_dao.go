@@ -25,8 +25,7 @@ type VectorClickHouseDao struct {
 func NewVectorClickHouseDao(config recconf.RecallConfig) *VectorClickHouseDao {
 	clickhouseDB, err := clickhouse.GetClickHouse(config.VectorDaoConf.ClickHouseName)
 	if err != nil {
-		log.Error(fmt.Sprintf("get clickhouse error:%v", err))
-		return nil
+		panic(fmt.Errorf("get clickhouse error:%w", err))
 	}
 
 	dao := &VectorClickHouseDao{
@@ -40,6 +39,11 @@ func NewVectorClickHouseDao(config recconf.RecallConfig) *VectorClickHouseDao {
 }
 
 func (d *VectorClickHouseDao) VectorString(id string) (string, error) {
+	return d.VectorStringWithContext(context.Background(), id)
+}
+
+// VectorStringWithContext returns vector of string, the query is bounded by the deadline of ctx
+func (d *VectorClickHouseDao) VectorStringWithContext(ctx context.Context, id string) (string, error) {
 	builder := sqlbuilder.MySQL.NewSelectBuilder()
 	builder.Select(d.embeddingField)
 	builder.From(d.table)
@@ -61,7 +65,7 @@ func (d *VectorClickHouseDao) VectorString(id string) (string, error) {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.dbStmt.QueryContext(ctx, args...)
 	if err != nil {
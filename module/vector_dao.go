@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"errors"
 
 	"github.com/alibaba/pairec/v2/recconf"
@@ -14,6 +15,20 @@ type VectorDao interface {
 	VectorString(id string) (string, error)
 }
 
+// ContextVectorDao is implemented by the VectorDao which can be bounded by the request deadline
+type ContextVectorDao interface {
+	VectorStringWithContext(ctx context.Context, id string) (string, error)
+}
+
+// VectorStringWithContext fetch the vector by the dao, use the ctx if the dao supports it
+func VectorStringWithContext(ctx context.Context, dao VectorDao, id string) (string, error) {
+	if ctxDao, ok := dao.(ContextVectorDao); ok {
+		return ctxDao.VectorStringWithContext(ctx, id)
+	}
+
+	return dao.VectorString(id)
+}
+
 func NewVectorDao(config recconf.RecallConfig) VectorDao {
 	if config.DaoConf.AdapterType == recconf.DaoConf_Adapter_Redis {
 		return NewVectorRedisDao(config)
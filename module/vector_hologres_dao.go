@@ -65,6 +65,11 @@ func NewVectorHologresDao(config recconf.RecallConfig) *VectorHologresDao {
 
 // returns vector of string
 func (d *VectorHologresDao) VectorString(id string) (string, error) {
+	return d.VectorStringWithContext(context.Background(), id)
+}
+
+// VectorStringWithContext returns vector of string, the query is bounded by the deadline of ctx
+func (d *VectorHologresDao) VectorStringWithContext(ctx context.Context, id string) (string, error) {
 	builder := sqlbuilder.PostgreSQL.NewSelectBuilder()
 	builder.Select(d.embeddingField)
 	builder.From(d.table)
@@ -86,7 +91,7 @@ func (d *VectorHologresDao) VectorString(id string) (string, error) {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.dbStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -40,6 +40,11 @@ func NewVectorMysqlDao(config recconf.RecallConfig) *VectorMysqlDao {
 }
 
 func (d *VectorMysqlDao) VectorString(id string) (string, error) {
+	return d.VectorStringWithContext(context.Background(), id)
+}
+
+// VectorStringWithContext returns vector of string, the query is bounded by the deadline of ctx
+func (d *VectorMysqlDao) VectorStringWithContext(ctx context.Context, id string) (string, error) {
 	builder := sqlbuilder.MySQL.NewSelectBuilder()
 	builder.Select(d.embeddingField)
 	builder.From(d.table)
@@ -61,7 +66,7 @@ func (d *VectorMysqlDao) VectorString(id string) (string, error) {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
 	defer cancel()
 	rows, err := d.dbStmt.QueryContext(ctx, args...)
 	if err != nil {
@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"errors"
 
 	"github.com/gomodule/redigo/redis"
@@ -27,16 +28,24 @@ func NewVectorRedisDao(config recconf.RecallConfig) *VectorRedisDao {
 	return dao
 }
 func (d *VectorRedisDao) VectorString(id string) (string, error) {
-	conn := d.redis.Get()
+	return d.VectorStringWithContext(context.Background(), id)
+}
+
+// VectorStringWithContext returns vector of string, the redis call is bounded by the deadline of ctx
+func (d *VectorRedisDao) VectorStringWithContext(ctx context.Context, id string) (string, error) {
+	conn, err := d.redis.GetContext(ctx)
+	if err != nil {
+		return "", err
+	}
 	defer conn.Close()
 	// key := fmt.Sprintf("UI2V_%s", user.Id)
 	key := d.prefix + string(id)
-	value, err := redis.String(conn.Do("GET", key))
+	value, err := redis.String(d.redis.DoContext(ctx, conn, "GET", key))
 	if err != nil {
 		if !errors.Is(err, redis.ErrNil) {
 			return "", err
 		} else if d.defaultKey != "" {
-			value, err = redis.String(conn.Do("GET", d.defaultKey))
+			value, err = redis.String(d.redis.DoContext(ctx, conn, "GET", d.defaultKey))
 			if err != nil && !errors.Is(err, redis.ErrNil) {
 				return "", err
 			}
@@ -1,42 +1,23 @@
 package pairec
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 
 	"github.com/alibaba/pairec/v2/abtest"
-	"github.com/alibaba/pairec/v2/algorithm"
 	"github.com/alibaba/pairec/v2/config"
-	"github.com/alibaba/pairec/v2/datasource/beengine"
 	"github.com/alibaba/pairec/v2/datasource/datahub"
-	"github.com/alibaba/pairec/v2/datasource/graph"
-	"github.com/alibaba/pairec/v2/datasource/ha3engine"
-	"github.com/alibaba/pairec/v2/datasource/hbase"
-	"github.com/alibaba/pairec/v2/datasource/hbase_thrift"
 	"github.com/alibaba/pairec/v2/datasource/kafka"
-	"github.com/alibaba/pairec/v2/datasource/opensearch"
 	"github.com/alibaba/pairec/v2/datasource/sls"
-	"github.com/alibaba/pairec/v2/filter"
-	"github.com/alibaba/pairec/v2/persist/clickhouse"
-	"github.com/alibaba/pairec/v2/persist/fs"
-	"github.com/alibaba/pairec/v2/persist/holo"
-	"github.com/alibaba/pairec/v2/persist/lindorm"
-	"github.com/alibaba/pairec/v2/persist/mysqldb"
-	"github.com/alibaba/pairec/v2/persist/redisdb"
-	"github.com/alibaba/pairec/v2/persist/tablestoredb"
+	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
-	"github.com/alibaba/pairec/v2/service"
-	"github.com/alibaba/pairec/v2/service/fallback"
-	"github.com/alibaba/pairec/v2/service/feature"
-	"github.com/alibaba/pairec/v2/service/general_rank"
+	"github.com/alibaba/pairec/v2/service/debug"
 	"github.com/alibaba/pairec/v2/service/metrics"
-	"github.com/alibaba/pairec/v2/service/pipeline"
-	"github.com/alibaba/pairec/v2/service/rank"
-	"github.com/alibaba/pairec/v2/service/recall/berecall"
-	"github.com/alibaba/pairec/v2/sort"
 	"github.com/alibaba/pairec/v2/web"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -46,17 +27,26 @@ import (
 type hookfunc func() error
 
 var (
-	hooks = make([]hookfunc, 0)
+	hooks         = make([]hookfunc, 0)
+	shutdownHooks = make([]hookfunc, 0)
 )
 var configFile string
+var watchConfig bool
 
 func init() {
 	flag.StringVar(&configFile, "config", "", "config file path")
+	flag.BoolVar(&watchConfig, "watch-config", false, "reload the config when the local config file changes")
 	flag.BoolVar(&config.AppConfig.WarmUpData, "warm-up-data", false, "create eas warm up data flag")
 }
 func AddStartHook(hf ...hookfunc) {
 	hooks = append(hooks, hf...)
 }
+
+// AddShutdownHook adds the hooks run in order after the in-flight requests are drained on shutdown,
+// they run before the built-in data sinks are flushed
+func AddShutdownHook(hf ...hookfunc) {
+	shutdownHooks = append(shutdownHooks, hf...)
+}
 func Run() {
 	mode := os.Getenv("RUN_MODE")
 	if mode != "COMMAND" {
@@ -79,48 +69,42 @@ func Run() {
 
 	registerRouteInfo()
 
+	// the server starts before the start hooks, so /ping works while warming up and /ready flips after the hooks finish,
+	// the other routes and the grpc methods return 503 or Unavailable until then
+	PairecApp.Start()
+
 	runStartHook()
 
-	PairecApp.Run()
+	// the local config file is watched after the start hooks load the initial config
+	if configName == "" && watchConfig {
+		filePath := configFile
+		if filePath == "" {
+			filePath = os.Getenv("CONFIG_PATH")
+		}
+		if err := WatchConfigFile(filePath); err != nil {
+			panic(err)
+		}
+	}
+
+	PairecApp.SetReady(true)
+
+	PairecApp.WaitShutdown()
 }
 
+// runBeforeStart applies the config, the server fails fast with the errors of all the modules failed to construct
 func runBeforeStart() {
-	mysqldb.Load(recconf.Config)
-	redisdb.Load(recconf.Config)
-	tablestoredb.Load(recconf.Config)
-	sls.Load(recconf.Config)
-	kafka.Load(recconf.Config)
-	datahub.Load(recconf.Config)
-	beengine.Load(recconf.Config)
-	graph.Load(recconf.Config)
-	ha3engine.Load(recconf.Config)
-	opensearch.Load(recconf.Config)
-	hbase.Load(recconf.Config)
-	hbase_thrift.Load(recconf.Config)
-	holo.Load(recconf.Config)
-	lindorm.Load(recconf.Config)
-	fs.Load(recconf.Config)
-	clickhouse.Load(recconf.Config)
-	//abtest.Load(recconf.Config)
-	algorithm.Load(recconf.Config) // holo must be loaded before loading some algorithm
-	register(recconf.Config)
+	if err := applyConfig(recconf.Config); err != nil {
+		logConfigError("runBeforeStart", err)
+		panic(err)
+	}
 }
 func runStartHook() {
 	runBeforeStart()
 
 	// first register hook
 	AddStartHook(func() error {
-		filter.Load(recconf.Config)
-		berecall.RegisterFilterWithConfig(recconf.Config)
-		sort.Load(recconf.Config)
-		// gdb.Load(recconf.Config)
-		service.Load(recconf.Config)
-		feature.UserLoadFeatureConfig(recconf.Config)
-		feature.LoadFeatureConfig(recconf.Config)
-		general_rank.LoadGeneralRankWithConfig(recconf.Config)
-		rank.LoadColdStartRankConfig(recconf.Config)
-		fallback.LoadFallbackConfig(recconf.Config)
-		pipeline.LoadPipelineConfigs(recconf.Config)
+		// the scenes are bound again, so the filters, sorts and recalls registered by the hooks are used
+		loadScenes(recconf.Config)
 		// clean log dir
 		ClearDir(recconf.Config.LogConf)
 
@@ -133,19 +117,64 @@ func runStartHook() {
 		}
 	}
 }
+func runShutdownHook(ctx context.Context) {
+	for _, hf := range shutdownHooks {
+		if err := hf(); err != nil {
+			log.Error(fmt.Sprintf("event=runShutdownHook	error=%v", err))
+		}
+	}
+
+	// the callback and debug logs written asynchronously are waited before the data sinks are closed,
+	// the wait is bounded by the shutdown timeout
+	waitUntil(ctx, "callback", web.WaitCallBack)
+	waitUntil(ctx, "debug", debug.Wait)
+	kafka.Close()
+	datahub.Close()
+	sls.Close()
+}
+
+// waitUntil runs the wait and returns when it finishes or the ctx is done
+func waitUntil(ctx context.Context, name string, wait func()) {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warning(fmt.Sprintf("event=runShutdownHook	wait=%s	error=%v", name, ctx.Err()))
+	}
+}
+
 func registerRouteInfo() {
 	// use for listen http server state
 	HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, "success")
 	})
+	// use for the readiness probe, success only after the start hooks finish
+	HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !PairecApp.IsReady() {
+			Error(w, http.StatusServiceUnavailable, "not ready")
+			return
+		}
+		io.WriteString(w, "success")
+	})
 	HandleFunc("/route_paths", func(w http.ResponseWriter, r *http.Request) {
 		paths := PairecApp.Handlers.GetRoutePath()
 
 		var result []string
 		for _, p := range paths {
 			if p == "/ping" ||
+				p == "/ready" ||
 				p == "/route_paths" ||
+				p == "/admin/config/versions" ||
+				p == "/admin/config/diff" ||
+				p == "/admin/config/rollback" ||
+				p == "/admin/config/scenes" ||
 				p == "/api/recommend" ||
+				p == "/api/recommend/batch" ||
 				p == "/api/recall" ||
 				p == "/api/feature_reply" ||
 				p == "/metrics" ||
@@ -162,8 +191,16 @@ func registerRouteInfo() {
 		io.WriteString(w, string(d))
 	})
 
+	// config versions admin, show the diff between the versions, rollback to a previous version and show the resolved scenes,
+	// they are served only with the ListenConf.AdminToken
+	HandleFunc("/admin/config/versions", adminAuth(configVersionsHandler))
+	HandleFunc("/admin/config/diff", adminAuth(configDiffHandler))
+	HandleFunc("/admin/config/rollback", adminAuth(configRollbackHandler))
+	HandleFunc("/admin/config/scenes", adminAuth(configScenesHandler))
+
 	// register recommend Controller
 	Route("/api/recommend", &web.RecommendController{})
+	Route("/api/recommend/batch", &web.BatchRecommendController{})
 	Route("/api/recall", &web.UserRecallController{})
 	Route("/api/callback", &web.CallBackController{})
 	Route("/api/feature_reply", &web.FeatureReplyController{})
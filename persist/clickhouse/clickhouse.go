@@ -6,6 +6,7 @@ import (
 	"time"
 
 	_ "github.com/ClickHouse/clickhouse-go"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 )
@@ -15,14 +16,15 @@ type ClickHouse struct {
 	DB  *sql.DB
 }
 
-var clickhouseInstances = make(map[string]*ClickHouse)
+var clickhouseInstances = registry.New[*ClickHouse]()
 
 func GetClickHouse(name string) (*ClickHouse, error) {
-	if _, ok := clickhouseInstances[name]; !ok {
+	instance, ok := clickhouseInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("ClickHouse not found, name:%s", name)
 	}
 
-	return clickhouseInstances[name], nil
+	return instance, nil
 }
 func (m *ClickHouse) Init() error {
 	db, err := sql.Open("clickhouse", m.DSN)
@@ -41,7 +43,7 @@ func (m *ClickHouse) Init() error {
 }
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.ClickHouseConfs {
-		if _, ok := clickhouseInstances[name]; !ok {
+		if !clickhouseInstances.Has(name) {
 			m := &ClickHouse{
 				DSN: conf.DSN,
 			}
@@ -50,7 +52,7 @@ func Load(config *recconf.RecommendConfig) {
 				log.Error(fmt.Sprintf("ClickHouse load error, name:%s, error:%v", name, err))
 				continue
 			}
-			clickhouseInstances[name] = m
+			clickhouseInstances.Stage(name, m)
 		}
 	}
 }
@@ -3,20 +3,22 @@ package fs
 import (
 	"fmt"
 
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/aliyun/aliyun-pai-featurestore-go-sdk/v2/domain"
 	"github.com/aliyun/aliyun-pai-featurestore-go-sdk/v2/featurestore"
 )
 
-var fsInstances = make(map[string]*FSClient)
+var fsInstances = registry.New[*FSClient]()
 
 func GetFeatureStoreClient(name string) (*FSClient, error) {
-	if _, ok := fsInstances[name]; !ok {
+	instance, ok := fsInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("feature store client not found, name:%s", name)
 	}
 
-	return fsInstances[name], nil
+	return instance, nil
 }
 
 type FSClient struct {
@@ -39,8 +41,9 @@ func (fs *FSClient) ReloadProject() {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.FeatureStoreConfs {
-		if fs, ok := fsInstances[name]; ok {
-			fs.ReloadProject()
+		if fs, ok := fsInstances.Get(name); ok {
+			// the project in use is reloaded only when the config is accepted
+			registry.OnCommit(fs.ReloadProject)
 			continue
 		}
 
@@ -85,6 +88,6 @@ func Load(config *recconf.RecommendConfig) {
 			project:     p,
 			projectName: conf.ProjectName,
 		}
-		fsInstances[name] = m
+		fsInstances.Stage(name, m)
 	}
 }
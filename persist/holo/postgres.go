@@ -9,6 +9,7 @@ import (
 
 	"github.com/lib/pq"
 	_ "github.com/lib/pq"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 )
@@ -40,14 +41,15 @@ type Postgres struct {
 	Name string
 }
 
-var postgresqlInstances = make(map[string]*Postgres)
+var postgresqlInstances = registry.New[*Postgres]()
 
 func GetPostgres(name string) (*Postgres, error) {
-	if _, ok := postgresqlInstances[name]; !ok {
+	instance, ok := postgresqlInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("Postgres not found, name:%s", name)
 	}
 
-	return postgresqlInstances[name], nil
+	return instance, nil
 }
 func (m *Postgres) Init() error {
 	db, err := sql.Open("hologres", m.DSN)
@@ -75,7 +77,7 @@ func (m *Postgres) loopDBStats() {
 }
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.HologresConfs {
-		if _, ok := postgresqlInstances[name]; ok {
+		if postgresqlInstances.Has(name) {
 			continue
 		}
 		m := &Postgres{
@@ -86,6 +88,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		postgresqlInstances[name] = m
+		postgresqlInstances.Stage(name, m)
 	}
 }
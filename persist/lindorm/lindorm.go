@@ -6,6 +6,7 @@ import (
 	"time"
 
 	avatica "github.com/apache/calcite-avatica-go/v5"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -21,14 +22,15 @@ type Lindorm struct {
 	Name     string
 }
 
-var lindormInstances = make(map[string]*Lindorm)
+var lindormInstances = registry.New[*Lindorm]()
 
 func GetLindorm(name string) (*Lindorm, error) {
-	if _, ok := lindormInstances[name]; !ok {
+	instance, ok := lindormInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("lindorm not found, name:%s", name)
 	}
 
-	return lindormInstances[name], nil
+	return instance, nil
 }
 func (m *Lindorm) Init() error {
 
@@ -51,7 +53,7 @@ func (m *Lindorm) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.LindormConfs {
-		if _, ok := lindormInstances[name]; ok {
+		if lindormInstances.Has(name) {
 			continue
 		}
 		m := &Lindorm{
@@ -65,6 +67,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		lindormInstances[name] = m
+		lindormInstances.Stage(name, m)
 	}
 }
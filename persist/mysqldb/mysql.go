@@ -6,6 +6,7 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -14,14 +15,15 @@ type Mysql struct {
 	DB  *sql.DB
 }
 
-var mysqlInstances = make(map[string]*Mysql)
+var mysqlInstances = registry.New[*Mysql]()
 
 func GetMysql(name string) (*Mysql, error) {
-	if _, ok := mysqlInstances[name]; !ok {
+	instance, ok := mysqlInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("Mysql not found, name:%s", name)
 	}
 
-	return mysqlInstances[name], nil
+	return instance, nil
 }
 func (m *Mysql) Init() error {
 	db, err := sql.Open("mysql", m.DSN)
@@ -36,7 +38,7 @@ func (m *Mysql) Init() error {
 }
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.MysqlConfs {
-		if _, ok := mysqlInstances[name]; !ok {
+		if !mysqlInstances.Has(name) {
 			m := &Mysql{
 				DSN: conf.DSN,
 			}
@@ -44,7 +46,7 @@ func Load(config *recconf.RecommendConfig) {
 			if err != nil {
 				panic(err)
 			}
-			mysqlInstances[name] = m
+			mysqlInstances.Stage(name, m)
 		}
 	}
 }
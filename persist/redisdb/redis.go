@@ -1,10 +1,12 @@
 package redisdb
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/utils/netutil"
 )
@@ -21,11 +23,11 @@ type Redis struct {
 	writeTimeout   time.Duration
 }
 
-var redisPools = make(map[string]*Redis)
-var redisConfs = make(map[string]*recconf.RedisConfig)
+var redisPools = registry.New[*Redis]()
+var redisConfs = registry.New[*recconf.RedisConfig]()
 
 func GetRedisConf(name string) (*recconf.RedisConfig, error) {
-	if conf, ok := redisConfs[name]; !ok {
+	if conf, ok := redisConfs.Get(name); !ok {
 		return nil, fmt.Errorf("RedisConf:not found, name:%s", name)
 	} else {
 		return conf, nil
@@ -33,11 +35,12 @@ func GetRedisConf(name string) (*recconf.RedisConfig, error) {
 }
 
 func GetRedis(name string) (*Redis, error) {
-	if _, ok := redisPools[name]; !ok {
+	r, ok := redisPools.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("Redis:not found, name:%s", name)
 	}
 
-	return redisPools[name], nil
+	return r, nil
 }
 
 func (r *Redis) Init() error {
@@ -99,10 +102,54 @@ func (r *Redis) Init() error {
 func (r *Redis) Get() redis.Conn {
 	return r.Pool.Get()
 }
+
+// GetContext gets a connection from the pool, it returns the ctx error when the ctx is already done
+func (r *Redis) GetContext(ctx context.Context) (redis.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.Pool.GetContext(ctx)
+}
+
+// DoContext sends the command and waits for the reply no longer than the read timeout and the deadline of ctx
+func (r *Redis) DoContext(ctx context.Context, conn redis.Conn, cmd string, args ...interface{}) (interface{}, error) {
+	timeout, err := r.timeoutOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return redis.DoWithTimeout(conn, timeout, cmd, args...)
+}
+
+// ReceiveContext receives a pipelined reply no longer than the read timeout and the deadline of ctx
+func (r *Redis) ReceiveContext(ctx context.Context, conn redis.Conn) (interface{}, error) {
+	timeout, err := r.timeoutOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return redis.ReceiveWithTimeout(conn, timeout)
+}
+
+func (r *Redis) timeoutOf(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	timeout := r.readTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		remain := time.Until(deadline)
+		if remain <= 0 {
+			return 0, context.DeadlineExceeded
+		}
+		if timeout <= 0 || remain < timeout {
+			timeout = remain
+		}
+	}
+	return timeout, nil
+}
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.RedisConfs {
-		redisConfs[name] = &conf
-		if _, ok := redisPools[name]; ok {
+		conf := conf
+		redisConfs.Stage(name, &conf)
+		if redisPools.Has(name) {
 			continue
 		}
 
@@ -133,6 +180,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(fmt.Sprintf("name=%s, err=%v", name, err))
 		}
-		redisPools[name] = r
+		redisPools.Stage(name, r)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aliyun/aliyun-tablestore-go-sdk/tablestore"
+	"github.com/alibaba/pairec/v2/datasource/registry"
 	"github.com/alibaba/pairec/v2/recconf"
 )
 
@@ -18,14 +19,15 @@ type TableStore struct {
 	Expiration      time.Time
 }
 
-var tablestoreInstances = make(map[string]*TableStore)
+var tablestoreInstances = registry.New[*TableStore]()
 
 func GetTableStore(name string) (*TableStore, error) {
-	if _, ok := tablestoreInstances[name]; !ok {
+	instance, ok := tablestoreInstances.Get(name)
+	if !ok {
 		return nil, fmt.Errorf("TableStore not found, name:%s", name)
 	}
 
-	return tablestoreInstances[name], nil
+	return instance, nil
 }
 func (m *TableStore) SetAccessKeyId(id string) {
 	m.accessKeyId = id
@@ -47,7 +49,7 @@ func (m *TableStore) Init() error {
 
 func Load(config *recconf.RecommendConfig) {
 	for name, conf := range config.TableStoreConfs {
-		if _, ok := tablestoreInstances[name]; ok {
+		if tablestoreInstances.Has(name) {
 			continue
 		}
 		t := &TableStore{
@@ -61,6 +63,6 @@ func Load(config *recconf.RecommendConfig) {
 		if err != nil {
 			panic(err)
 		}
-		tablestoreInstances[name] = t
+		tablestoreInstances.Stage(name, t)
 	}
 }
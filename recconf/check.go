@@ -1,9 +1,5 @@
 package recconf
 
-import (
-	"encoding/json"
-)
-
 type CheckResult struct {
 	Issues   []string
 	RefCount int
@@ -11,7 +7,7 @@ type CheckResult struct {
 
 func CheckRecommendConfig(configData string) (map[ModuleIndex]*CheckResult, error) {
 	var conf RecommendConfig
-	if err := json.Unmarshal([]byte(configData), &conf); err != nil {
+	if err := UnmarshalConfig([]byte(configData), &conf); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,92 @@
+package recconf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ModuleError is the error of constructing the module of the config, Module is the kind of the module such as recall,
+// and Type is the type string of the module such as RecallType
+type ModuleError struct {
+	Module string `json:"module"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Err    error  `json:"-"`
+}
+
+func NewModuleError(module, name, moduleType string, err error) *ModuleError {
+	return &ModuleError{Module: module, Name: name, Type: moduleType, Err: err}
+}
+
+func (e *ModuleError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("%s %s: %v", e.Module, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s %s(%s): %v", e.Module, e.Name, e.Type, e.Err)
+}
+
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError is the errors of all the modules failed to construct when the config is applied
+type ConfigError struct {
+	Errors []*ModuleError
+}
+
+func (e *ConfigError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("apply config error, %d modules failed:\n\t%s", len(e.Errors), strings.Join(messages, "\n\t"))
+}
+
+func (e *ConfigError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// BuildModule calls build to construct the module, the panic of build and the nil module are returned as the ModuleError,
+// so the construction failure of one module doesn't crash the server and is reported with the others
+func BuildModule[T any](module, name, moduleType string, build func() T) (result T, err *ModuleError) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			result = zero
+			if e, ok := r.(error); ok {
+				err = NewModuleError(module, name, moduleType, e)
+			} else {
+				err = NewModuleError(module, name, moduleType, fmt.Errorf("%v", r))
+			}
+		}
+	}()
+
+	result = build()
+	if isNilValue(result) {
+		err = NewModuleError(module, name, moduleType, errors.New("module is nil"))
+	}
+
+	return
+}
+
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
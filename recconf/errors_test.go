@@ -0,0 +1,39 @@
+package recconf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testModule struct{}
+
+func TestBuildModule(t *testing.T) {
+	if m, err := BuildModule("recall", "r1", "MockRecall", func() *testModule { return &testModule{} }); err != nil || m == nil {
+		t.Errorf("expect the module built, got %v, %v", m, err)
+	}
+
+	if _, err := BuildModule("recall", "r1", "MockRecall", func() *testModule { return nil }); err == nil {
+		t.Error("expect the error of the nil module")
+	}
+
+	// the typed nil in the interface is the nil module too
+	if _, err := BuildModule("recall", "r1", "MockRecall", func() interface{} { return (*testModule)(nil) }); err == nil {
+		t.Error("expect the error of the typed nil module")
+	}
+
+	notFound := errors.New("redis not found")
+	_, err := BuildModule("filter", "f1", "User2ItemExposureFilter", func() *testModule { panic(notFound) })
+	if err == nil || !errors.Is(err, notFound) || err.Module != "filter" || err.Name != "f1" {
+		t.Errorf("expect the panic returned as the module error, got %v", err)
+	}
+
+	configErr := &ConfigError{Errors: []*ModuleError{err, NewModuleError("sort", "s1", "", errors.New("sort type not registered"))}}
+	if !errors.Is(configErr, notFound) {
+		t.Error("expect the config error wraps the module errors")
+	}
+	if msg := configErr.Error(); !strings.Contains(msg, "2 modules failed") || !strings.Contains(msg, "filter f1(User2ItemExposureFilter): redis not found") ||
+		!strings.Contains(msg, "sort s1: sort type not registered") {
+		t.Errorf("expect all the errors listed, got %s", msg)
+	}
+}
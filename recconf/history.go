@@ -0,0 +1,207 @@
+package recconf
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// Default_Config_History_Size is the count of the config versions kept in memory
+const Default_Config_History_Size = 10
+
+// ConfigVersion is a config published by UpdateConf, Version increases from 1 in the process
+type ConfigVersion struct {
+	Version int              `json:"version"`
+	Time    time.Time        `json:"time"`
+	Source  string           `json:"source"`
+	Sign    string           `json:"sign"`
+	Config  *RecommendConfig `json:"-"`
+}
+
+type ConfigHistory struct {
+	mu          sync.RWMutex
+	size        int
+	lastVersion int
+	versions    []*ConfigVersion
+}
+
+var configHistory = NewConfigHistory(Default_Config_History_Size)
+
+func NewConfigHistory(size int) *ConfigHistory {
+	if size <= 0 {
+		size = Default_Config_History_Size
+	}
+
+	return &ConfigHistory{size: size}
+}
+
+// Add records the config as the latest version, the oldest version is dropped when the history is full
+func (h *ConfigHistory) Add(conf *RecommendConfig, source string) *ConfigVersion {
+	data, _ := json.Marshal(conf)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastVersion++
+	version := &ConfigVersion{
+		Version: h.lastVersion,
+		Time:    time.Now(),
+		Source:  source,
+		Sign:    utils.Md5(string(data)),
+		Config:  conf,
+	}
+	h.versions = append(h.versions, version)
+	if len(h.versions) > h.size {
+		h.versions = h.versions[len(h.versions)-h.size:]
+	}
+
+	return version
+}
+
+// Versions returns the versions kept, the latest one is the last
+func (h *ConfigHistory) Versions() []*ConfigVersion {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	versions := make([]*ConfigVersion, len(h.versions))
+	copy(versions, h.versions)
+	return versions
+}
+
+// Get returns the version, nil if the version is not kept
+func (h *ConfigHistory) Get(version int) *ConfigVersion {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, v := range h.versions {
+		if v.Version == version {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Latest returns the version in use, nil if no config is published
+func (h *ConfigHistory) Latest() *ConfigVersion {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.versions) == 0 {
+		return nil
+	}
+	return h.versions[len(h.versions)-1]
+}
+
+// GetConfigHistory returns the history of the configs published by UpdateConf
+func GetConfigHistory() *ConfigHistory {
+	return configHistory
+}
+
+// ModuleDiff is the names of the modules added, removed and changed
+type ModuleDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// ConfigDiff is the structural diff between two configs, Others is the other top-level fields changed
+type ConfigDiff struct {
+	Recalls ModuleDiff `json:"recalls"`
+	Filters ModuleDiff `json:"filters"`
+	Sorts   ModuleDiff `json:"sorts"`
+	Algos   ModuleDiff `json:"algos"`
+	Scenes  ModuleDiff `json:"scenes"`
+	Others  []string   `json:"others,omitempty"`
+}
+
+// DiffConfig compares the modules by name, the module is changed when the json of it is different
+func DiffConfig(from, to *RecommendConfig) *ConfigDiff {
+	if from == nil {
+		from = newRecommendConfig()
+	}
+	if to == nil {
+		to = newRecommendConfig()
+	}
+
+	diff := &ConfigDiff{
+		Recalls: diffModules(namedSigns(from.RecallConfs, func(c RecallConfig) string { return c.Name }),
+			namedSigns(to.RecallConfs, func(c RecallConfig) string { return c.Name })),
+		Filters: diffModules(namedSigns(from.FilterConfs, func(c FilterConfig) string { return c.Name }),
+			namedSigns(to.FilterConfs, func(c FilterConfig) string { return c.Name })),
+		Sorts: diffModules(namedSigns(from.SortConfs, func(c SortConfig) string { return c.Name }),
+			namedSigns(to.SortConfs, func(c SortConfig) string { return c.Name })),
+		Algos: diffModules(namedSigns(from.AlgoConfs, func(c AlgoConfig) string { return c.Name }),
+			namedSigns(to.AlgoConfs, func(c AlgoConfig) string { return c.Name })),
+		Scenes: diffModules(mapSigns(from.SceneConfs), mapSigns(to.SceneConfs)),
+	}
+
+	moduleFields := map[string]bool{
+		"RecallConfs": true,
+		"FilterConfs": true,
+		"SortConfs":   true,
+		"AlgoConfs":   true,
+		"SceneConfs":  true,
+	}
+	fromVal := reflect.ValueOf(from).Elem()
+	toVal := reflect.ValueOf(to).Elem()
+	confType := fromVal.Type()
+	for i := 0; i < confType.NumField(); i++ {
+		name := confType.Field(i).Name
+		if moduleFields[name] {
+			continue
+		}
+		fromData, _ := json.Marshal(fromVal.Field(i).Interface())
+		toData, _ := json.Marshal(toVal.Field(i).Interface())
+		if string(fromData) != string(toData) {
+			diff.Others = append(diff.Others, name)
+		}
+	}
+
+	return diff
+}
+
+func namedSigns[T any](confs []T, name func(T) string) map[string]string {
+	signs := make(map[string]string, len(confs))
+	for _, conf := range confs {
+		data, _ := json.Marshal(conf)
+		signs[name(conf)] = utils.Md5(string(data))
+	}
+
+	return signs
+}
+
+func mapSigns[T any](confs map[string]T) map[string]string {
+	signs := make(map[string]string, len(confs))
+	for name, conf := range confs {
+		data, _ := json.Marshal(conf)
+		signs[name] = utils.Md5(string(data))
+	}
+
+	return signs
+}
+
+func diffModules(from, to map[string]string) ModuleDiff {
+	var diff ModuleDiff
+	for name, sign := range to {
+		if fromSign, ok := from[name]; !ok {
+			diff.Added = append(diff.Added, name)
+		} else if fromSign != sign {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range from {
+		if _, ok := to[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
@@ -0,0 +1,55 @@
+package recconf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigHistory(t *testing.T) {
+	history := NewConfigHistory(2)
+	if history.Latest() != nil {
+		t.Error("expect no version in the empty history")
+	}
+
+	for i := 0; i < 3; i++ {
+		history.Add(newRecommendConfig(), "test")
+	}
+
+	versions := history.Versions()
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 3 {
+		t.Errorf("expect the latest 2 versions kept, got %v", versions)
+	}
+	if history.Get(1) != nil {
+		t.Error("expect the oldest version dropped")
+	}
+	if history.Latest().Version != 3 {
+		t.Errorf("expect the latest version 3, got %d", history.Latest().Version)
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	from := newRecommendConfig()
+	from.RecallConfs = []RecallConfig{{Name: "r1", RecallType: "MockRecall"}, {Name: "r2", RecallType: "MockRecall"}}
+	from.SceneConfs = map[string]map[string]CategoryConfig{"home": {}}
+
+	to := newRecommendConfig()
+	to.RecallConfs = []RecallConfig{{Name: "r1", RecallType: "MockRecall", RecallCount: 100}, {Name: "r3", RecallType: "MockRecall"}}
+	to.FilterConfs = []FilterConfig{{Name: "f1"}}
+	to.SceneConfs = map[string]map[string]CategoryConfig{"home": {}}
+	to.RunMode = "product"
+
+	diff := DiffConfig(from, to)
+	expect := ModuleDiff{Added: []string{"r3"}, Removed: []string{"r2"}, Changed: []string{"r1"}}
+	if !reflect.DeepEqual(diff.Recalls, expect) {
+		t.Errorf("expect the recall diff %v, got %v", expect, diff.Recalls)
+	}
+	if !reflect.DeepEqual(diff.Filters, ModuleDiff{Added: []string{"f1"}}) {
+		t.Errorf("expect the filter added, got %v", diff.Filters)
+	}
+	if !reflect.DeepEqual(diff.Scenes, ModuleDiff{}) {
+		t.Errorf("expect the scenes not changed, got %v", diff.Scenes)
+	}
+	if !reflect.DeepEqual(diff.Others, []string{"RunMode"}) {
+		t.Errorf("expect RunMode changed, got %v", diff.Others)
+	}
+}
@@ -0,0 +1,202 @@
+package recconf
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	Lint_Level_Error   = "error"
+	Lint_Level_Warning = "warning"
+)
+
+// referencedModuleTypes are the module types expected to be referenced by the other modules,
+// the unreferenced ones are reported as the warnings
+var referencedModuleTypes = map[string]bool{
+	HologresConfig{}.ModuleType():     true,
+	TableStoreConfig{}.ModuleType():   true,
+	RedisConfig{}.ModuleType():        true,
+	MysqlConfig{}.ModuleType():        true,
+	HBaseConfig{}.ModuleType():        true,
+	FeatureStoreConfig{}.ModuleType(): true,
+	BEConfig{}.ModuleType():           true,
+	ClickHouseConfig{}.ModuleType():   true,
+	LindormConfig{}.ModuleType():      true,
+	GraphConfig{}.ModuleType():        true,
+	HBaseThriftConfig{}.ModuleType():  true,
+	OpenSearchConfig{}.ModuleType():   true,
+	RecallConfig{}.ModuleType():       true,
+	FilterConfig{}.ModuleType():       true,
+	AlgoConfig{}.ModuleType():         true,
+	SortConfig{}.ModuleType():         true,
+}
+
+// moduleTypeFields are the type fields of the modules created by the registered factories,
+// the module of the type not registered fails to load
+var moduleTypeFields = map[string]string{
+	RecallConfig{}.ModuleType(): "RecallConfig.RecallType",
+	FilterConfig{}.ModuleType(): "FilterConfig.FilterType",
+	AlgoConfig{}.ModuleType():   "AlgoConfig.Type",
+	SortConfig{}.ModuleType():   "SortConfig.SortType",
+}
+
+// LintIssue is the error or the warning of a module, Path is the json path of the module in the config
+type LintIssue struct {
+	Level   string `json:"level"`
+	Module  string `json:"module"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (i *LintIssue) String() string {
+	return fmt.Sprintf("%s (%s %s): %s", i.Path, i.Module, i.Name, i.Message)
+}
+
+// LintRecommendConfig checks the config data by CheckRecommendConfig, the issues of the modules and the module types
+// not registered by the factories are returned as the errors, and the modules not referenced by the scenes, the pipelines or the other modules are returned as the warnings.
+func LintRecommendConfig(configData string) ([]*LintIssue, error) {
+	results, err := CheckRecommendConfig(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := ParseConfig([]byte(configData))
+	if err != nil {
+		return nil, err
+	}
+
+	pipelineRefs := conf.pipelineReferences()
+
+	var issues []*LintIssue
+	for index, result := range results {
+		path := conf.modulePath(index)
+		for _, issue := range result.Issues {
+			issues = append(issues, &LintIssue{
+				Level:   Lint_Level_Error,
+				Module:  index.Type,
+				Name:    index.Name,
+				Path:    path,
+				Message: issue,
+			})
+		}
+
+		if referencedModuleTypes[index.Type] && result.RefCount == 0 && !pipelineRefs[index] {
+			issues = append(issues, &LintIssue{
+				Level:   Lint_Level_Warning,
+				Module:  index.Type,
+				Name:    index.Name,
+				Path:    path,
+				Message: "is not referenced by any module",
+			})
+		}
+	}
+
+	issues = append(issues, conf.moduleTypeIssues()...)
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Level != issues[j].Level {
+			return issues[i].Level == Lint_Level_Error
+		}
+		return issues[i].Path < issues[j].Path
+	})
+
+	return issues, nil
+}
+
+// moduleTypeIssues returns the errors of the recalls, the filters, the algorithms and the sorts of the types not registered,
+// the types are checked only when the factories of the module are registered, as the schema enums do
+func (conf RecommendConfig) moduleTypeIssues() []*LintIssue {
+	var issues []*LintIssue
+	check := func(moduleType, name, value string) {
+		field := moduleTypeFields[moduleType]
+		if registered, ok := schemaEnumContains(field, value); !registered || ok {
+			return
+		}
+		issues = append(issues, &LintIssue{
+			Level:   Lint_Level_Error,
+			Module:  moduleType,
+			Name:    name,
+			Path:    conf.modulePath(ModuleIndex{Type: moduleType, Name: name}),
+			Message: fmt.Sprintf("%s %q is not registered", field, value),
+		})
+	}
+
+	for _, c := range conf.RecallConfs {
+		check(RecallConfig{}.ModuleType(), c.Name, c.RecallType)
+	}
+	for _, c := range conf.FilterConfs {
+		check(FilterConfig{}.ModuleType(), c.Name, c.FilterType)
+	}
+	for _, c := range conf.AlgoConfs {
+		check(AlgoConfig{}.ModuleType(), c.Name, c.Type)
+	}
+	for _, c := range conf.SortConfs {
+		check(SortConfig{}.ModuleType(), c.Name, c.SortType)
+	}
+
+	return issues
+}
+
+// pipelineReferences returns the recalls, the filters and the sorts used by the pipelines
+func (conf RecommendConfig) pipelineReferences() map[ModuleIndex]bool {
+	refs := make(map[ModuleIndex]bool)
+	for _, pipelines := range conf.PipelineConfs {
+		for _, pipeline := range pipelines {
+			for _, name := range pipeline.RecallNames {
+				refs[ModuleIndex{Type: RecallConfig{}.ModuleType(), Name: name}] = true
+			}
+			for _, name := range pipeline.FilterNames {
+				refs[ModuleIndex{Type: FilterConfig{}.ModuleType(), Name: name}] = true
+			}
+			for _, name := range pipeline.SortNames {
+				refs[ModuleIndex{Type: SortConfig{}.ModuleType(), Name: name}] = true
+			}
+			for _, algoName := range pipeline.RankConf.RankAlgoList {
+				refs[ModuleIndex{Type: AlgoConfig{}.ModuleType(), Name: algoName}] = true
+			}
+		}
+	}
+
+	return refs
+}
+
+// modulePath returns the json path of the module, the modules of the list are indexed by the position in the list
+func (conf RecommendConfig) modulePath(index ModuleIndex) string {
+	jsonPath, ok := modJsonPath[index.Type]
+	if !ok {
+		return "$"
+	}
+
+	position := -1
+	switch index.Type {
+	case RecallConfig{}.ModuleType():
+		for i, c := range conf.RecallConfs {
+			if c.Name == index.Name {
+				position = i
+			}
+		}
+	case FilterConfig{}.ModuleType():
+		for i, c := range conf.FilterConfs {
+			if c.Name == index.Name {
+				position = i
+			}
+		}
+	case AlgoConfig{}.ModuleType():
+		for i, c := range conf.AlgoConfs {
+			if c.Name == index.Name {
+				position = i
+			}
+		}
+	case SortConfig{}.ModuleType():
+		for i, c := range conf.SortConfs {
+			if c.Name == index.Name {
+				position = i
+			}
+		}
+	default:
+		return fmt.Sprintf("$.%s.%s", jsonPath, index.Name)
+	}
+
+	return fmt.Sprintf("$.%s[%d]", jsonPath, position)
+}
@@ -0,0 +1,65 @@
+package recconf
+
+import "testing"
+
+func TestLintRecommendConfig(t *testing.T) {
+	RegisterSchemaEnum("RecallConfig.RecallType", "MockRecall")
+	configData := `{
+		"RedisConfs": {"redis1": {}},
+		"RecallConfs": [
+			{"Name": "recall1", "RecallType": "MockRecall", "DaoConf": {"AdapterType": "redis", "RedisName": "redis1"}},
+			{"Name": "recall2", "RecallType": "MockRecall", "DaoConf": {"AdapterType": "redis", "RedisName": "not_exist"}}
+		],
+		"SortConfs": [{"Name": "sort1"}],
+		"SceneConfs": {"home": {"default": {"RecallNames": ["recall1", "recall3"]}}},
+		"SortNames": {"home": ["sort1"]}
+	}`
+
+	issues, err := LintRecommendConfig(configData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []LintIssue{
+		{Level: Lint_Level_Error, Module: "SceneRecallConf", Name: "home", Path: "$.SceneConfs.home"},
+		{Level: Lint_Level_Error, Module: "RecallConf", Name: "recall2", Path: "$.RecallConfs[1]"},
+		{Level: Lint_Level_Warning, Module: "RecallConf", Name: "recall2", Path: "$.RecallConfs[1]"},
+	}
+	if len(issues) != len(expects) {
+		t.Fatalf("expect %d issues, got %v", len(expects), issues)
+	}
+	for _, expect := range expects {
+		found := false
+		for _, issue := range issues {
+			if issue.Level == expect.Level && issue.Module == expect.Module && issue.Name == expect.Name && issue.Path == expect.Path {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expect the issue %v, got %v", expect, issues)
+		}
+	}
+	if issues[len(issues)-1].Level != Lint_Level_Warning {
+		t.Error("expect the errors listed before the warnings")
+	}
+}
+
+func TestLintRecommendConfigModuleType(t *testing.T) {
+	RegisterSchemaEnum("RecallConfig.RecallType", "MockRecall")
+	configData := `{
+		"RecallConfs": [
+			{"Name": "recall1", "RecallType": "MockRecall"},
+			{"Name": "recall2", "RecallType": "NotExistRecall"}
+		],
+		"SceneConfs": {"home": {"default": {"RecallNames": ["recall1", "recall2"]}}}
+	}`
+
+	issues, err := LintRecommendConfig(configData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 1 || issues[0].Level != Lint_Level_Error || issues[0].Name != "recall2" || issues[0].Path != "$.RecallConfs[1]" {
+		t.Errorf("expect the recall type not registered reported, got %v", issues)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/alibaba/pairec/v2/config"
 )
@@ -82,11 +84,30 @@ type RecommendConfig struct {
 	FeatureLogConfs           map[string]FeatureLogConfig
 	PipelineConfs             map[string][]PipelineConfig
 	PrometheusConfig          PrometheusConfig
+	BatchRecommendConf        BatchRecommendConfig
 	UserDefineConfs           json.RawMessage
+	// SceneExtends is the base scene of the scenes declaring Extends in SceneConfs, it is set by UnmarshalConfig
+	SceneExtends map[string]string `json:"-"`
 }
 type ListenConfig struct {
 	HttpAddr string
 	HttpPort int
+	// GrpcPort is the port of the grpc server, the grpc server is not started when it is not set
+	GrpcPort int
+	// ShutdownTimeout is the max seconds of draining the in-flight requests on shutdown, default 30
+	ShutdownTimeout int
+	// ShutdownDrainDelay is the seconds between the app turning not ready and the listeners stopping on shutdown,
+	// the requests routed before the load balancer removes the app are still served, default 0
+	ShutdownDrainDelay int
+	// AdminToken enables the /admin/config routes, the requests must carry the header Authorization: Bearer <AdminToken>.
+	// The admin routes are disabled when it is not set.
+	AdminToken string
+}
+type BatchRecommendConfig struct {
+	// Parallelism is the max count of the requests of a batch running concurrently
+	Parallelism int
+	// MaxBatchSize is the max count of the requests of a batch
+	MaxBatchSize int
 }
 type PrometheusConfig struct {
 	Enable           bool
@@ -306,7 +327,10 @@ type VectorConfig struct {
 }
 type MilvusConfig struct {
 	ServerAddress string
-	Timeout       int64
+	// Timeout is the timeout of connecting and searching in milliseconds
+	Timeout  int64
+	Username string
+	Password string
 }
 type RecallConfig struct {
 	Name           string
@@ -319,11 +343,18 @@ type RecallConfig struct {
 	CacheConfig    string
 	CachePrefix    string
 	CacheTime      int // cache time by seconds
+	Timeout        int // timeout by milliseconds, the recall is dropped when it misses the timeout
 	Triggers       []TriggerConfig
 
+	// ResultCacheConf caches the items of any recall type by the user when CacheAdapter is set
+	ResultCacheConf RecallResultCacheConfig
+
 	HologresVectorConf       HologresVectorConfig
 	BeVectorConf             BeVectorConfig
 	MilvusVectorConf         MilvusVectorConfig
+	HNSWConf                 HNSWConfig
+	OnlineI2IConf            OnlineI2IConfig
+	GeoConf                  GeoRecallConfig
 	UserCollaborativeDaoConf UserCollaborativeDaoConfig
 	ItemCollaborativeDaoConf ItemCollaborativeDaoConfig
 	User2ItemDaoConf         User2ItemDaoConfig
@@ -496,9 +527,111 @@ type MilvusVectorConfig struct {
 	VectorKeyField       string
 	VectorEmbeddingField string
 	CollectionName       string
-	MetricType           string
-	SearchParams         map[string]interface{}
+	// MetricType is the metric type of the index, such as IP and L2, IP by default
+	MetricType   string
+	SearchParams map[string]interface{}
+	// Partitions are the partitions searched, all the partitions are searched when it is empty
+	Partitions []string
+	// Expr is the boolean expression filtering the entities
+	Expr string
+}
+
+// HNSWConfig is the config of the in-process hnsw index of the item vectors, the user vector is fetched by VectorDaoConf
+type HNSWConfig struct {
+	// M is the max count of the neighbors of the node, 16 by default
+	M int
+	// EfConstruction is the size of the candidates searched when building the index, 200 by default
+	EfConstruction int
+	// EfSearch is the size of the candidates searched when recalling, 64 by default and at least the recall count
+	EfSearch int
+	// MetricType is one of ip, l2 and cosine, ip by default
+	MetricType string
+	// FilePath is the local file of the item vectors, each line is the item id and the vector separated by the tab,
+	// ItemVectorDaoConf is used when it is empty
+	FilePath          string
+	ItemVectorDaoConf VectorDaoConfig
+	// RefreshInterval is the interval of rebuilding the index in seconds, the index is not rebuilt when it is 0
+	RefreshInterval int
+}
+
+// RecallResultCacheConfig is the config of the cache of the recall items, the items are fresh for CacheTime seconds,
+// then the stale items are returned for StaleTime seconds while the items are refreshed in the background
+type RecallResultCacheConfig struct {
+	// CacheAdapter is localCache or redis, CacheConfig is the config of the adapter
+	CacheAdapter string
+	CacheConfig  string
+	CachePrefix  string
+	// CacheTime is the seconds the items are fresh, 1800 by default
+	CacheTime int
+	// StaleTime is the seconds the stale items are returned after CacheTime, the cache misses after CacheTime when it is 0
+	StaleTime int
+	// KeyFeatures are the request features the items depend on, their values are a part of the cache key with the scene and the uid
+	KeyFeatures []string
+}
+
+// OnlineI2IConfig is the config of the i2i statistics computed online from the callback stream,
+// the statistics are kept in the local memory when DaoConf.AdapterType is empty, and in redis when it is redis
+type OnlineI2IConfig struct {
+	DaoConf DaoConfig
+	// Algorithm is swing or covisit, covisit by default
+	Algorithm string
+	// SwingAlpha is the smoothing of the swing weight 1/(alpha+|Iu∩Iv|), 1 by default
+	SwingAlpha float64
+	// SessionWindow is the window in seconds, the items the user acts on within the window are co-visited, 1800 by default
+	SessionWindow int
+	// HalfLife is the half life in seconds of the decay of the scores, 86400 by default
+	HalfLife int
+	// Expire is the seconds the recent items of the user and the recent users of the item are kept, 604800 by default
+	Expire int
+	// UserItemCount is the max count of the recent items of the user, 50 by default
+	UserItemCount int
+	// ItemUserCount is the max count of the recent users of the item used by swing, 50 by default
+	ItemUserCount int
+	// MaxNeighbors is the max count of the neighbors kept of the item, 200 by default
+	MaxNeighbors int
+	// NeighborCount is the count of the neighbors of each trigger when recalling, 50 by default
+	NeighborCount int
+	// ShardCount is the count of the shards of the local memory, 32 by default
+	ShardCount int
+	// Scenes are the callback scenes feeding the statistics, all the scenes when it is empty
+	Scenes []string
+	// EventField is the property of the callback item of the event, event by default,
+	// the callback items are counted only when the event is one of Events, all the items are counted when Events is empty
+	EventField string
+	Events     []string
+}
+
+// GeoRecallConfig is the config of the items near the user by the geohash cells, the items of the cells are fetched from
+// redis or hologres of DaoConf, or from the local index loaded from FilePath or the hologres table when InMemory is true
+type GeoRecallConfig struct {
+	// DaoConf is redis or hologres, the redis key is RedisPrefix+geohash, and the value is the items separated by the comma,
+	// each item is the item id, latitude, longitude and the optional timestamp separated by the colon.
+	// The hologres table has the fields of DaoConf.ItemIdField, GeoHashField, LatitudeField, LongitudeField and the optional TimestampField.
+	DaoConf DaoConfig
+	// InMemory loads all the items of FilePath or the hologres table into the local index by the geohash
+	InMemory bool
+	// FilePath is the local file of the items, each line is the item id, latitude, longitude and the optional timestamp separated by the tab
+	FilePath string
+	// RefreshInterval is the interval of reloading the local index in seconds, the index is not reloaded when it is 0
+	RefreshInterval int
+	// Precision is the length of the geohash of the cells, 6 by default
+	Precision int
+	// Rings is the count of the rings of the neighbor cells around the cell of the user, 1 by default
+	Rings int
+	// LatitudeFeature and LongitudeFeature are the names of the request features or the user properties of the location,
+	// latitude and longitude by default
+	LatitudeFeature  string
+	LongitudeFeature string
+	GeoHashField     string
+	LatitudeField    string
+	LongitudeField   string
+	TimestampField   string
+	// MaxDistance is the max distance in meters of the items, no limit when it is 0
+	MaxDistance float64
+	// FreshnessHalfLife is the half life in seconds of the decay of the score by the timestamp of the item, no decay when it is 0
+	FreshnessHalfLife int
 }
+
 type UserCollaborativeDaoConfig struct {
 	DaoConfig
 	User2ItemTable           string
@@ -645,11 +778,52 @@ type SceneConfig struct {
 type CategoryConfig struct {
 	RecallNames    []string
 	FallbackConfig *FallbackConfig
+	// Timeout is the request-level budget in milliseconds, it can be overridden by the timeout of the request param
+	Timeout int
+	// RecallTimeout is the budget of all the recalls in milliseconds, the recalls which miss it are dropped
+	RecallTimeout    int
+	PaginationConfig *PaginationConfig
+	// ReturnFields are the item properties returned in the response, * returns all the properties
+	ReturnFields []string
+	// ReturnScores returns the final score and the recall scores of the item
+	ReturnScores bool
+	// ReturnAlgoScores returns the scores of the algorithms
+	ReturnAlgoScores bool
+	// RecallMergeConf merges the items of the recalls by the normalized scores before the filters, the items are not merged when it is nil
+	RecallMergeConf *RecallMergeConfig
+}
+
+// RecallMergeConfig normalizes the scores of each recall, weights them by the recall and fuses the scores of the duplicated items,
+// the fused score is the score of the item
+type RecallMergeConfig struct {
+	// Normalization is one of minmax, zscore and rank, the scores are not normalized when it is empty
+	Normalization string
+	// Fusion is one of sum, max and rrf, sum by default
+	Fusion string
+	// RRFK is the constant k of the reciprocal rank fusion, 60 by default
+	RRFK int
+	// RecallWeights are the weights of the recalls by the recall name, 1 by default,
+	// they can be overridden by the RecallWeights experiment param of the category or the pipeline
+	RecallWeights map[string]float64
+}
+
+type PaginationConfig struct {
+	// CacheAdapter is localCache or redis, localCache by default
+	CacheAdapter string
+	CacheConfig  string
+	// CacheTime is the expiration of the ranked list of the session in seconds
+	CacheTime int
+	// ListSize is the size of the ranked list computed on the first page
+	ListSize int
 }
 
 type FallbackConfig struct {
-	Timeout int
+	// FallbackType is one of featurestore, redis, hologres, file and last_response, featurestore by default
+	FallbackType string
+	Timeout      int
 	DaoConfig
+	// FilePath is the json file of the items used by the file fallback
+	FilePath            string
 	CacheTime           int
 	CacheConfig         string
 	CompleteItemsIfNeed bool
@@ -914,6 +1088,7 @@ type PipelineConfig struct {
 	RankConf          RankConfig
 	ColdStartRankConf ColdStartRankConfig
 	SortNames         []string
+	RecallMergeConf   *RecallMergeConfig
 }
 
 func newRecommendConfig() *RecommendConfig {
@@ -955,13 +1130,12 @@ func CopyConfig(src, dst *RecommendConfig, filters ...func(string) bool) {
 }
 
 func loadConfigFromFile(filePath string) error {
-	configer, err := config.NewConfig(adapterName, filePath)
+	rawdata, err := ReadConfigFile(filePath)
 	if err != nil {
 		return err
 	}
-	rawdata := configer.RawData()
 
-	err = json.Unmarshal(rawdata, Config)
+	err = UnmarshalConfig(rawdata, Config)
 	if err != nil {
 		return err
 	}
@@ -969,6 +1143,38 @@ func loadConfigFromFile(filePath string) error {
 	return nil
 }
 
+// ReadConfigFile reads the raw data of the config file through the config adapter of the file extension,
+// the yaml and toml files are read as the json data, the other files are read by the json adapter
+func ReadConfigFile(filePath string) ([]byte, error) {
+	configer, err := config.NewConfig(fileAdapterName(filePath), filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return configer.RawData(), nil
+}
+
+func fileAdapterName(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	}
+
+	return adapterName
+}
+
+// ParseConfig parses the raw data into a new config with the default values, the config in use is not changed
+func ParseConfig(data []byte) (*RecommendConfig, error) {
+	conf := newRecommendConfig()
+	if err := UnmarshalConfig(data, conf); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
 // LoadConfig load config from file or pairec config server
 // First check the environment CONFIG_NAME, if exist, load config data from pairec config server
 func LoadConfig(filePath string) error {
@@ -981,7 +1187,12 @@ func LoadConfig(filePath string) error {
 		return errors.New("config file path empty")
 	}
 
-	return loadConfigFromFile(filePath)
+	if err := loadConfigFromFile(filePath); err != nil {
+		return err
+	}
+
+	configHistory.Add(Config, "file:"+filePath)
+	return nil
 }
 
 var notifyCh = make([]chan *RecommendConfig, 0)
@@ -993,7 +1204,13 @@ func Subscribe() <-chan *RecommendConfig {
 }
 
 func UpdateConf(conf *RecommendConfig) {
+	UpdateConfWithSource(conf, "update")
+}
+
+// UpdateConfWithSource publishes the config like UpdateConf, and records it in the config history with the source
+func UpdateConfWithSource(conf *RecommendConfig, source string) {
 	Config = conf
+	configHistory.Add(conf, source)
 	go func() {
 		for _, ch := range notifyCh {
 			ch <- conf
@@ -67,6 +67,8 @@ func (conf RecallConfig) Requirements() Requirements {
 	}
 	addDaoRequirements(conf.ColdStartDaoConf.DaoConfig, requirements)
 	addDaoRequirements(conf.ItemCollaborativeDaoConf.DaoConfig, requirements)
+	addDaoRequirements(conf.OnlineI2IConf.DaoConf, requirements)
+	addDaoRequirements(conf.GeoConf.DaoConf, requirements)
 	if conf.OpenSearchConf.OpenSearchName != "" {
 		requirements.Add(OpenSearchConfig{}.ModuleType(), conf.OpenSearchConf.OpenSearchName)
 	}
@@ -176,7 +178,7 @@ func (conf SceneSortConfig) Requirements() Requirements {
 			continue
 		}
 
-		requirements.Add(FilterConfig{}.ModuleType(), name)
+		requirements.Add(SortConfig{}.ModuleType(), name)
 	}
 
 	return requirements
@@ -0,0 +1,250 @@
+package recconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Scene_Extends_Key is the key of the scene in SceneConfs naming the base scene, the scene inherits the config of the base scene
+// in the scene keyed configs, and the fields set by the scene itself override the inherited ones
+const Scene_Extends_Key = "Extends"
+
+// sceneKeyedConfigs are the configs of RecommendConfig keyed by the scene name, they are inherited from the base scene
+var sceneKeyedConfigs = []string{"SceneConfs", "FilterNames", "SortNames", "RankConf", "PipelineConfs"}
+
+// SceneView is the resolved config of the scene, Extends is the base scene declared by the scene
+type SceneView struct {
+	Scene         string                    `json:"scene"`
+	Extends       string                    `json:"extends,omitempty"`
+	Categories    map[string]CategoryConfig `json:"categories,omitempty"`
+	FilterNames   []string                  `json:"filter_names,omitempty"`
+	SortNames     []string                  `json:"sort_names,omitempty"`
+	RankConf      *RankConfig               `json:"rank_conf,omitempty"`
+	PipelineConfs []PipelineConfig          `json:"pipeline_confs,omitempty"`
+}
+
+// UnmarshalConfig resolves the scene inheritance of the raw data and unmarshals it into conf
+func UnmarshalConfig(data []byte, conf *RecommendConfig) error {
+	resolved, extends, err := ResolveSceneExtends(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resolved, conf); err != nil {
+		return err
+	}
+	conf.SceneExtends = extends
+
+	return nil
+}
+
+// ResolveSceneExtends resolves the scenes of the raw data declaring Scene_Extends_Key, the scene keyed configs of the base scene
+// are merged into the scene: the objects are merged field by field, the lists of the objects with Name are merged by Name,
+// and the other values of the scene win. The base scene may extend another scene, the cycles and the unknown base scenes are errors.
+// The base scene of each scene is returned, the data is returned unchanged when no scene extends another one.
+func ResolveSceneExtends(data []byte) ([]byte, map[string]string, error) {
+	if !bytes.Contains(data, []byte(Scene_Extends_Key)) {
+		return data, nil, nil
+	}
+
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	scenes, _ := raw["SceneConfs"].(map[string]interface{})
+	extends := make(map[string]string)
+	for scene, value := range scenes {
+		categories, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		base, ok := categories[Scene_Extends_Key]
+		if !ok {
+			continue
+		}
+		baseName, ok := base.(string)
+		if !ok || baseName == "" {
+			return nil, nil, fmt.Errorf("scene %s: %s should be the name of the base scene", scene, Scene_Extends_Key)
+		}
+		if _, ok := scenes[baseName]; !ok {
+			return nil, nil, fmt.Errorf("scene %s: base scene %s not exist", scene, baseName)
+		}
+		extends[scene] = baseName
+	}
+	if len(extends) == 0 {
+		return data, nil, nil
+	}
+
+	resolver := &sceneResolver{raw: raw, extends: extends, resolved: make(map[string]bool)}
+	names := make([]string, 0, len(extends))
+	for scene := range extends {
+		names = append(names, scene)
+	}
+	sort.Strings(names)
+	for _, scene := range names {
+		if err := resolver.resolve(scene, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	result, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, extends, nil
+}
+
+type sceneResolver struct {
+	raw      map[string]interface{}
+	extends  map[string]string
+	resolved map[string]bool
+}
+
+// resolve merges the configs of the base scene into the scene after the base scene itself is resolved
+func (r *sceneResolver) resolve(scene string, stack []string) error {
+	if r.resolved[scene] {
+		return nil
+	}
+	for _, s := range stack {
+		if s == scene {
+			return fmt.Errorf("scene extends cycle, %s", strings.Join(append(stack, scene), " -> "))
+		}
+	}
+
+	base, ok := r.extends[scene]
+	if !ok {
+		r.resolved[scene] = true
+		return nil
+	}
+	if err := r.resolve(base, append(stack, scene)); err != nil {
+		return err
+	}
+
+	for _, key := range sceneKeyedConfigs {
+		configs, ok := r.raw[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		baseValue, ok := configs[base]
+		if !ok {
+			continue
+		}
+		value := configs[scene]
+		if key == "SceneConfs" {
+			// the base scene of the base scene is not inherited
+			baseValue = withoutKey(baseValue, Scene_Extends_Key)
+			value = withoutKey(value, Scene_Extends_Key)
+		}
+		configs[scene] = mergeSceneValue(baseValue, value)
+	}
+	r.resolved[scene] = true
+
+	return nil
+}
+
+func withoutKey(value interface{}, key string) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// mergeSceneValue returns the value of the scene merged over the value of the base scene, the base value is not modified
+func mergeSceneValue(base, value interface{}) interface{} {
+	if value == nil {
+		return base
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		b, ok := base.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		result := make(map[string]interface{}, len(b)+len(v))
+		for key, baseVal := range b {
+			result[key] = baseVal
+		}
+		for key, val := range v {
+			result[key] = mergeSceneValue(b[key], val)
+		}
+		return result
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok || !namedObjects(b) || !namedObjects(v) {
+			return value
+		}
+		result := make([]interface{}, 0, len(b)+len(v))
+		index := make(map[interface{}]int, len(b))
+		for _, item := range b {
+			index[item.(map[string]interface{})["Name"]] = len(result)
+			result = append(result, item)
+		}
+		for _, item := range v {
+			name := item.(map[string]interface{})["Name"]
+			if i, ok := index[name]; ok {
+				result[i] = mergeSceneValue(result[i], item)
+			} else {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+
+	return value
+}
+
+// namedObjects checks whether all the items are the objects with Name, such as the pipelines
+func namedObjects(items []interface{}) bool {
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["Name"].(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SceneViews returns the resolved configs of the scenes in SceneConfs, all the scenes are returned when scene is empty
+func (c *RecommendConfig) SceneViews(scene string) map[string]*SceneView {
+	views := make(map[string]*SceneView)
+	for name, categories := range c.SceneConfs {
+		if scene != "" && name != scene {
+			continue
+		}
+
+		view := &SceneView{
+			Scene:       name,
+			Extends:     c.SceneExtends[name],
+			Categories:  categories,
+			FilterNames: c.FilterNames[name],
+			SortNames:   c.SortNames[name],
+		}
+		if rankConf, ok := c.RankConf[name]; ok {
+			view.RankConf = &rankConf
+		}
+		view.PipelineConfs = c.PipelineConfs[name]
+		views[name] = view
+	}
+
+	return views
+}
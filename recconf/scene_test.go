@@ -0,0 +1,71 @@
+package recconf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveSceneExtends(t *testing.T) {
+	data := `{
+		"SceneConfs": {
+			"base": {"default": {"RecallNames": ["r1", "r2"], "Timeout": 100}},
+			"scene_a": {"Extends": "base", "default": {"RecallNames": ["r3"]}},
+			"scene_b": {"Extends": "scene_a"}
+		},
+		"FilterNames": {"base": ["f1"]},
+		"RankConf": {"base": {"RankAlgoList": ["a1"], "RankScore": "${a1}"}, "scene_a": {"RankScore": "${a1} * 2"}},
+		"PipelineConfs": {
+			"base": [{"Name": "p1", "RecallNames": ["r1"], "SortNames": ["s1"]}],
+			"scene_a": [{"Name": "p1", "RecallNames": ["r4"]}, {"Name": "p2"}]
+		}
+	}`
+
+	conf, err := ParseConfig([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sceneA := conf.SceneConfs["scene_a"]["default"]
+	if !reflect.DeepEqual(sceneA.RecallNames, []string{"r3"}) || sceneA.Timeout != 100 {
+		t.Errorf("expect the overridden RecallNames and the inherited Timeout, got %v", sceneA)
+	}
+	if _, ok := conf.SceneConfs["scene_a"][Scene_Extends_Key]; ok {
+		t.Error("expect Extends not a category")
+	}
+	if !reflect.DeepEqual(conf.SceneConfs["scene_b"]["default"].RecallNames, []string{"r3"}) {
+		t.Errorf("expect scene_b inherits the resolved scene_a, got %v", conf.SceneConfs["scene_b"])
+	}
+	if !reflect.DeepEqual(conf.FilterNames["scene_b"], []string{"f1"}) {
+		t.Errorf("expect the inherited FilterNames, got %v", conf.FilterNames["scene_b"])
+	}
+	if rank := conf.RankConf["scene_a"]; rank.RankScore != "${a1} * 2" || !reflect.DeepEqual(rank.RankAlgoList, []string{"a1"}) {
+		t.Errorf("expect RankConf merged by field, got %v", rank)
+	}
+
+	pipelines := conf.PipelineConfs["scene_a"]
+	if len(pipelines) != 2 || !reflect.DeepEqual(pipelines[0].RecallNames, []string{"r4"}) || !reflect.DeepEqual(pipelines[0].SortNames, []string{"s1"}) {
+		t.Errorf("expect the pipelines merged by Name, got %v", pipelines)
+	}
+	if !reflect.DeepEqual(conf.RankConf["base"].RankScore, "${a1}") || len(conf.PipelineConfs["base"]) != 1 {
+		t.Error("expect the base scene not changed")
+	}
+
+	view := conf.SceneViews("scene_b")["scene_b"]
+	if view == nil || view.Extends != "scene_a" || view.RankConf == nil || view.RankConf.RankScore != "${a1} * 2" {
+		t.Errorf("expect the resolved view of scene_b, got %v", view)
+	}
+}
+
+func TestResolveSceneExtendsError(t *testing.T) {
+	testcases := []string{
+		`{"SceneConfs": {"a": {"Extends": "b"}, "b": {"Extends": "a"}}}`,
+		`{"SceneConfs": {"a": {"Extends": "unknown"}}}`,
+		`{"SceneConfs": {"a": {"Extends": 1}}}`,
+	}
+
+	for _, data := range testcases {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("expect the error of %s", data)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package recconf
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Json_Schema_Version is the draft of the json schema generated, it is supported by the most editors
+const Json_Schema_Version = "http://json-schema.org/draft-07/schema#"
+
+// JsonSchema is the subset of the json schema used by GenerateJsonSchema
+type JsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JsonSchema `json:"properties,omitempty"`
+	Items                *JsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *JsonSchema            `json:"additionalProperties,omitempty"`
+	Definitions          map[string]*JsonSchema `json:"definitions,omitempty"`
+}
+
+var (
+	schemaEnumsMu sync.RWMutex
+	// schemaEnums are the known values of the string fields, the key is Type.Field such as RecallConfig.RecallType
+	schemaEnums = map[string][]string{
+		"DaoConfig.AdapterType": {
+			DaoConf_Adapter_Mysql,
+			DaoConf_Adapter_Redis,
+			DaoConf_Adapter_TableStore,
+			DaoConf_Adapter_HBase,
+			DaoConf_Adapter_Hologres,
+			DataSource_Type_ClickHouse,
+			DataSource_Type_BE,
+			DataSource_Type_Lindorm,
+			DataSource_Type_HBase_Thrift,
+			DataSource_Type_FeatureStore,
+			Datasource_Type_Graph,
+		},
+	}
+)
+
+// RegisterSchemaEnum adds the known values of the string field to the json schema, field is Type.Field of the config type,
+// such as RecallConfig.RecallType. The packages creating the modules by the type strings register the types they support.
+func RegisterSchemaEnum(field string, values ...string) {
+	schemaEnumsMu.Lock()
+	defer schemaEnumsMu.Unlock()
+
+	for _, value := range values {
+		exist := false
+		for _, v := range schemaEnums[field] {
+			if v == value {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			schemaEnums[field] = append(schemaEnums[field], value)
+		}
+	}
+}
+
+// schemaEnumContains returns whether the value is the known value of the field, registered is false when no value of the field is registered
+func schemaEnumContains(field, value string) (registered bool, ok bool) {
+	schemaEnumsMu.RLock()
+	defer schemaEnumsMu.RUnlock()
+
+	for _, v := range schemaEnums[field] {
+		if v == value {
+			return true, true
+		}
+	}
+	return len(schemaEnums[field]) > 0, false
+}
+
+// GenerateJsonSchema generates the json schema of RecommendConfig from the go types,
+// the struct types are the definitions, and the string fields registered by RegisterSchemaEnum have the enums.
+func GenerateJsonSchema() *JsonSchema {
+	schemaEnumsMu.RLock()
+	defer schemaEnumsMu.RUnlock()
+
+	g := &schemaGenerator{definitions: make(map[string]*JsonSchema)}
+	root := g.structSchema(reflect.TypeOf(RecommendConfig{}))
+	root.Schema = Json_Schema_Version
+	root.Title = "RecommendConfig"
+	root.Definitions = g.definitions
+
+	// the scenes map the categories, and the scene may name the base scene by Scene_Extends_Key
+	if scenes, ok := root.Properties["SceneConfs"]; ok && scenes.AdditionalProperties != nil {
+		scenes.AdditionalProperties.Properties = map[string]*JsonSchema{
+			Scene_Extends_Key: {Type: "string"},
+		}
+	}
+
+	return root
+}
+
+type schemaGenerator struct {
+	definitions map[string]*JsonSchema
+}
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) *JsonSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &JsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JsonSchema{Type: "string"}
+		}
+		return &JsonSchema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JsonSchema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.definitions[name]; !ok {
+			// add the placeholder first, so the recursive types refer to the definition
+			g.definitions[name] = &JsonSchema{}
+			*g.definitions[name] = *g.structSchema(t)
+		}
+		return &JsonSchema{Ref: "#/definitions/" + name}
+	}
+
+	// interface{} and the other types accept any value
+	return &JsonSchema{}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *JsonSchema {
+	schema := &JsonSchema{Type: "object", Properties: make(map[string]*JsonSchema)}
+	g.addFields(schema, t, t.Name())
+
+	return schema
+}
+
+// addFields adds the fields of the struct as the properties, the fields of the embedded structs are promoted as encoding/json does,
+// and the fields of the struct itself win over the promoted ones
+func (g *schemaGenerator) addFields(schema *JsonSchema, t reflect.Type, typeName string) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := schema.Properties[name]; ok {
+			continue
+		}
+
+		fieldSchema := g.typeSchema(field.Type)
+		if enum, ok := schemaEnums[typeName+"."+field.Name]; ok {
+			values := make([]string, len(enum))
+			copy(values, enum)
+			sort.Strings(values)
+			if fieldSchema.Type == "string" {
+				fieldSchema.Enum = values
+			} else if fieldSchema.Type == "array" && fieldSchema.Items.Type == "string" {
+				fieldSchema.Items.Enum = values
+			}
+		}
+		schema.Properties[name] = fieldSchema
+	}
+
+	for _, embeddedType := range embedded {
+		g.addFields(schema, embeddedType, embeddedType.Name())
+	}
+}
+
+// MarshalJsonSchema returns the indented json of the schema generated by GenerateJsonSchema
+func MarshalJsonSchema() ([]byte, error) {
+	return json.MarshalIndent(GenerateJsonSchema(), "", "  ")
+}
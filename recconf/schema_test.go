@@ -0,0 +1,46 @@
+package recconf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateJsonSchema(t *testing.T) {
+	RegisterSchemaEnum("RecallConfig.RecallType", "MockRecall", "MockRecall")
+
+	schema := GenerateJsonSchema()
+	if schema.Schema != Json_Schema_Version || schema.Type != "object" {
+		t.Fatalf("expect the object schema, got %s %s", schema.Schema, schema.Type)
+	}
+
+	recallConfs := schema.Properties["RecallConfs"]
+	if recallConfs == nil || recallConfs.Type != "array" || recallConfs.Items.Ref != "#/definitions/RecallConfig" {
+		t.Fatalf("expect RecallConfs the array of RecallConfig, got %v", recallConfs)
+	}
+	recallType := schema.Definitions["RecallConfig"].Properties["RecallType"]
+	if !reflect.DeepEqual(recallType.Enum, []string{"MockRecall"}) {
+		t.Errorf("expect the registered enum, got %v", recallType.Enum)
+	}
+
+	// the fields of the embedded DaoConfig are promoted with the enums of DaoConfig
+	adapterType := schema.Definitions["FeatureDaoConfig"].Properties["AdapterType"]
+	if adapterType == nil || len(adapterType.Enum) == 0 {
+		t.Errorf("expect AdapterType of FeatureDaoConfig with the enum, got %v", adapterType)
+	}
+
+	if schema.Properties["SceneConfs"].AdditionalProperties.Type != "object" {
+		t.Errorf("expect SceneConfs the map, got %v", schema.Properties["SceneConfs"])
+	}
+
+	// the references of the recursive types are resolved
+	for name, def := range schema.Definitions {
+		if def.Type != "object" {
+			t.Errorf("expect the definition %s object, got %s", name, def.Type)
+		}
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,37 +1,205 @@
 package pairec
 
 import (
+	"fmt"
+
+	"github.com/alibaba/pairec/v2/algorithm"
+	"github.com/alibaba/pairec/v2/datasource/beengine"
+	"github.com/alibaba/pairec/v2/datasource/datahub"
+	"github.com/alibaba/pairec/v2/datasource/graph"
+	"github.com/alibaba/pairec/v2/datasource/ha3engine"
+	"github.com/alibaba/pairec/v2/datasource/hbase"
+	"github.com/alibaba/pairec/v2/datasource/hbase_thrift"
+	"github.com/alibaba/pairec/v2/datasource/kafka"
+	"github.com/alibaba/pairec/v2/datasource/opensearch"
+	"github.com/alibaba/pairec/v2/datasource/registry"
+	"github.com/alibaba/pairec/v2/datasource/sls"
 	"github.com/alibaba/pairec/v2/filter"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/persist/clickhouse"
+	"github.com/alibaba/pairec/v2/persist/fs"
+	"github.com/alibaba/pairec/v2/persist/holo"
+	"github.com/alibaba/pairec/v2/persist/lindorm"
+	"github.com/alibaba/pairec/v2/persist/mysqldb"
+	"github.com/alibaba/pairec/v2/persist/redisdb"
+	"github.com/alibaba/pairec/v2/persist/tablestoredb"
 	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service"
+	"github.com/alibaba/pairec/v2/service/fallback"
+	"github.com/alibaba/pairec/v2/service/feature"
+	"github.com/alibaba/pairec/v2/service/general_rank"
 	"github.com/alibaba/pairec/v2/service/metrics"
+	"github.com/alibaba/pairec/v2/service/pagination"
+	"github.com/alibaba/pairec/v2/service/pipeline"
+	"github.com/alibaba/pairec/v2/service/rank"
 	"github.com/alibaba/pairec/v2/service/recall"
+	"github.com/alibaba/pairec/v2/service/recall/berecall"
 	"github.com/alibaba/pairec/v2/sort"
 )
 
-func register(conf *recconf.RecommendConfig) {
-	registerRecall(conf)
-	registerFilter(conf)
-	registerSort(conf)
+// dataSourceLoaders stage the datasources of the config in order, the staged datasources are added or replaced by name
+// when the config is accepted, and closed when the config is rejected
+var dataSourceLoaders = []struct {
+	name string
+	load func(*recconf.RecommendConfig)
+}{
+	{"mysql", mysqldb.Load},
+	{"redis", redisdb.Load},
+	{"tablestore", tablestoredb.Load},
+	{"sls", sls.Load},
+	{"kafka", kafka.Load},
+	{"datahub", datahub.Load},
+	{"be", beengine.Load},
+	{"graph", graph.Load},
+	{"ha3engine", ha3engine.Load},
+	{"opensearch", opensearch.Load},
+	{"hbase", hbase.Load},
+	{"hbase_thrift", hbase_thrift.Load},
+	{"hologres", holo.Load},
+	{"lindorm", lindorm.Load},
+	{"featurestore", fs.Load},
+	{"clickhouse", clickhouse.Load},
+}
+
+// loadDataSources stages the datasources of the config, the errors of all the datasources are returned as the ConfigError
+func loadDataSources(conf *recconf.RecommendConfig) error {
+	var errs []*recconf.ModuleError
+	for _, loader := range dataSourceLoaders {
+		loader := loader
+		if _, err := recconf.BuildModule("datasource", loader.name, "", func() bool {
+			loader.load(conf)
+			return true
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &recconf.ConfigError{Errors: errs}
+	}
+	return nil
+}
+
+// modulePreparers build the modules of the config without registering them, the datasources must be loaded before,
+// and holo must be loaded before loading some algorithm
+var modulePreparers = []func(*recconf.RecommendConfig) (func(), []*recconf.ModuleError){
+	algorithm.Prepare,
+	prepareRecall,
+	prepareFilter,
+	berecall.PrepareFilterWithConfig,
+	prepareSort,
+	feature.PrepareUserFeatureConfig,
+	feature.PrepareFeatureConfig,
+	service.PrepareSceneConfig,
+	pipeline.PreparePipelineConfigs,
+	fallback.PrepareFallbackConfig,
+	pagination.PreparePaginationConfig,
+}
+
+// sceneLoaders bind the scenes to the filters, sorts and recalls registered by name, they run after the modules
+// are registered
+var sceneLoaders = []func(*recconf.RecommendConfig){
+	filter.Load,
+	sort.Load,
+	service.Load,
+	general_rank.LoadGeneralRankWithConfig,
+	rank.LoadColdStartRankConfig,
+}
+
+// register builds the algorithms, recalls, filters, sorts, features and scene services of the config in a transaction,
+// they are registered together with the staged datasources only when all of them are built, otherwise the errors
+// of all the modules are returned as the ConfigError and the modules in use are not changed
+func register(conf *recconf.RecommendConfig) error {
+	var commits []func()
+	var errs []*recconf.ModuleError
+	for _, prepare := range modulePreparers {
+		commit, moduleErrs := prepare(conf)
+		commits = append(commits, commit)
+		errs = append(errs, moduleErrs...)
+	}
+
+	if len(errs) > 0 {
+		return &recconf.ConfigError{Errors: errs}
+	}
+
+	registry.Commit()
+	for _, commit := range commits {
+		commit()
+	}
+	loadScenes(conf)
 	registerMetrics(conf)
+
+	return nil
+}
+
+// applyConfig loads the datasources and registers the modules of the config, the errors of all the modules are returned
+func applyConfig(conf *recconf.RecommendConfig) error {
+	// the staged datasources are closed when the config is rejected, it does nothing after they are committed
+	defer registry.Discard()
+
+	if err := loadDataSources(conf); err != nil {
+		return err
+	}
+
+	return register(conf)
 }
 
-func registerFilter(conf *recconf.RecommendConfig) {
-	filter.RegisterFilterWithConfig(conf)
-	filter.RegisterFilter("UniqueFilter", filter.NewUniqueFilter())
+func loadScenes(conf *recconf.RecommendConfig) {
+	for _, load := range sceneLoaders {
+		load(conf)
+	}
 }
 
-func registerSort(conf *recconf.RecommendConfig) {
+func prepareFilter(conf *recconf.RecommendConfig) (func(), []*recconf.ModuleError) {
+	commit, errs := filter.PrepareFilterWithConfig(conf)
+	return func() {
+		commit()
+		filter.RegisterFilter("UniqueFilter", filter.NewUniqueFilter())
+	}, errs
+}
+
+func prepareSort(conf *recconf.RecommendConfig) (func(), []*recconf.ModuleError) {
+	var errs []*recconf.ModuleError
+	dppSorts := make(map[string]sort.ISort)
 	for _, conf_ := range conf.DPPConf {
-		sort.RegisterSort(conf_.Name, sort.NewDPPSort(conf_))
+		conf_ := conf_
+		s, err := recconf.BuildModule("sort", conf_.Name, "DPPSort", func() sort.ISort { return sort.NewDPPSort(conf_) })
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		dppSorts[conf_.Name] = s
 	}
-	sort.RegisterSortWithConfig(conf)
+
+	commit, sortErrs := sort.PrepareSortWithConfig(conf)
+	return func() {
+		for name, s := range dppSorts {
+			sort.RegisterSort(name, s)
+		}
+		commit()
+	}, append(errs, sortErrs...)
 }
 
-func registerRecall(conf *recconf.RecommendConfig) {
-	recall.RegisterRecall("ContextItemRecall", recall.NewContextItemRecall(recconf.RecallConfig{Name: "ContextItemRecall"}))
-	recall.Load(conf)
+func prepareRecall(conf *recconf.RecommendConfig) (func(), []*recconf.ModuleError) {
+	commit, errs := recall.Prepare(conf)
+	return func() {
+		recall.RegisterRecall("ContextItemRecall", recall.NewContextItemRecall(recconf.RecallConfig{Name: "ContextItemRecall"}))
+		commit()
+	}, errs
 }
 
 func registerMetrics(conf *recconf.RecommendConfig) {
 	metrics.Load(conf)
 }
+
+// logConfigError logs each error of the ConfigError, so all the errors are listed in the log
+func logConfigError(event string, err error) {
+	if configErr, ok := err.(*recconf.ConfigError); ok {
+		for _, moduleErr := range configErr.Errors {
+			log.Error(fmt.Sprintf("event=%s\tmodule=%s\tname=%s\ttype=%s\terror=%v", event, moduleErr.Module, moduleErr.Name, moduleErr.Type, moduleErr.Err))
+		}
+		return
+	}
+
+	log.Error(fmt.Sprintf("event=%s\terror=%v", event, err))
+}
@@ -0,0 +1,87 @@
+package pairec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/persist/mysqldb"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service/recall"
+)
+
+func TestRegisterRejectAtomically(t *testing.T) {
+	conf, _ := recconf.ParseConfig([]byte(`{
+		"RecallConfs": [
+			{"Name": "register_test_recall", "RecallType": "MockRecall", "RecallCount": 10},
+			{"Name": "register_test_unknown", "RecallType": "UnknownRecall"}
+		],
+		"FilterConfs": [
+			{"Name": "register_test_filter", "FilterType": "User2ItemExposureFilter", "DaoConf": {"AdapterType": "redis", "RedisName": "not_exist"}}
+		]
+	}`))
+
+	err := register(conf)
+	var configErr *recconf.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 2 {
+		t.Fatalf("expect the errors of the unknown recall and the filter without redis, got %v", err)
+	}
+	if _, err := recall.GetRecall("register_test_recall"); err == nil {
+		t.Error("expect no recall registered when the config is rejected")
+	}
+
+	conf.RecallConfs = conf.RecallConfs[:1]
+	conf.FilterConfs = nil
+	if err := register(conf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recall.GetRecall("register_test_recall"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApplyConfigDiscardDataSources(t *testing.T) {
+	conf, _ := recconf.ParseConfig([]byte(`{
+		"MysqlConfs": {
+			"register_test_mysql": {"DSN": "user:password@tcp(127.0.0.1:3306)/test"}
+		},
+		"RecallConfs": [
+			{"Name": "register_test_unknown", "RecallType": "UnknownRecall"}
+		]
+	}`))
+
+	if err := applyConfig(conf); err == nil {
+		t.Fatal("expect the error of the unknown recall")
+	}
+	if _, err := mysqldb.GetMysql("register_test_mysql"); err == nil {
+		t.Error("expect no datasource added when the config is rejected")
+	}
+
+	conf.RecallConfs = nil
+	if err := applyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mysqldb.GetMysql("register_test_mysql"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegisterRejectRecallMergeConf(t *testing.T) {
+	conf, _ := recconf.ParseConfig([]byte(`{
+		"SceneConfs": {
+			"register_test_scene": {
+				"default": {"RecallNames": [], "RecallMergeConf": {"Fusion": "unknown"}}
+			}
+		},
+		"PipelineConfs": {
+			"register_test_scene": [
+				{"Name": "register_test_pipeline", "RecallMergeConf": {"Normalization": "unknown"}}
+			]
+		}
+	}`))
+
+	err := register(conf)
+	var configErr *recconf.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 2 {
+		t.Fatalf("expect the errors of the category and the pipeline merge config, got %v", err)
+	}
+}
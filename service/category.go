@@ -12,6 +12,7 @@ type Category struct {
 	CategoryName string
 	recalls      []recall.Recall
 	recallNames  []string
+	recallMerger *recall.RecallMerger
 }
 
 func NewCategory(name string) *Category {
@@ -39,6 +40,16 @@ func (c *Category) Init(config recconf.CategoryConfig) {
 
 	c.recalls = recalls
 	c.recallNames = recallNames
+
+	c.recallMerger = nil
+	if config.RecallMergeConf != nil {
+		merger, err := recall.NewRecallMerger(*config.RecallMergeConf)
+		if err != nil {
+			log.Error(fmt.Sprintf("module=category init	category=%s	error=%v", c.CategoryName, err))
+		} else {
+			c.recallMerger = merger
+		}
+	}
 }
 
 func (c *Category) GetRecalls() []recall.Recall {
@@ -48,3 +59,8 @@ func (c *Category) GetRecalls() []recall.Recall {
 func (c *Category) GetRecallNames() []string {
 	return c.recallNames
 }
+
+// GetRecallMerger returns the merger of the recall items, nil means the items are not merged
+func (c *Category) GetRecallMerger() *recall.RecallMerger {
+	return c.recallMerger
+}
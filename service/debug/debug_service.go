@@ -75,6 +75,14 @@ func (k *KafkaOutput) WriteLog(logs map[string]interface{}) {
 
 var fileOutputMux sync.Mutex
 
+// writeLogWg counts the debug logs writing asynchronously
+var writeLogWg sync.WaitGroup
+
+// Wait waits for the debug logs writing asynchronously, it is used on shutdown after the requests are drained
+func Wait() {
+	writeLogWg.Wait()
+}
+
 type FileOutput struct {
 	path       string
 	maxFileNum int
@@ -296,6 +304,14 @@ func (d *DebugService) init(config *recconf.DebugConfig) {
 	}
 }
 
+func (d *DebugService) goWriteLog(f func()) {
+	writeLogWg.Add(1)
+	go func() {
+		defer writeLogWg.Done()
+		f()
+	}()
+}
+
 func (d *DebugService) WriteRecallLog(user *module.User, items []*module.Item, context *context.RecommendContext) {
 	if d.logFlag {
 		triggerMap := make(map[module.ItemId]string, len(items))
@@ -307,13 +323,13 @@ func (d *DebugService) WriteRecallLog(user *module.User, items []*module.Item, c
 			newItem.Score = item.Score
 			newItems = append(newItems, newItem)
 		}
-		go d.doWriteRecallLog(user, newItems, context, triggerMap)
+		d.goWriteLog(func() { d.doWriteRecallLog(user, newItems, context, triggerMap) })
 	}
 }
 
 func (d *DebugService) WriteFilterLog(user *module.User, items []*module.Item, context *context.RecommendContext) {
 	if d.logFlag {
-		go d.doWriteFilterLog(user, items, context)
+		d.goWriteLog(func() { d.doWriteFilterLog(user, items, context) })
 	}
 }
 
@@ -321,7 +337,7 @@ func (d *DebugService) WriteGeneralLog(user *module.User, items []*module.Item,
 	if d.logFlag {
 		newItems := make([]*module.Item, len(items))
 		copy(newItems, items)
-		go d.doWriteGeneralLog(user, newItems, context)
+		d.goWriteLog(func() { d.doWriteGeneralLog(user, newItems, context) })
 	}
 }
 
@@ -331,19 +347,19 @@ func (d *DebugService) WriteRankLog(user *module.User, items []*module.Item, con
 		for _, item := range items {
 			newItems = append(newItems, item.DeepClone())
 		}
-		go d.doWriteRankLog(user, newItems, context)
+		d.goWriteLog(func() { d.doWriteRankLog(user, newItems, context) })
 	}
 }
 
 func (d *DebugService) WriteSortLog(user *module.User, items []*module.Item, context *context.RecommendContext) {
 	if d.logFlag {
-		go d.doWriteSortLog(user, items, context)
+		d.goWriteLog(func() { d.doWriteSortLog(user, items, context) })
 	}
 }
 
 func (d *DebugService) WriteRecommendLog(user *module.User, items []*module.Item, context *context.RecommendContext) {
 	if d.logFlag {
-		go d.doWriteRecommendLog(user, items, context)
+		d.goWriteLog(func() { d.doWriteRecommendLog(user, items, context) })
 	}
 }
 
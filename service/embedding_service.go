@@ -124,7 +124,7 @@ func (r *EmbeddingService) Rank(user *module.User, items []*module.Item, context
 			defer wg.Done()
 
 			// run 返回原始的值，然后处理返回数据// 注册配置
-			ret, err := algorithm.Run(algo, algoData.GetFeatures())
+			ret, err := algorithm.RunWithContext(context.Context(), algo, algoData.GetFeatures())
 			if err != nil {
 				log.Error(fmt.Sprintf("requestId=%s\terror=run algorithm error(%v)", context.RecommendId, err))
 				algoData.SetError(err)
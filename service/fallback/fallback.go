@@ -7,6 +7,23 @@ import (
 	"github.com/alibaba/pairec/v2/module"
 )
 
+const (
+	Fallback_Type_FeatureStore = "featurestore"
+	Fallback_Type_Redis        = "redis"
+	Fallback_Type_Hologres     = "hologres"
+	Fallback_Type_File         = "file"
+	Fallback_Type_LastResponse = "last_response"
+)
+
+// Fallback_Recommend_Timeout is the budget of the fallback, it is not bounded by the request deadline
+const Fallback_Recommend_Timeout = 500 * time.Millisecond
+
+const (
+	Fallback_Reason_Timeout       = "timeout"
+	Fallback_Reason_ItemNotEnough = "item_not_enough"
+	Fallback_Reason_Panic         = "panic"
+)
+
 type IFallback interface {
 	GetTimer() *time.Timer
 	CompleteItemsIfNeed() bool
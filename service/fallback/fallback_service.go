@@ -2,8 +2,10 @@ package fallback
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 
+	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/utils"
 )
@@ -67,20 +69,113 @@ func (r *FallbackService) GetFallback(sceneName string) (ret IFallback) {
 	return
 }
 
+// GetSceneFallback returns the fallback of the scene and category, the fallback of the default category is used
+// when the category has no fallback
+func (r *FallbackService) GetSceneFallback(sceneName, category string) IFallback {
+	if category != "" && category != "default" {
+		if fallback := r.GetFallback(fallbackKey(sceneName, category)); fallback != nil {
+			return fallback
+		}
+	}
+
+	return r.GetFallback(sceneName)
+}
+
+// fallbackKey is the scene name for the default category, keeps compatible with RegisterFallback
+func fallbackKey(sceneName, category string) string {
+	if category == "default" {
+		return sceneName
+	}
+
+	return sceneName + "#" + category
+}
+
+func RegisterCategoryFallback(sceneName, category string, fallback IFallback) {
+	DefaultFallbackService().AddFallback(fallbackKey(sceneName, category), fallback)
+}
+
+// NewFallback creates the fallback by the FallbackType of the config
+func NewFallback(conf recconf.FallbackConfig) IFallback {
+	switch conf.FallbackType {
+	case "", Fallback_Type_FeatureStore:
+		if f := NewFeatureStoreFallback(conf); f != nil {
+			return f
+		}
+	case Fallback_Type_Redis:
+		if f := NewRedisFallback(conf); f != nil {
+			return f
+		}
+	case Fallback_Type_Hologres:
+		if f := NewHologresFallback(conf); f != nil {
+			return f
+		}
+	case Fallback_Type_File:
+		if f := NewFileFallback(conf); f != nil {
+			return f
+		}
+	case Fallback_Type_LastResponse:
+		if f := NewLastResponseFallback(conf); f != nil {
+			return f
+		}
+	default:
+		log.Error(fmt.Sprintf("event=NewFallback	error=fallback type not support, type:%s", conf.FallbackType))
+	}
+
+	return nil
+}
+
 func LoadFallbackConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareFallbackConfig(config)
+	for _, err := range errs {
+		log.Error(fmt.Sprintf("event=LoadFallbackConfig\terror=%v", err))
+	}
+	commit()
+}
+
+// PrepareFallbackConfig builds the changed fallbacks of the scenes and categories, commit registers them
+// and removes the fallbacks which are not in the config any more
+func PrepareFallbackConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]IFallback)
+	signs := make(map[string]string)
 	for scene, conf := range config.SceneConfs {
-		if categoryConf, ok := conf["default"]; ok && categoryConf.FallbackConfig != nil {
+		for category, categoryConf := range conf {
+			if categoryConf.FallbackConfig == nil {
+				continue
+			}
+
+			key := fallbackKey(scene, category)
 			sign, _ := json.Marshal(categoryConf.FallbackConfig)
-			if utils.Md5(string(sign)) == fallbackSigns[scene] {
+			if utils.Md5(string(sign)) == fallbackSigns[key] {
+				signs[key] = fallbackSigns[key]
 				continue
 			}
 
-			f := NewFeatureStoreFallback(*categoryConf.FallbackConfig)
-			if f != nil {
-				RegisterFallback(scene, f)
+			fallbackConf := *categoryConf.FallbackConfig
+			f, err := recconf.BuildModule("fallback", key, fallbackConf.FallbackType, func() IFallback { return NewFallback(fallbackConf) })
+			if err != nil {
+				errs = append(errs, err)
+				// the fallback in use is kept when the new one fails to construct
+				if old, ok := fallbackSigns[key]; ok {
+					signs[key] = old
+				}
+				continue
 			}
-		} else if ok && categoryConf.FallbackConfig == nil {
-			RemoveFallback(scene)
+
+			built[key] = f
+			signs[key] = utils.Md5(string(sign))
 		}
 	}
+
+	return func() {
+		for key, f := range built {
+			RegisterFallback(key, f)
+		}
+		// remove the fallbacks which are not in the config any more
+		for key := range fallbackSigns {
+			if _, ok := signs[key]; !ok {
+				RemoveFallback(key)
+			}
+		}
+		fallbackSigns = signs
+	}, errs
 }
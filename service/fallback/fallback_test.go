@@ -0,0 +1,97 @@
+package fallback
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type testParam map[string]interface{}
+
+func (p testParam) GetParameter(name string) interface{} {
+	return p[name]
+}
+
+func TestLoadFallbackConfig(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "fallback.json")
+	content := `["item1", "item2:hot", {"item_id":"item3", "retrieve_id":"hot", "score":0.5}]`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	LoadFallbackConfig(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"home_feed": {
+				"default": {FallbackConfig: &recconf.FallbackConfig{FallbackType: Fallback_Type_File, FilePath: filePath}},
+				"video":   {FallbackConfig: &recconf.FallbackConfig{FallbackType: Fallback_Type_LastResponse}},
+			},
+		},
+	})
+
+	if _, ok := DefaultFallbackService().GetSceneFallback("home_feed", "default").(*FileFallback); !ok {
+		t.Error("expect the file fallback for the default category")
+	}
+	if _, ok := DefaultFallbackService().GetSceneFallback("home_feed", "article").(*FileFallback); !ok {
+		t.Error("expect the category without fallback uses the default one")
+	}
+	if _, ok := DefaultFallbackService().GetSceneFallback("home_feed", "video").(*LastResponseFallback); !ok {
+		t.Error("expect the last response fallback for the video category")
+	}
+
+	ctx := context.NewRecommendContext()
+	ctx.Size = 10
+	ctx.Param = testParam{"uid": "u1"}
+	items := DefaultFallbackService().GetSceneFallback("home_feed", "default").Recommend(ctx)
+	if len(items) != 3 {
+		t.Errorf("expect 3 items of the file, got %v", items)
+	}
+	for _, item := range items {
+		if item.Id == "item3" && (item.RetrieveId != "hot" || item.Score != 0.5) {
+			t.Errorf("expect item3 with RetrieveId hot and score 0.5, got %v", item)
+		}
+	}
+
+	LoadFallbackConfig(&recconf.RecommendConfig{})
+	if DefaultFallbackService().GetSceneFallback("home_feed", "video") != nil {
+		t.Error("expect the fallback removed")
+	}
+}
+
+func TestLastResponseFallback(t *testing.T) {
+	f := NewLastResponseFallback(recconf.FallbackConfig{})
+
+	ctx := context.NewRecommendContext()
+	ctx.Size = 10
+	ctx.Param = testParam{"uid": "u1", "scene": "home", "category": "video"}
+	if items := f.Recommend(ctx); len(items) != 0 {
+		t.Errorf("expect no items before the response saved, got %v", items)
+	}
+
+	var response []*module.Item
+	for _, id := range []string{"item3", "item:1", "item2"} {
+		item := module.NewItem(id)
+		item.RetrieveId = "recall"
+		response = append(response, item)
+	}
+	f.SaveResponse(ctx, response)
+
+	items := f.Recommend(ctx)
+	if len(items) != 3 || items[0].Id != "item3" || items[1].Id != "item:1" || items[2].Id != "item2" || items[1].RetrieveId != "recall" {
+		t.Errorf("expect the last response, got %v", items)
+	}
+
+	ctx.Param = testParam{"uid": "u2", "scene": "home", "category": "video"}
+	if items := f.Recommend(ctx); len(items) != 0 {
+		t.Errorf("expect no items for the other user, got %v", items)
+	}
+
+	// the category using the fallback of the default category has its own response
+	ctx.Param = testParam{"uid": "u1", "scene": "home", "category": "article"}
+	if items := f.Recommend(ctx); len(items) != 0 {
+		t.Errorf("expect no items for the other category, got %v", items)
+	}
+}
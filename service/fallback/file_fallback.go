@@ -0,0 +1,81 @@
+package fallback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// FileFallback loads the item list from the static json file shipped with the service, the file is a json array, e.g.
+// ["item1", "item2:hot", {"item_id":"item3", "retrieve_id":"hot", "score":0.5}]
+type FileFallback struct {
+	*ItemListFallback
+
+	itemIds []string
+}
+
+func NewFileFallback(conf recconf.FallbackConfig) *FileFallback {
+	itemIds, err := loadFallbackFile(conf.FilePath)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewFileFallback\tfile=%s\terror=%v", conf.FilePath, err))
+		return nil
+	}
+
+	fallback := &FileFallback{
+		itemIds: itemIds,
+	}
+
+	fallback.ItemListFallback, err = NewItemListFallback("FileFallback", conf, fallback)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewFileFallback\terror=%v", err))
+		return nil
+	}
+
+	return fallback
+}
+
+func (r *FileFallback) LoadItemIds(context *context.RecommendContext) ([]string, error) {
+	return r.itemIds, nil
+}
+
+func loadFallbackFile(filePath string) ([]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	itemIds := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			itemIds = append(itemIds, v)
+		case map[string]any:
+			itemId := utils.ToString(v["item_id"], "")
+			if itemId == "" {
+				continue
+			}
+			retrieveId := utils.ToString(v["retrieve_id"], "")
+			if score, ok := v["score"]; ok {
+				itemIds = append(itemIds, fmt.Sprintf("%s:%s:%v", itemId, retrieveId, utils.ToFloat(score, 0)))
+			} else {
+				itemIds = append(itemIds, fmt.Sprintf("%s:%s", itemId, retrieveId))
+			}
+		}
+	}
+
+	if len(itemIds) == 0 {
+		return nil, fmt.Errorf("no items found in the file")
+	}
+
+	return itemIds, nil
+}
@@ -0,0 +1,84 @@
+package fallback
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/persist/holo"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// HologresFallback loads the item list from the hologres table, the table has the same format as the global hot recall
+type HologresFallback struct {
+	*ItemListFallback
+
+	db             *sql.DB
+	table          string
+	itemIdField    string
+	itemScoreField string
+}
+
+func NewHologresFallback(conf recconf.FallbackConfig) *HologresFallback {
+	hologres, err := holo.GetPostgres(conf.HologresName)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewHologresFallback\terror=%v", err))
+		return nil
+	}
+
+	fallback := &HologresFallback{
+		db:             hologres.DB,
+		table:          conf.HologresTableName,
+		itemIdField:    conf.ItemIdField,
+		itemScoreField: conf.ItemScoreField,
+	}
+
+	fallback.ItemListFallback, err = NewItemListFallback("HologresFallback", conf, fallback)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewHologresFallback\terror=%v", err))
+		return nil
+	}
+
+	return fallback
+}
+
+func (r *HologresFallback) LoadItemIds(context *context.RecommendContext) ([]string, error) {
+	itemField := "item_ids"
+	if r.itemIdField != "" {
+		if r.itemScoreField == "" {
+			itemField = r.itemIdField
+		} else {
+			// itemid::score, the empty RetrieveId uses the default one
+			itemField = fmt.Sprintf("CONCAT_WS('::', %s, %s::text)", r.itemIdField, r.itemScoreField)
+		}
+	}
+
+	builder := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	builder.Select(itemField)
+	builder.From(r.table)
+
+	// compatible with the original data format
+	if r.itemScoreField == "" {
+		builder.Where(builder.Equal("trigger_id", "-1"))
+	}
+
+	sqlquery, args := builder.Build()
+	rows, err := r.db.QueryContext(context.Context(), sqlquery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var itemIds []string
+	for rows.Next() {
+		var ids string
+		if err := rows.Scan(&ids); err == nil {
+			itemIds = append(itemIds, strings.Split(ids, ",")...)
+		}
+	}
+
+	return itemIds, rows.Err()
+}
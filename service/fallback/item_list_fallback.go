@@ -0,0 +1,222 @@
+package fallback
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/persist/cache"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// ItemIdsLoader loads the fallback items, every item id is formatted as itemid, itemid:RetrieveId or itemid:RetrieveId:score
+type ItemIdsLoader interface {
+	LoadItemIds(context *context.RecommendContext) ([]string, error)
+}
+
+// ItemListFallback recommends the item list loaded by the loader, the item list is cached in the local cache
+// and merged with the items of the request param item_list
+type ItemListFallback struct {
+	name   string
+	loader ItemIdsLoader
+
+	timeout time.Duration
+
+	cache     cache.Cache
+	cacheTime int
+
+	completeItemsIfNeed bool
+}
+
+func NewItemListFallback(name string, conf recconf.FallbackConfig, loader ItemIdsLoader) (*ItemListFallback, error) {
+	cacheConfig := conf.CacheConfig
+	if cacheConfig == "" {
+		cacheConfig = "{\"defaultExpiration\":1800, \"cleanupInterval\":1800}"
+	}
+
+	cacheTime := conf.CacheTime
+	if cacheTime <= 0 {
+		cacheTime = 7200
+	}
+
+	fallbackCache, err := cache.NewCache("localCache", cacheConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemListFallback{
+		name:   name,
+		loader: loader,
+
+		timeout: time.Duration(conf.Timeout) * time.Millisecond,
+
+		cache:     fallbackCache,
+		cacheTime: cacheTime,
+
+		completeItemsIfNeed: conf.CompleteItemsIfNeed,
+	}, nil
+}
+
+func (r *ItemListFallback) GetTimer() *time.Timer {
+	return time.NewTimer(r.timeout)
+}
+
+func (r *ItemListFallback) CompleteItemsIfNeed() bool {
+	return r.completeItemsIfNeed
+}
+
+func (r *ItemListFallback) Recommend(context *context.RecommendContext) []*module.Item {
+	start := time.Now()
+	moduleName := "Fallback"
+
+	var itemIds []string
+	var useCache bool
+	if r.cache != nil {
+		if itemStr, ok := r.cache.Get(moduleName).(string); ok && itemStr != "" {
+			itemIds = strings.Split(itemStr, ",")
+			useCache = true
+		}
+	}
+
+	if !useCache {
+		ids, err := r.loader.LoadItemIds(context)
+		if err != nil {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=%s\terror=%v", context.RecommendId, r.name, err))
+		}
+		for _, id := range ids {
+			if id != "" {
+				itemIds = append(itemIds, id)
+			}
+		}
+
+		if r.cache != nil && len(itemIds) > 0 {
+			itemStr := strings.Join(itemIds, ",")
+			go func() {
+				if err := r.cache.Put(moduleName, itemStr, time.Duration(r.cacheTime)*time.Second); err != nil {
+					log.Error(fmt.Sprintf("requestId=%s\tmodule=%s\terror=%v", context.RecommendId, r.name, err))
+				}
+			}()
+		}
+	}
+
+	ret := mergeFallbackItems(context, parseFallbackItems(itemIds, moduleName))
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=fallback\tname=%s\tuseCache=%v\tcost=%d", context.RecommendId, r.name, useCache, utils.CostTime(start)))
+
+	if len(ret) > context.Size {
+		return ret[:context.Size]
+	}
+
+	return ret
+}
+
+// parseFallbackItems parses the item ids formatted as itemid, itemid:RetrieveId or itemid:RetrieveId:score
+func parseFallbackItems(itemIds []string, retrieveId string) map[module.ItemId]*module.Item {
+	fallbackItemsMap := make(map[module.ItemId]*module.Item)
+	for _, id := range itemIds {
+		strs := strings.Split(id, ":")
+		if strs[0] == "" {
+			continue
+		}
+
+		item := module.NewItem(strs[0])
+		item.RetrieveId = retrieveId
+		if len(strs) > 1 && strs[1] != "" {
+			item.RetrieveId = strs[1]
+		}
+		if len(strs) > 2 {
+			item.Score = utils.ToFloat(strs[2], float64(0))
+			item.AddAlgoScore("hot_score", item.Score)
+		}
+
+		if seenItem, ok := fallbackItemsMap[item.Id]; ok {
+			seenItem.Score += item.Score
+		} else {
+			fallbackItemsMap[item.Id] = item
+		}
+	}
+
+	return fallbackItemsMap
+}
+
+// contextFallbackItems returns the items of the request param item_list
+func contextFallbackItems(context *context.RecommendContext) map[module.ItemId]*module.Item {
+	contextItemsMap := make(map[module.ItemId]*module.Item)
+
+	itemList, ok := context.GetParameter("item_list").([]map[string]any)
+	if !ok {
+		return contextItemsMap
+	}
+
+	for _, itemData := range itemList {
+		itemId := itemData["item_id"]
+		itemIdStr := utils.ToString(itemId, "")
+		if itemIdStr == "" {
+			continue
+		}
+		item := module.NewItem(itemIdStr)
+		item.RetrieveId = "ContextItemRecall"
+
+		for k, v := range itemData {
+			if k == "item_id" {
+				continue
+			} else if k == "score" {
+				item.Score = utils.ToFloat(v, 0)
+			} else {
+				item.AddProperty(k, v)
+			}
+		}
+
+		if seenItem, ok := contextItemsMap[item.Id]; ok {
+			seenItem.Score += item.Score
+		} else {
+			contextItemsMap[item.Id] = item
+		}
+	}
+
+	return contextItemsMap
+}
+
+// mergeFallbackItems merges the fallback items with the items of the request param item_list
+func mergeFallbackItems(context *context.RecommendContext, fallbackItemsMap map[module.ItemId]*module.Item) []*module.Item {
+	contextItemsMap := contextFallbackItems(context)
+
+	// 优先选择同时出现在上下文召回和兜底数据集的 item，按召回分排序
+	// 次选只出现在上下文召回的 item
+	// 最后用只出现在兜底数据集的 item 补全
+	var firstPriorityItems, secondPriorityItems, remainingItems []*module.Item
+
+	for id, item := range contextItemsMap {
+		if fallbackItem, ok := fallbackItemsMap[id]; ok {
+			item.Score += fallbackItem.Score
+			delete(fallbackItemsMap, id)
+			firstPriorityItems = append(firstPriorityItems, item)
+		} else {
+			secondPriorityItems = append(secondPriorityItems, item)
+		}
+	}
+	for _, item := range fallbackItemsMap {
+		remainingItems = append(remainingItems, item)
+	}
+
+	sort.Sort(sort.Reverse(module.ItemScoreSlice(firstPriorityItems)))
+	rand.Shuffle(len(secondPriorityItems), func(i, j int) {
+		secondPriorityItems[i], secondPriorityItems[j] = secondPriorityItems[j], secondPriorityItems[i]
+	})
+	rand.Shuffle(len(remainingItems), func(i, j int) {
+		remainingItems[i], remainingItems[j] = remainingItems[j], remainingItems[i]
+	})
+
+	ret := make([]*module.Item, 0, len(firstPriorityItems)+len(secondPriorityItems)+len(remainingItems))
+	ret = append(ret, firstPriorityItems...)
+	ret = append(ret, secondPriorityItems...)
+	ret = append(ret, remainingItems...)
+
+	return ret
+}
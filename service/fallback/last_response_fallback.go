@@ -0,0 +1,146 @@
+package fallback
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/persist/cache"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+// IResponseSaver is implemented by the fallback which needs the items of the successful recommend
+type IResponseSaver interface {
+	SaveResponse(context *context.RecommendContext, items []*module.Item)
+}
+
+// LastResponseFallback serves the last good response of the user from the local cache
+type LastResponseFallback struct {
+	timeout time.Duration
+
+	cache     cache.Cache
+	cacheTime int
+
+	completeItemsIfNeed bool
+}
+
+func NewLastResponseFallback(conf recconf.FallbackConfig) *LastResponseFallback {
+	cacheConfig := conf.CacheConfig
+	if cacheConfig == "" {
+		cacheConfig = "{\"defaultExpiration\":1800, \"cleanupInterval\":1800}"
+	}
+
+	cacheTime := conf.CacheTime
+	if cacheTime <= 0 {
+		cacheTime = 7200
+	}
+
+	fallbackCache, err := cache.NewCache("localCache", cacheConfig)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewLastResponseFallback\terror=%v", err))
+		return nil
+	}
+
+	return &LastResponseFallback{
+		timeout: time.Duration(conf.Timeout) * time.Millisecond,
+
+		cache:     fallbackCache,
+		cacheTime: cacheTime,
+
+		completeItemsIfNeed: conf.CompleteItemsIfNeed,
+	}
+}
+
+func (r *LastResponseFallback) GetTimer() *time.Timer {
+	return time.NewTimer(r.timeout)
+}
+
+func (r *LastResponseFallback) CompleteItemsIfNeed() bool {
+	return r.completeItemsIfNeed
+}
+
+// lastResponseItem is the item of the last response cached, it is encoded in json so the id can have any character
+type lastResponseItem struct {
+	Id         string  `json:"id"`
+	RetrieveId string  `json:"retrieve_id,omitempty"`
+	Score      float64 `json:"score"`
+}
+
+// cacheKey returns the key of the user in the scene and category, the categories using the fallback of the default category
+// have their own responses
+func (r *LastResponseFallback) cacheKey(context *context.RecommendContext) string {
+	uid := utils.ToString(context.GetParameter("uid"), "")
+	if uid == "" {
+		return ""
+	}
+	scene := utils.ToString(context.GetParameter("scene"), "")
+	category := utils.ToString(context.GetParameter("category"), "")
+
+	return scene + "#" + category + "#" + uid
+}
+
+func (r *LastResponseFallback) SaveResponse(context *context.RecommendContext, items []*module.Item) {
+	key := r.cacheKey(context)
+	if key == "" || len(items) == 0 {
+		return
+	}
+
+	responseItems := make([]lastResponseItem, 0, len(items))
+	for _, item := range items {
+		responseItems = append(responseItems, lastResponseItem{Id: string(item.Id), RetrieveId: item.RetrieveId, Score: item.Score})
+	}
+	data, err := json.Marshal(responseItems)
+	if err == nil {
+		err = r.cache.Put(key, data, time.Duration(r.cacheTime)*time.Second)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=LastResponseFallback\tkey=%s\terror=%v", context.RecommendId, key, err))
+	}
+}
+
+func (r *LastResponseFallback) Recommend(context *context.RecommendContext) []*module.Item {
+	start := time.Now()
+
+	var responseItems []lastResponseItem
+	key := r.cacheKey(context)
+	if data, ok := r.cache.Get(key).([]byte); ok && key != "" {
+		if err := json.Unmarshal(data, &responseItems); err != nil {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=LastResponseFallback\tkey=%s\terror=%v", context.RecommendId, key, err))
+		}
+	}
+
+	var ret []*module.Item
+	if len(responseItems) > 0 {
+		// keep the order of the last response
+		exists := make(map[string]bool, len(responseItems))
+		for _, responseItem := range responseItems {
+			if responseItem.Id == "" || exists[responseItem.Id] {
+				continue
+			}
+			exists[responseItem.Id] = true
+
+			item := module.NewItem(responseItem.Id)
+			item.RetrieveId = responseItem.RetrieveId
+			if item.RetrieveId == "" {
+				item.RetrieveId = "Fallback"
+			}
+			item.Score = responseItem.Score
+			item.AddAlgoScore("hot_score", item.Score)
+			ret = append(ret, item)
+		}
+	} else {
+		ret = mergeFallbackItems(context, nil)
+	}
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=fallback\tname=LastResponseFallback\tkey=%s\tcount=%d\tcost=%d", context.RecommendId, key, len(responseItems), utils.CostTime(start)))
+
+	if len(ret) > context.Size {
+		return ret[:context.Size]
+	}
+
+	return ret
+}
@@ -0,0 +1,52 @@
+package fallback
+
+import (
+	"fmt"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/persist/redisdb"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/gomodule/redigo/redis"
+)
+
+// RedisFallback loads the item list from the redis list of the key RedisPrefix + RedisDefaultKey
+type RedisFallback struct {
+	*ItemListFallback
+
+	redis *redisdb.Redis
+	key   string
+}
+
+func NewRedisFallback(conf recconf.FallbackConfig) *RedisFallback {
+	redis, err := redisdb.GetRedis(conf.RedisName)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewRedisFallback\terror=%v", err))
+		return nil
+	}
+
+	key := conf.RedisDefaultKey
+	if key == "" {
+		key = "fallback"
+	}
+
+	fallback := &RedisFallback{
+		redis: redis,
+		key:   conf.RedisPrefix + key,
+	}
+
+	fallback.ItemListFallback, err = NewItemListFallback("RedisFallback", conf, fallback)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=NewRedisFallback\terror=%v", err))
+		return nil
+	}
+
+	return fallback
+}
+
+func (r *RedisFallback) LoadItemIds(context *context.RecommendContext) ([]string, error) {
+	conn := r.redis.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("LRANGE", r.key, 0, -1))
+}
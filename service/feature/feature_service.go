@@ -192,7 +192,10 @@ func (s *FeatureService) LoadFeaturesForPipelineRank(user *module.User, items []
 
 }
 
-func LoadFeatureConfig(config *recconf.RecommendConfig) {
+// PrepareFeatureConfig builds the features of the changed scenes without loading them, commit loads the features built.
+// The construction errors of all the scenes are returned, and nothing should be committed when there are errors.
+func PrepareFeatureConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string][]*Feature)
 	for name, sceneConf := range config.FeatureConfs {
 		if _, ok := featureService.FeatureSceneMap[name]; ok {
 			if signOfFeatureLoadConfs(sceneConf.FeatureLoadConfs) == featureService.FeatureSceneSigns[name] {
@@ -200,19 +203,46 @@ func LoadFeatureConfig(config *recconf.RecommendConfig) {
 			}
 		}
 
-		var features []*Feature
-		for _, conf := range sceneConf.FeatureLoadConfs {
-			f := LoadWithConfig(conf)
-			features = append(features, f)
+		sceneConf := sceneConf
+		features, err := recconf.BuildModule("feature", name, "", func() []*Feature {
+			features := make([]*Feature, 0, len(sceneConf.FeatureLoadConfs))
+			for _, conf := range sceneConf.FeatureLoadConfs {
+				features = append(features, LoadWithConfig(conf))
+			}
+			return features
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		featureService.FeatureSceneAsyncMap[name] = false
-		if sceneConf.AsynLoadFeature {
-			featureService.FeatureSceneAsyncMap[name] = true
+		built[name] = features
+	}
+
+	commit = func() {
+		for name, features := range built {
+			sceneConf := config.FeatureConfs[name]
+			featureService.FeatureSceneAsyncMap[name] = false
+			if sceneConf.AsynLoadFeature {
+				featureService.FeatureSceneAsyncMap[name] = true
+			}
+			featureService.FeatureSceneMap[name] = features
+			featureService.FeatureSceneSigns[name] = signOfFeatureLoadConfs(sceneConf.FeatureLoadConfs)
 		}
-		featureService.FeatureSceneMap[name] = features
-		featureService.FeatureSceneSigns[name] = signOfFeatureLoadConfs(sceneConf.FeatureLoadConfs)
 	}
+
+	return commit, errs
+}
+
+// LoadFeatureConfig loads the features of the changed scenes, it panics with the errors of all the scenes
+// when any feature fails to construct, and no scene is changed in that case
+func LoadFeatureConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareFeatureConfig(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
+	}
+
+	commit()
 }
 
 func signOfFeatureLoadConfs(confs []recconf.FeatureLoadConfig) string {
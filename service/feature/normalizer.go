@@ -14,22 +14,37 @@ type Normalizer interface {
 	Apply(value interface{}) interface{}
 }
 
-func NewNormalizer(name, expression string) Normalizer {
+// NormalizerFactory creates the Normalizer of the name, expression is the Expression of the feature config
+type NormalizerFactory func(expression string) Normalizer
+
+// normalizerFactories are the factories of the normalizers used by NewNormalizer
+var normalizerFactories = make(map[string]NormalizerFactory)
+
+// RegisterNormalizerFactory registers the factory of the normalizer, so the custom Normalizer can be configured by Normalizer like the built-in ones,
+// the factory of the same name is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterNormalizerFactory(name string, factory NormalizerFactory) {
+	if factory == nil {
+		panic("NormalizerFactory is nil, name:" + name)
+	}
+
+	normalizerFactories[name] = factory
+}
+
+func init() {
+	RegisterNormalizerFactory("hour_in_day", func(expression string) Normalizer { return &CreateHourNormalizer{} })
+	RegisterNormalizerFactory("weekday", func(expression string) Normalizer { return &CreateDayNormalizer{} })
+	RegisterNormalizerFactory("random", func(expression string) Normalizer { return NewCreateRandomNormalizer() })
+	RegisterNormalizerFactory("const_value", func(expression string) Normalizer { return NewCreateConstValueNormalizer() })
+	RegisterNormalizerFactory("expression", func(expression string) Normalizer { return NewExpressionNormalizer(expression) })
+}
 
-	var normalize Normalizer
-	if name == "hour_in_day" {
-		normalize = &CreateHourNormalizer{}
-	} else if name == "weekday" {
-		normalize = &CreateDayNormalizer{}
-	} else if name == "random" {
-		normalize = NewCreateRandomNormalizer()
-	} else if name == "const_value" {
-		normalize = NewCreateConstValueNormalizer()
-	} else if name == "expression" {
-		normalize = NewExpressionNormalizer(expression)
+// NewNormalizer creates the normalizer of the name, nil if the name is not registered
+func NewNormalizer(name, expression string) Normalizer {
+	if factory, ok := normalizerFactories[name]; ok {
+		return factory(expression)
 	}
 
-	return normalize
+	return nil
 }
 
 type CreateHourNormalizer struct {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/alibaba/pairec/v2/context"
 	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
 )
 
 type FeatureOp interface {
@@ -13,19 +14,35 @@ type FeatureOp interface {
 	ItemTransOp(featureName string, source string, remove bool, normalizer Normalizer, user *module.User, item *module.Item, context *context.RecommendContext)
 }
 
+// FeatureOpFactory creates the FeatureOp of the FeatureType
+type FeatureOpFactory func() FeatureOp
+
+// featureOpFactories are the factories of the feature types used by NewFeatureOp
+var featureOpFactories = make(map[string]FeatureOpFactory)
+
+// RegisterFeatureOpFactory registers the factory of the feature type, so the custom FeatureOp can be configured by FeatureType like the built-in ones,
+// the factory of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterFeatureOpFactory(featureType string, factory FeatureOpFactory) {
+	if factory == nil {
+		panic("FeatureOpFactory is nil, type:" + featureType)
+	}
+
+	featureOpFactories[featureType] = factory
+	recconf.RegisterSchemaEnum("FeatureConfig.FeatureType", featureType)
+}
+
+func init() {
+	RegisterFeatureOpFactory("raw_feature", func() FeatureOp { return RawFeatureOp{} })
+	RegisterFeatureOpFactory("compose_feature", func() FeatureOp { return ComposeFeatureOp{} })
+	RegisterFeatureOpFactory("delete_feature", func() FeatureOp { return DeleteFeatureOp{} })
+	RegisterFeatureOpFactory("batch_raw_feature", func() FeatureOp { return BatchRawFeatureOp{} })
+	RegisterFeatureOpFactory("new_feature", func() FeatureOp { return CreateNewFeatureOp{} })
+	RegisterFeatureOpFactory("context_feature", func() FeatureOp { return ContextFeatureOp{} })
+}
+
 func NewFeatureOp(t string) FeatureOp {
-	if t == "raw_feature" {
-		return RawFeatureOp{}
-	} else if t == "compose_feature" {
-		return ComposeFeatureOp{}
-	} else if t == "delete_feature" {
-		return DeleteFeatureOp{}
-	} else if t == "batch_raw_feature" {
-		return BatchRawFeatureOp{}
-	} else if t == "new_feature" {
-		return CreateNewFeatureOp{}
-	} else if t == "context_feature" {
-		return ContextFeatureOp{}
+	if factory, ok := featureOpFactories[t]; ok {
+		return factory()
 	}
 
 	panic(fmt.Sprintf("not find feature type:%s", t))
@@ -138,3 +138,41 @@ func TestNewFeatureWithExpressionFeatureOp(t *testing.T) {
 	})
 
 }
+
+type upperFeatureOp struct{}
+
+func (op upperFeatureOp) UserTransOp(featureName string, source string, remove bool, normalizer Normalizer, user *module.User, context *context.RecommendContext) {
+	user.AddProperty(featureName, normalizer.Apply(user.StringProperty(source)))
+}
+
+func (op upperFeatureOp) ItemTransOp(featureName string, source string, remove bool, normalizer Normalizer, user *module.User, item *module.Item, context *context.RecommendContext) {
+}
+
+type suffixNormalizer struct {
+	suffix string
+}
+
+func (n *suffixNormalizer) Apply(value interface{}) interface{} {
+	return value.(string) + n.suffix
+}
+
+func TestRegisterFeatureOpFactory(t *testing.T) {
+	RegisterFeatureOpFactory("custom_test_feature", func() FeatureOp { return upperFeatureOp{} })
+	RegisterNormalizerFactory("custom_test_suffix", func(expression string) Normalizer { return &suffixNormalizer{suffix: expression} })
+
+	conf := recconf.FeatureLoadConfig{}
+	conf.Features = append(conf.Features, recconf.FeatureConfig{
+		FeatureType:   "custom_test_feature",
+		FeatureStore:  "user",
+		FeatureSource: "gender",
+		FeatureName:   "gender_suffix",
+		Normalizer:    "custom_test_suffix",
+		Expression:    "_v1",
+	})
+
+	user := module.NewUser("user1")
+	user.AddProperty("gender", "male")
+	LoadWithConfig(conf).LoadFeatures(user, nil, context.NewRecommendContext())
+
+	assert.Equal(t, user.StringProperty("gender_suffix"), "male_v1")
+}
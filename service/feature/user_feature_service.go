@@ -228,7 +228,11 @@ func (s *UserFeatureService) LoadUserFeaturesForCallback(user *module.User, cont
 
 }
 
-func UserLoadFeatureConfig(config *recconf.RecommendConfig) {
+// PrepareUserFeatureConfig builds the user features of the changed scenes without loading them, commit loads the features built.
+// The construction errors of all the scenes are returned, and nothing should be committed when there are errors.
+func PrepareUserFeatureConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string][]*Feature)
+	asyncLoads := make(map[*Feature]bool)
 	for name, sceneConf := range config.UserFeatureConfs {
 		if _, ok := userFeatureService.FeatureSceneMap[name]; ok {
 			if signOfFeatureLoadConfs(sceneConf.FeatureLoadConfs) == userFeatureService.FeatureSceneSigns[name] {
@@ -236,27 +240,47 @@ func UserLoadFeatureConfig(config *recconf.RecommendConfig) {
 			}
 		}
 
-		var features []*Feature
-		for _, conf := range sceneConf.FeatureLoadConfs {
-			if conf.FeatureDaoConf.FeatureStore != SOURCE_USER {
-				panic("user load features config, FeatureStore value must is user")
-			}
-			f := LoadWithConfig(conf)
-			userFeatureService.FeatureAsyncLoadMap[f] = false
-			features = append(features, f)
-			if conf.FeatureDaoConf.FeatureAsyncLoad {
-				userFeatureService.FeatureAsyncLoadMap[f] = true
+		sceneConf := sceneConf
+		features, err := recconf.BuildModule("user_feature", name, "", func() []*Feature {
+			features := make([]*Feature, 0, len(sceneConf.FeatureLoadConfs))
+			for _, conf := range sceneConf.FeatureLoadConfs {
+				if conf.FeatureDaoConf.FeatureStore != SOURCE_USER {
+					panic("user load features config, FeatureStore value must is user")
+				}
+				f := LoadWithConfig(conf)
+				asyncLoads[f] = conf.FeatureDaoConf.FeatureAsyncLoad
+				features = append(features, f)
 			}
+			return features
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		/**
-		userFeatureService.FeatureSceneAsyncMap[name] = true
-		userFeatureService.FeatureSceneAsyncMap[name] = false
-		if sceneConf.AsynLoadFeature {
-			userFeatureService.FeatureSceneAsyncMap[name] = true
+		built[name] = features
+	}
+
+	commit = func() {
+		for f, async := range asyncLoads {
+			userFeatureService.FeatureAsyncLoadMap[f] = async
+		}
+		for name, features := range built {
+			userFeatureService.FeatureSceneMap[name] = features
+			userFeatureService.FeatureSceneSigns[name] = signOfFeatureLoadConfs(config.UserFeatureConfs[name].FeatureLoadConfs)
 		}
-		**/
-		userFeatureService.FeatureSceneMap[name] = features
-		userFeatureService.FeatureSceneSigns[name] = signOfFeatureLoadConfs(sceneConf.FeatureLoadConfs)
 	}
+
+	return commit, errs
+}
+
+// UserLoadFeatureConfig loads the user features of the changed scenes, it panics with the errors of all the scenes
+// when any feature fails to construct, and no scene is changed in that case
+func UserLoadFeatureConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareUserFeatureConfig(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
+	}
+
+	commit()
 }
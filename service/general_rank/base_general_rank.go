@@ -170,7 +170,7 @@ func (r *BaseGeneralRank) doRankWithAlgo(user *module.User, items []*module.Item
 				wg.Add(1)
 				go func(algo string) {
 					defer wg.Done()
-					algoResponses, err := algorithm.Run(algo, algoData.GetFeatures())
+					algoResponses, err := algorithm.RunWithContext(context.Context(), algo, algoData.GetFeatures())
 					if err != nil {
 						log.Error(fmt.Sprintf("requestId=%s\terror=run algorithm error(%v)", context.RecommendId, err))
 						algoData.SetError(err)
@@ -22,6 +22,7 @@ var (
 	SortDurSecs           *prometheus.HistogramVec
 	RecDurSecs            *prometheus.HistogramVec
 	FallbackTotal         *prometheus.CounterVec
+	RecallTimeoutTotal    *prometheus.CounterVec
 
 	enabled = false
 	once    sync.Once
@@ -47,7 +48,8 @@ func Load(conf *recconf.RecommendConfig) {
 			LoadFeatureDurSecs,
 			RankDurSecs,
 			SortDurSecs,
-			FallbackTotal)
+			FallbackTotal,
+			RecallTimeoutTotal)
 	})
 
 	enabled = conf.PrometheusConfig.Enable
@@ -151,5 +153,11 @@ func initMetrics(conf *recconf.RecommendConfig) {
 		Subsystem: subsystem,
 		Name:      "fallback_total",
 		Help:      "How many times of fallback recommend.",
-	}, []string{"scene"})
+	}, []string{"scene", "reason"})
+
+	RecallTimeoutTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "recall_timeout_total",
+		Help:      "How many times of recall dropped by timeout.",
+	}, []string{"recall_name"})
 }
@@ -0,0 +1,151 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/persist/cache"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+// Pagination caches the full ranked list of the session, the later pages of the session are served from it
+type Pagination struct {
+	cache     cache.Cache
+	cacheTime time.Duration
+	listSize  int
+}
+
+type pageItem struct {
+	ItemId     string                 `json:"item_id"`
+	ItemType   string                 `json:"item_type,omitempty"`
+	RetrieveId string                 `json:"retrieve_id,omitempty"`
+	Score      float64                `json:"score"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	AlgoScores map[string]float64     `json:"algo_scores,omitempty"`
+}
+
+func NewPagination(conf recconf.PaginationConfig) (*Pagination, error) {
+	adapter := conf.CacheAdapter
+	if adapter == "" {
+		adapter = "localCache"
+	}
+
+	cacheConfig := conf.CacheConfig
+	if cacheConfig == "" && adapter == "localCache" {
+		cacheConfig = "{\"defaultExpiration\":1800, \"cleanupInterval\":600}"
+	}
+
+	c, err := cache.NewCache(adapter, cacheConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTime := conf.CacheTime
+	if cacheTime <= 0 {
+		cacheTime = 1800
+	}
+
+	listSize := conf.ListSize
+	if listSize <= 0 {
+		listSize = 200
+	}
+
+	return &Pagination{
+		cache:     c,
+		cacheTime: time.Duration(cacheTime) * time.Second,
+		listSize:  listSize,
+	}, nil
+}
+
+// ListSize returns the size of the ranked list to compute, it contains the page at least
+func (p *Pagination) ListSize(page, size int) int {
+	if page*size > p.listSize {
+		return page * size
+	}
+
+	return p.listSize
+}
+
+// GetPage returns the items of the page from the cached ranked list of the session,
+// found is false when the ranked list is not cached or expired
+func (p *Pagination) GetPage(context *context.RecommendContext, sessionId string, page, size int) (items []*module.Item, found bool) {
+	var data []byte
+	switch value := p.cache.Get(p.cacheKey(context, sessionId)).(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return nil, false
+	}
+
+	var pageItems []*pageItem
+	if err := json.Unmarshal(data, &pageItems); err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=pagination\tsession_id=%s\terror=%v", context.RecommendId, sessionId, err))
+		return nil, false
+	}
+
+	for _, pageItem := range Page(pageItems, page, size) {
+		item := module.NewItemWithProperty(pageItem.ItemId, pageItem.Properties)
+		item.ItemType = pageItem.ItemType
+		item.RetrieveId = pageItem.RetrieveId
+		item.Score = pageItem.Score
+		item.AddAlgoScores(pageItem.AlgoScores)
+		items = append(items, item)
+	}
+
+	return items, true
+}
+
+// Put caches the ranked list of the session
+func (p *Pagination) Put(context *context.RecommendContext, sessionId string, items []*module.Item) {
+	pageItems := make([]*pageItem, 0, len(items))
+	for _, item := range items {
+		pageItems = append(pageItems, &pageItem{
+			ItemId:     string(item.Id),
+			ItemType:   item.ItemType,
+			RetrieveId: item.RetrieveId,
+			Score:      item.Score,
+			Properties: item.GetProperties(),
+			AlgoScores: item.CloneAlgoScores(),
+		})
+	}
+
+	data, err := json.Marshal(pageItems)
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=pagination\tsession_id=%s\terror=%v", context.RecommendId, sessionId, err))
+		return
+	}
+
+	if err := p.cache.Put(p.cacheKey(context, sessionId), string(data), p.cacheTime); err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=pagination\tsession_id=%s\terror=%v", context.RecommendId, sessionId, err))
+	}
+}
+
+func (p *Pagination) cacheKey(context *context.RecommendContext, sessionId string) string {
+	return fmt.Sprintf("pairec_page_%v_%v_%v_%s", context.GetParameter("scene"), context.GetParameter("category"),
+		context.GetParameter("uid"), sessionId)
+}
+
+// Page returns the items of the page, page starts from 1
+func Page[T any](items []T, page, size int) []T {
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * size
+	if start >= len(items) {
+		return nil
+	}
+
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end]
+}
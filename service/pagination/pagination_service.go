@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+var (
+	paginations     = make(map[string]*Pagination)
+	paginationSigns = make(map[string]string)
+	mu              sync.RWMutex
+)
+
+// GetPagination returns the pagination of the scene and category, it is nil when the pagination is not configured
+func GetPagination(sceneName, category string) *Pagination {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return paginations[sceneName+"#"+category]
+}
+
+// LoadPaginationConfig loads the paginations of the config, the invalid pagination configs are logged and skipped
+func LoadPaginationConfig(config *recconf.RecommendConfig) {
+	commit, errs := PreparePaginationConfig(config)
+	for _, err := range errs {
+		log.Error(fmt.Sprintf("event=LoadPaginationConfig\terror=%v", err))
+	}
+	commit()
+}
+
+// PreparePaginationConfig builds the changed paginations of the config, commit replaces the paginations in use,
+// the invalid pagination configs are returned as the ModuleError
+func PreparePaginationConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	mu.RLock()
+	oldSigns := paginationSigns
+	mu.RUnlock()
+
+	built := make(map[string]*Pagination)
+	signs := make(map[string]string)
+	for scene, conf := range config.SceneConfs {
+		for category, categoryConf := range conf {
+			if categoryConf.PaginationConfig == nil {
+				continue
+			}
+
+			key := scene + "#" + category
+			data, _ := json.Marshal(categoryConf.PaginationConfig)
+			sign := utils.Md5(string(data))
+			if sign == oldSigns[key] {
+				signs[key] = sign
+				continue
+			}
+
+			p, err := NewPagination(*categoryConf.PaginationConfig)
+			if err != nil {
+				errs = append(errs, recconf.NewModuleError("pagination", key, "", err))
+				// the pagination in use is kept when the new one is invalid
+				if old, ok := oldSigns[key]; ok {
+					signs[key] = old
+				}
+				continue
+			}
+
+			built[key] = p
+			signs[key] = sign
+		}
+	}
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for key, p := range built {
+			paginations[key] = p
+		}
+		for key := range paginationSigns {
+			if _, ok := signs[key]; !ok {
+				delete(paginations, key)
+			}
+		}
+		paginationSigns = signs
+	}, errs
+}
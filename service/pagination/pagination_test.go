@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type testParam map[string]interface{}
+
+func (p testParam) GetParameter(name string) interface{} {
+	return p[name]
+}
+
+func TestPagination(t *testing.T) {
+	LoadPaginationConfig(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"home_feed": {
+				"default": {PaginationConfig: &recconf.PaginationConfig{ListSize: 25}},
+			},
+		},
+	})
+
+	p := GetPagination("home_feed", "default")
+	if p == nil {
+		t.Fatal("expect the pagination of home_feed")
+	}
+	if GetPagination("home_feed", "video") != nil {
+		t.Error("expect no pagination of the video category")
+	}
+	if size := p.ListSize(3, 10); size != 30 {
+		t.Errorf("expect list size 30, got %d", size)
+	}
+
+	ctx := context.NewRecommendContext()
+	ctx.Param = testParam{"scene": "home_feed", "category": "default", "uid": "u1"}
+
+	if _, found := p.GetPage(ctx, "s1", 2, 10); found {
+		t.Error("expect the ranked list not found before it is cached")
+	}
+
+	var items []*module.Item
+	for i := 0; i < 25; i++ {
+		item := module.NewItem(fmt.Sprintf("item%d", i))
+		item.RetrieveId = "recall"
+		item.Score = float64(25 - i)
+		item.AddProperty("title", fmt.Sprintf("title%d", i))
+		items = append(items, item)
+	}
+	p.Put(ctx, "s1", items)
+
+	page, found := p.GetPage(ctx, "s1", 2, 10)
+	if !found || len(page) != 10 || page[0].Id != "item10" || page[9].Id != "item19" {
+		t.Errorf("expect items from item10 to item19, got %v", page)
+	}
+	if page[0].RetrieveId != "recall" || page[0].Score != 15 || page[0].StringProperty("title") != "title10" {
+		t.Errorf("expect the item restored from the cache, got %v", page[0])
+	}
+	if page, _ := p.GetPage(ctx, "s1", 3, 10); len(page) != 5 {
+		t.Errorf("expect 5 items of the last page, got %v", page)
+	}
+	if page, found := p.GetPage(ctx, "s1", 4, 10); !found || len(page) != 0 {
+		t.Errorf("expect no items beyond the ranked list, got %v", page)
+	}
+	if _, found := p.GetPage(ctx, "s2", 2, 10); found {
+		t.Error("expect the ranked list of the other session not found")
+	}
+
+	LoadPaginationConfig(&recconf.RecommendConfig{})
+	if GetPagination("home_feed", "default") != nil {
+		t.Error("expect the pagination removed")
+	}
+}
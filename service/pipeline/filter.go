@@ -22,6 +22,9 @@ func NewFilterService(config *recconf.PipelineConfig) *FilterService {
 func (fs *FilterService) Filter(filterData *filter.FilterData) {
 	context := filterData.Context
 
+	// the items of the request param exclude_items are removed before the configured filters
+	filter.NewExcludeItemsFilter().Filter(filterData)
+
 	var filters []filter.IFilter
 	var filterNames []string
 	if context.ExperimentResult != nil {
@@ -1,7 +1,10 @@
 package pipeline
 
 import (
+	"fmt"
+
 	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
 	"github.com/alibaba/pairec/v2/module"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/service/debug"
@@ -30,17 +33,35 @@ func (p *PipelineService) clear() {
 }
 
 func LoadPipelineConfigs(conf *recconf.RecommendConfig) {
+	commit, errs := PreparePipelineConfigs(conf)
+	for _, err := range errs {
+		log.Error(fmt.Sprintf("event=LoadPipelineConfigs\terror=%v", err))
+	}
+	commit()
+}
+
+// PreparePipelineConfigs builds the pipelines of the config, commit replaces the pipelines in use
+func PreparePipelineConfigs(conf *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
 	userRecommendSceneMap := make(map[string][]*UserRecommendService)
 
 	for sceneName, configs := range conf.PipelineConfs {
 		for _, config := range configs {
-			userRecommendService := NewUserRecommendService(&config)
+			config := config
+			userRecommendService, err := recconf.BuildModule("pipeline", config.Name, "", func() *UserRecommendService {
+				return NewUserRecommendService(&config)
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
 
 			userRecommendSceneMap[sceneName] = append(userRecommendSceneMap[sceneName], userRecommendService)
 		}
 	}
 
-	pipelineService.userRecommendSceneMap = userRecommendSceneMap
+	return func() {
+		pipelineService.userRecommendSceneMap = userRecommendSceneMap
+	}, errs
 }
 func Recommend(user *module.User, context *context.RecommendContext, debugService *debug.DebugService) (ret []*module.Item) {
 	scene := context.GetParameter("scene").(string)
@@ -58,11 +79,15 @@ func Recommend(user *module.User, context *context.RecommendContext, debugServic
 		}(service)
 	}
 
+	done := context.Context().Done()
 	for i := 0; i < len(services); i++ {
-		items := <-ch
-		ret = append(ret, items...)
+		select {
+		case items := <-ch:
+			ret = append(ret, items...)
+		case <-done:
+			return
+		}
 	}
-	close(ch)
 
 	return
 }
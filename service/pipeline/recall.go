@@ -2,10 +2,7 @@ package pipeline
 
 import (
 	"encoding/json"
-	"fmt"
 	"reflect"
-	"runtime/debug"
-	"strings"
 	"sync"
 
 	"github.com/alibaba/pairec/v2/context"
@@ -21,6 +18,7 @@ var mutex sync.Mutex
 type RecallService struct {
 	pipelineName string
 	recallNames  []string
+	recallMerger *recall.RecallMerger
 }
 
 func NewRecallService(config *recconf.PipelineConfig) *RecallService {
@@ -29,6 +27,14 @@ func NewRecallService(config *recconf.PipelineConfig) *RecallService {
 		recallNames:  config.RecallNames,
 	}
 
+	if config.RecallMergeConf != nil {
+		merger, err := recall.NewRecallMerger(*config.RecallMergeConf)
+		if err != nil {
+			panic(err)
+		}
+		service.recallMerger = merger
+	}
+
 	return &service
 }
 func getRecallFromABConfig(recallConfig map[string]interface{}, name, recallNewName string) recall.Recall {
@@ -38,9 +44,11 @@ func getRecallFromABConfig(recallConfig map[string]interface{}, name, recallNewN
 		return nil
 	}
 
+	// the recall constructors may panic, so the mutex is released by defer
 	mutex.Lock()
+	defer mutex.Unlock()
+
 	if newRecall, err := recall.GetRecall(recallNewName); err == nil {
-		mutex.Unlock()
 		return newRecall
 	}
 
@@ -50,18 +58,18 @@ func getRecallFromABConfig(recallConfig map[string]interface{}, name, recallNewN
 			if newRecall, ok := i.(recall.Recall); ok {
 				recall.RegisterRecall(recallNewName, newRecall)
 				log.Info("register recall :" + recallNewName)
-				mutex.Unlock()
 				return newRecall
 			}
 		}
 	}
 
-	mutex.Unlock()
 	return nil
 }
 func (s *RecallService) GetItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
 	var recalls []recall.Recall
 	var recallNames []string
+	// runNames has the same length as recalls
+	var runNames []string
 	found := false
 	if context.ExperimentResult != nil {
 		names := context.ExperimentResult.GetExperimentParams().Get("pipelines."+s.pipelineName+".RecallNames", nil)
@@ -88,6 +96,7 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 			if recallConfig == nil {
 				if recall, err := recall.GetRecall(name); err == nil {
 					recalls = append(recalls, recall)
+					runNames = append(runNames, name)
 				}
 
 			} else {
@@ -96,10 +105,12 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 				// find new recall by the new recall name
 				if recall, err := recall.GetRecall(recallName); err == nil {
 					recalls = append(recalls, recall)
+					runNames = append(runNames, name)
 				} else {
 					if params, ok := recallConfig.(map[string]interface{}); ok {
 						if r := getRecallFromABConfig(params, name, recallName); r != nil {
 							recalls = append(recalls, r)
+							runNames = append(runNames, name)
 						}
 					}
 				}
@@ -109,33 +120,16 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 			// not find abtest config
 			if recall, err := recall.GetRecall(name); err == nil {
 				recalls = append(recalls, recall)
+				runNames = append(runNames, name)
 			}
 		}
 	}
 
-	ch := make(chan []*module.Item, len(recalls))
+	ret = recall.RunRecalls(user, context, runNames, recalls, 0)
 
-	for i := 0; i < len(recalls); i++ {
-		go func(ch chan<- []*module.Item, recall recall.Recall) {
-			// when recall is panic, can recover it
-			defer func() {
-				if err := recover(); err != nil {
-					stack := string(debug.Stack())
-					log.Error(fmt.Sprintf("error=%v, stack=%s", err, strings.ReplaceAll(stack, "\n", "\t")))
-
-					var tmp []*module.Item
-					ch <- tmp
-				}
-			}()
-
-			items := recall.GetCandidateItems(user, context)
-			ch <- items
-		}(ch, recalls[i])
-	}
-	for i := 0; i < len(recalls); i++ {
-		items := <-ch
-		ret = append(ret, items...)
+	// merge the items of the recalls by the normalized scores before the filters
+	if s.recallMerger != nil {
+		ret = s.recallMerger.Merge(ret, recall.ExperimentRecallWeights(context, "pipelines."+s.pipelineName+".RecallWeights"))
 	}
-	close(ch)
 	return
 }
@@ -52,4 +52,7 @@ func (ss *SortService) Sort(sortData *sort.SortData) {
 		s.Sort(sortData)
 
 	}
+
+	// the items of the request param pinned_items are put at their positions at the final stage
+	sort.NewPinnedItemsSort().Sort(sortData)
 }
@@ -56,19 +56,24 @@ func (r *UserRecommendService) Recommend(user *module.User, context *context.Rec
 
 	debugService.WriteFilterLog(user, items, context)
 
-	// general rank
-	items = r.generalRankService.Rank(user, items, context)
+	// when the request deadline is exceeded, rank stages are skipped and the filtered items are sorted directly
+	if err := context.Err(); err != nil {
+		log.Warning(fmt.Sprintf("requestId=%s\tmodule=pipeline\tpipeline=%s\tevent=timeout\terror=%v", context.RecommendId, r.pipelineName, err))
+	} else {
+		// general rank
+		items = r.generalRankService.Rank(user, items, context)
 
-	debugService.WriteGeneralLog(user, items, context)
+		debugService.WriteGeneralLog(user, items, context)
 
-	// load user or item features
-	// can load data from datasource(holo, ots, redis)
-	// after load data, use feature engine to create or modify features
-	items = r.featureService.LoadFeatures(user, items, context)
+		// load user or item features
+		// can load data from datasource(holo, ots, redis)
+		// after load data, use feature engine to create or modify features
+		items = r.featureService.LoadFeatures(user, items, context)
 
-	r.rankService.Rank(user, items, context)
+		r.rankService.Rank(user, items, context)
 
-	r.coldStartRankService.Rank(user, items, context)
+		r.coldStartRankService.Rank(user, items, context)
+	}
 
 	items = r.Sort(user, items, context)
 	log.Info(fmt.Sprintf("requestId=%s\tmodule=pipeline\tpipeline=%s\tcount=%d\tcost=%d", context.RecommendId, r.pipelineName, len(items), utils.CostTime(start)))
@@ -64,7 +64,7 @@ func (r *ColdStartRank) Rank(user *module.User, items []*module.Item, requestDat
 		Limit:        limit,
 	}
 
-	ret, err := algorithm.Run(r.AlgoName, &request)
+	ret, err := algorithm.RunWithContext(context.Context(), r.AlgoName, &request)
 	if err != nil {
 		log.Error(fmt.Sprintf("requestId=%s\tevent=ColdStartRank\terr=%v", context.RecommendId, err))
 		return
@@ -264,7 +264,7 @@ func (r *RankService) Rank(user *module.User, items []*module.Item, context *con
 				wg.Add(1)
 				go func(algo string) {
 					defer wg.Done()
-					ret, err := algorithm.Run(algo, algoData.GetFeatures())
+					ret, err := algorithm.RunWithContext(context.Context(), algo, algoData.GetFeatures())
 					if err != nil {
 						log.Error(fmt.Sprintf("requestId=%s\terror=run algorithm error(%v)", context.RecommendId, err))
 						algoData.SetError(err)
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"runtime/debug"
-	"strings"
 	"sync"
 	"time"
 
@@ -28,9 +26,11 @@ func getRecallFromABConfig(recallConfig map[string]interface{}, name, recallNewN
 		return nil
 	}
 
+	// the recall constructors may panic, so the mutex is released by defer
 	mutex.Lock()
+	defer mutex.Unlock()
+
 	if newRecall, err := recall.GetRecall(recallNewName); err == nil {
-		mutex.Unlock()
 		return newRecall
 	}
 
@@ -40,13 +40,11 @@ func getRecallFromABConfig(recallConfig map[string]interface{}, name, recallNewN
 			if newRecall, ok := i.(recall.Recall); ok {
 				recall.RegisterRecall(recallNewName, newRecall)
 				log.Info("register recall :" + recallNewName)
-				mutex.Unlock()
 				return newRecall
 			}
 		}
 	}
 
-	mutex.Unlock()
 	return nil
 }
 func (s *RecallService) GetItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
@@ -55,6 +53,8 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 
 	var recalls []recall.Recall
 	var recallNames []string
+	// runNames has the same length as recalls
+	var runNames []string
 	if context.ExperimentResult != nil {
 		names := context.ExperimentResult.GetExperimentParams().Get(categoryName+".RecallNames", nil)
 		if names != nil {
@@ -68,16 +68,20 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 		}
 	}
 
+	var category *Category
+	scene, err1 := GetSence(sceneName.(string))
+	if err1 == nil {
+		category, _ = scene.GetCategory(categoryName)
+	}
+
 	if len(recallNames) == 0 {
-		scene, err1 := GetSence(sceneName.(string))
 		if err1 != nil {
 			log.Error(fmt.Sprintf("requestId=%s\tmodule=recall\terror=%v", context.RecommendId, err1))
 			return
 
 		}
-		category, err2 := scene.GetCategory(categoryName)
-		if err2 != nil {
-			log.Error(fmt.Sprintf("requestId=%s\tmodule=recall\terror=%v", context.RecommendId, err2))
+		if category == nil {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=recall\terror=category not found:%s", context.RecommendId, categoryName))
 			return
 		}
 		// recalls = category.GetRecalls()
@@ -91,6 +95,7 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 			if recallConfig == nil {
 				if recall, err := recall.GetRecall(name); err == nil {
 					recalls = append(recalls, recall)
+					runNames = append(runNames, name)
 				}
 
 			} else {
@@ -99,10 +104,12 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 				// find new recall by the new recall name
 				if recall, err := recall.GetRecall(recallName); err == nil {
 					recalls = append(recalls, recall)
+					runNames = append(runNames, name)
 				} else {
 					if params, ok := recallConfig.(map[string]interface{}); ok {
 						if r := getRecallFromABConfig(params, name, recallName); r != nil {
 							recalls = append(recalls, r)
+							runNames = append(runNames, name)
 						}
 					}
 				}
@@ -112,35 +119,19 @@ func (s *RecallService) GetItems(user *module.User, context *context.RecommendCo
 			// not find abtest config
 			if recall, err := recall.GetRecall(name); err == nil {
 				recalls = append(recalls, recall)
+				runNames = append(runNames, name)
 			}
 		}
 	}
 
-	ch := make(chan []*module.Item, len(recalls))
-
 	start := time.Now()
-	for i := 0; i < len(recalls); i++ {
-		go func(ch chan<- []*module.Item, recall recall.Recall) {
-			// when recall is panic, can recover it
-			defer func() {
-				if err := recover(); err != nil {
-					stack := string(debug.Stack())
-					log.Error(fmt.Sprintf("error=%v, stack=%s", err, strings.ReplaceAll(stack, "\n", "\t")))
-
-					var tmp []*module.Item
-					ch <- tmp
-				}
-			}()
+	budget := GetSceneRecallTimeout(sceneName.(string), categoryName)
+	ret = recall.RunRecalls(user, context, runNames, recalls, budget)
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=recall\tcost=%d", context.RecommendId, utils.CostTime(start)))
 
-			items := recall.GetCandidateItems(user, context)
-			ch <- items
-		}(ch, recalls[i])
+	// merge the items of the recalls by the normalized scores before the filters
+	if category != nil && category.GetRecallMerger() != nil {
+		ret = category.GetRecallMerger().Merge(ret, recall.ExperimentRecallWeights(context, categoryName+".RecallWeights"))
 	}
-	for i := 0; i < len(recalls); i++ {
-		items := <-ch
-		ret = append(ret, items...)
-	}
-	close(ch)
-	log.Info(fmt.Sprintf("requestId=%s\tmodule=recall\tcost=%d", context.RecommendId, utils.CostTime(start)))
 	return
 }
@@ -18,6 +18,9 @@ var filterService *FilterService
 func init() {
 	filterService = &FilterService{}
 	filterService.Filters = make(map[string][]IBeFilter)
+
+	// the be filter types created by RegisterFilterWithConfig
+	recconf.RegisterSchemaEnum("BeFilterConfig.FilterType", "User2ItemExposureFilter")
 }
 
 type IBeFilter interface {
@@ -49,28 +52,53 @@ func (fs *FilterService) AddFilters(scene string, filters []IBeFilter) {
 	fs.Filters[scene] = filters
 }
 
-func RegisterFilterWithConfig(config *recconf.RecommendConfig) {
+// PrepareFilterWithConfig builds the changed be filters of the config without registering them, commit registers the filters built.
+// The construction errors of all the filters are returned, and nothing should be committed when there are errors.
+func PrepareFilterWithConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]IBeFilter)
+	signs := make(map[string]string)
 	for _, conf := range config.BeFilterConfs {
+		sign, _ := json.Marshal(&conf)
 		if _, ok := filterMapping[conf.Name]; ok {
-			sign, _ := json.Marshal(&conf)
 			if utils.Md5(string(sign)) == filterSigns[conf.Name] {
 				continue
 			}
 		}
 
-		var f IBeFilter
-		if conf.FilterType == "User2ItemExposureFilter" {
-			f = NewUser2ItemExposureFilter(conf)
+		conf := conf
+		f, err := recconf.BuildModule("be_filter", conf.Name, conf.FilterType, func() IBeFilter {
+			if conf.FilterType == "User2ItemExposureFilter" {
+				return NewUser2ItemExposureFilter(conf)
+			}
+			panic("be filter type not support")
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		if f == nil {
-			panic("Filter is nil, name:" + conf.Name)
+		built[conf.Name] = f
+		signs[conf.Name] = utils.Md5(string(sign))
+	}
+
+	commit = func() {
+		for name, f := range built {
+			registerFilterWithSign(name, f, signs[name])
 		}
+	}
 
-		sign, _ := json.Marshal(&conf)
-		registerFilterWithSign(conf.Name, f, utils.Md5(string(sign)))
+	return commit, errs
+}
 
+// RegisterFilterWithConfig registers the changed be filters of the config, it panics with the errors of all the filters
+// when any filter fails to construct, and no filter is registered in that case
+func RegisterFilterWithConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareFilterWithConfig(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
 	}
+
+	commit()
 }
 
 func RegisterFilter(name string, filter IBeFilter) {
@@ -81,7 +81,7 @@ func (t *UserEmbeddingDssmO2OTrigger) loadUserFeatures(user *module.User, contex
 func (t *UserEmbeddingDssmO2OTrigger) GetTriggerKey(u *module.User, context *context.RecommendContext) *TriggerResult {
 	//start := time.Now()
 	if t.useCacheFeatures {
-		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+		ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 		defer cancel()
 		select {
 		case <-u.FeatureAsyncLoadCh():
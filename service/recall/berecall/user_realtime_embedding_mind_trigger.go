@@ -80,7 +80,7 @@ func (t *UserRealtimeEmbeddingMindTrigger) loadUserFeatures(user *module.User, c
 func (t *UserRealtimeEmbeddingMindTrigger) GetTriggerKey(u *module.User, context *context.RecommendContext) *TriggerResult {
 	//start := time.Now()
 	if t.useCacheFeatures {
-		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 150*time.Millisecond)
+		ctx, cancel := gocontext.WithTimeout(context.Context(), 150*time.Millisecond)
 		defer cancel()
 		select {
 		case <-u.FeatureAsyncLoadCh():
@@ -98,7 +98,7 @@ func (t *UserRealtimeEmbeddingMindTrigger) GetTriggerKey(u *module.User, context
 	algoData := algoGenerator.GeneratorAlgoDataDebugWithLevel(102)
 	easyrecRequest := algoData.GetFeatures().(*easyrec.PBRequest)
 	easyrecRequest.FaissNeighNum = int32(t.embeddingNum)
-	algoRet, err := algorithm.Run(t.recallAlgo, easyrecRequest)
+	algoRet, err := algorithm.RunWithContext(context.Context(), t.recallAlgo, easyrecRequest)
 
 	var triggerItem string
 	var triggerItems []string
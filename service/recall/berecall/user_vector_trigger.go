@@ -71,7 +71,7 @@ func (t *UserVectorTrigger) GetTriggerKey(user *module.User, context *context.Re
 		algoGenerator.SetItemFeatures(nil)
 		algoGenerator.AddFeatures(nil, nil, user.MakeUserFeatures2())
 		algoData := algoGenerator.GeneratorAlgoData()
-		algoRet, err := algorithm.Run(t.recallAlgo, algoData.GetFeatures())
+		algoRet, err := algorithm.RunWithContext(context.Context(), t.recallAlgo, algoData.GetFeatures())
 		if err != nil {
 			context.LogError(fmt.Sprintf("requestId=%s\tmodule=UserVectorTrigger\terr=%v", context.RecommendId, err))
 		} else {
@@ -0,0 +1,373 @@
+package recall
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/persist/cache"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const (
+	Default_Result_Cache_Time = 1800
+
+	cachedItemsVersion byte = 2
+)
+
+func init() {
+	recconf.RegisterSchemaEnum("RecallResultCacheConfig.CacheAdapter", "localCache", "redis")
+}
+
+// CachedRecall caches the items of the recall by the scene, the user and the KeyFeatures of the request, the items are encoded in binary with the retrieve id, item type, score
+// and properties, the properties are encoded in json, so the numbers of them are float64 when the items are got from the cache.
+// The stale items are returned while one request of the user refreshes them in the background,
+// and the concurrent misses of the same key wait for the same call of the recall until their requests time out.
+type CachedRecall struct {
+	recall      Recall
+	name        string
+	cache       cache.Cache
+	keyPrefix   string
+	keyFeatures []string
+	cacheTime   time.Duration
+	staleTime   time.Duration
+	calls       *cacheCallGroup
+}
+
+// NewCachedRecall wraps the recall by the ResultCacheConf of the config
+func NewCachedRecall(config recconf.RecallConfig, recall Recall) (*CachedRecall, error) {
+	conf := config.ResultCacheConf
+	c, err := cache.NewCache(conf.CacheAdapter, conf.CacheConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedRecall := &CachedRecall{
+		recall:      recall,
+		name:        config.Name,
+		cache:       c,
+		keyPrefix:   conf.CachePrefix + config.Name + "_",
+		keyFeatures: conf.KeyFeatures,
+		cacheTime:   time.Duration(conf.CacheTime) * time.Second,
+		staleTime:   time.Duration(conf.StaleTime) * time.Second,
+		calls:       newCacheCallGroup(),
+	}
+	if cachedRecall.cacheTime <= 0 {
+		cachedRecall.cacheTime = Default_Result_Cache_Time * time.Second
+	}
+	if cachedRecall.staleTime < 0 {
+		cachedRecall.staleTime = 0
+	}
+
+	return cachedRecall, nil
+}
+
+// Unwrap returns the recall cached
+func (r *CachedRecall) Unwrap() Recall {
+	return r.recall
+}
+
+// CloneWithConfig clones the recall cached by the experiment params, the clone has its own cache keys of the params.
+// nil is returned when the recall cached can not be cloned.
+func (r *CachedRecall) CloneWithConfig(params map[string]interface{}) Recall {
+	m := reflect.ValueOf(r.recall).MethodByName("CloneWithConfig")
+	if !m.IsValid() {
+		return nil
+	}
+	callValues := m.Call([]reflect.Value{reflect.ValueOf(params)})
+	if len(callValues) != 1 {
+		return nil
+	}
+	recall, ok := callValues[0].Interface().(Recall)
+	if !ok {
+		return nil
+	}
+
+	d, _ := json.Marshal(params)
+	clone := *r
+	clone.recall = recall
+	clone.keyPrefix = r.keyPrefix + utils.Md5(string(d)) + "_"
+	clone.calls = newCacheCallGroup()
+
+	return &clone
+}
+
+// cacheKey returns the key of the items by the scene, the user and the values of the KeyFeatures of the request
+func (r *CachedRecall) cacheKey(user *module.User, context *context.RecommendContext) string {
+	var scene string
+	var features map[string]interface{}
+	if context.Param != nil {
+		scene, _ = context.GetParameter("scene").(string)
+		features, _ = context.GetParameter("features").(map[string]interface{})
+	}
+
+	key := r.keyPrefix + scene + "_" + string(user.Id)
+	if len(r.keyFeatures) > 0 {
+		values := make([]interface{}, len(r.keyFeatures))
+		for i, name := range r.keyFeatures {
+			values[i] = features[name]
+		}
+		d, _ := json.Marshal(values)
+		key += "_" + utils.Md5(string(d))
+	}
+
+	return key
+}
+
+func (r *CachedRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) []*module.Item {
+	start := time.Now()
+	key := r.cacheKey(user, context)
+
+	if data, ok := r.cache.Get(key).([]byte); ok {
+		freshUntil, items, err := decodeCachedItems(data)
+		if err == nil {
+			if start.Before(freshUntil) {
+				log.Info(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\thit cache\tcount=%d\tcost=%d", context.RecommendId, r.name, len(items), utils.CostTime(start)))
+				return items
+			}
+			if r.staleTime > 0 {
+				r.refresh(key, user, context)
+				log.Info(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\thit stale cache\tcount=%d\tcost=%d", context.RecommendId, r.name, len(items), utils.CostTime(start)))
+				return items
+			}
+		} else {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\terror=decode cache:%v", context.RecommendId, r.name, err))
+		}
+	}
+
+	// the callers of the same key share the data of one call, and each one decodes its own items
+	data := r.calls.Do(context.Context(), key, func() []byte {
+		return r.load(key, user, context)
+	})
+	if data == nil {
+		if err := context.Err(); err != nil {
+			log.Warning(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\terror=wait call:%v\tcost=%d", context.RecommendId, r.name, err, utils.CostTime(start)))
+		}
+		return nil
+	}
+	_, items, err := decodeCachedItems(data)
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\terror=decode items:%v", context.RecommendId, r.name, err))
+		return nil
+	}
+
+	return items
+}
+
+// refresh loads the items in the background, it is skipped when the items of the key are being loaded
+func (r *CachedRecall) refresh(key string, user *module.User, context *context.RecommendContext) {
+	refreshContext := newRefreshContext(r.name, context)
+	refreshUser := user.Clone()
+	if !r.calls.DoAsync(key, func() []byte {
+		defer refreshContext.Cancel()
+		return r.load(key, refreshUser, refreshContext)
+	}) {
+		refreshContext.Cancel()
+	}
+}
+
+// load invokes the recall and puts the encoded items into the cache, nil is returned when the recall has no item
+func (r *CachedRecall) load(key string, user *module.User, context *context.RecommendContext) []byte {
+	items := r.recall.GetCandidateItems(user, context)
+	if len(items) == 0 {
+		return nil
+	}
+
+	data := encodeCachedItems(time.Now().Add(r.cacheTime), items)
+	if err := r.cache.Put(key, data, r.cacheTime+r.staleTime); err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=CachedRecall\tname=%s\terror=%v", context.RecommendId, r.name, err))
+	}
+
+	return data
+}
+
+// newRefreshContext returns the context of the background refresh, it is not canceled with the request,
+// and the timeout is the timeout of the recall
+func newRefreshContext(name string, ctx *context.RecommendContext) *context.RecommendContext {
+	refreshContext := context.NewRecommendContext()
+	refreshContext.Debug = ctx.Debug
+	refreshContext.Size = ctx.Size
+	refreshContext.Param = ctx.Param
+	refreshContext.Config = ctx.Config
+	refreshContext.ExperimentResult = ctx.ExperimentResult
+	refreshContext.RecommendId = ctx.RecommendId
+	refreshContext.ExpId = ctx.ExpId
+	refreshContext.SetTimeout(GetRecallTimeout(name))
+
+	return refreshContext
+}
+
+// encodeCachedItems encodes the fresh deadline and the items, each item is the id, retrieve id, item type, score and the json of the properties
+func encodeCachedItems(freshUntil time.Time, items []*module.Item) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(cachedItemsVersion)
+
+	var b [binary.MaxVarintLen64]byte
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(b[:], v)
+		buf.Write(b[:n])
+	}
+	writeString := func(s string) {
+		writeUvarint(uint64(len(s)))
+		buf.WriteString(s)
+	}
+
+	binary.BigEndian.PutUint64(b[:8], uint64(freshUntil.UnixMilli()))
+	buf.Write(b[:8])
+	writeUvarint(uint64(len(items)))
+	for _, item := range items {
+		writeString(string(item.Id))
+		writeString(item.RetrieveId)
+		writeString(item.ItemType)
+		binary.BigEndian.PutUint64(b[:8], math.Float64bits(item.Score))
+		buf.Write(b[:8])
+
+		var properties []byte
+		if features := item.GetCloneFeatures(); len(features) > 0 {
+			properties, _ = json.Marshal(features)
+		}
+		writeString(string(properties))
+	}
+
+	return buf.Bytes()
+}
+
+var errCachedItemsCorrupted = errors.New("cached items corrupted")
+
+func decodeCachedItems(data []byte) (freshUntil time.Time, items []*module.Item, err error) {
+	reader := bytes.NewReader(data)
+	version, err := reader.ReadByte()
+	if err != nil {
+		return freshUntil, nil, errCachedItemsCorrupted
+	}
+	if version != cachedItemsVersion {
+		return freshUntil, nil, fmt.Errorf("cached items version %d not support", version)
+	}
+
+	var b [8]byte
+	readString := func() (string, error) {
+		n, err := binary.ReadUvarint(reader)
+		if err != nil || n > uint64(reader.Len()) {
+			return "", errCachedItemsCorrupted
+		}
+		s := make([]byte, n)
+		reader.Read(s)
+		return string(s), nil
+	}
+
+	if _, err := reader.Read(b[:]); err != nil {
+		return freshUntil, nil, errCachedItemsCorrupted
+	}
+	freshUntil = time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+
+	count, err := binary.ReadUvarint(reader)
+	if err != nil || count > uint64(reader.Len()) {
+		return freshUntil, nil, errCachedItemsCorrupted
+	}
+	items = make([]*module.Item, 0, count)
+	for i := uint64(0); i < count; i++ {
+		id, err := readString()
+		if err != nil {
+			return freshUntil, nil, err
+		}
+		item := module.NewItem(id)
+		if item.RetrieveId, err = readString(); err != nil {
+			return freshUntil, nil, err
+		}
+		if item.ItemType, err = readString(); err != nil {
+			return freshUntil, nil, err
+		}
+		if n, _ := reader.Read(b[:]); n != len(b) {
+			return freshUntil, nil, errCachedItemsCorrupted
+		}
+		item.Score = math.Float64frombits(binary.BigEndian.Uint64(b[:]))
+		properties, err := readString()
+		if err != nil {
+			return freshUntil, nil, err
+		}
+		if properties != "" {
+			if err := json.Unmarshal([]byte(properties), &item.Properties); err != nil {
+				return freshUntil, nil, errCachedItemsCorrupted
+			}
+		}
+		items = append(items, item)
+	}
+
+	return freshUntil, items, nil
+}
+
+type cacheCall struct {
+	done chan struct{}
+	data []byte
+}
+
+// cacheCallGroup dedupes the concurrent calls of the same key
+type cacheCallGroup struct {
+	mu    sync.Mutex
+	calls map[string]*cacheCall
+}
+
+func newCacheCallGroup() *cacheCallGroup {
+	return &cacheCallGroup{calls: make(map[string]*cacheCall)}
+}
+
+// Do calls fn once for the concurrent callers of the key, and the callers get the same data,
+// the caller waiting for the call of the other one gets nil when the ctx is done
+func (g *cacheCallGroup) Do(ctx gocontext.Context, key string, fn func() []byte) []byte {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.data
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	g.call(key, call, fn)
+	return call.data
+}
+
+// DoAsync calls fn in the background when no call of the key is running, false is returned when the call is skipped
+func (g *cacheCallGroup) DoAsync(key string, fn func() []byte) bool {
+	g.mu.Lock()
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return false
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	go g.call(key, call, fn)
+	return true
+}
+
+func (g *cacheCallGroup) call(key string, call *cacheCall, fn func() []byte) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error(fmt.Sprintf("module=CachedRecall\tkey=%s\terror=%v", key, err))
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.data = fn()
+}
@@ -0,0 +1,218 @@
+package recall
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type countRecall struct {
+	calls  int32
+	delay  time.Duration
+	params map[string]interface{}
+}
+
+func (r *countRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) []*module.Item {
+	calls := atomic.AddInt32(&r.calls, 1)
+	time.Sleep(r.delay)
+
+	item := module.NewItem(string(user.Id) + "_item")
+	item.RetrieveId = "count_recall"
+	item.ItemType = "video"
+	item.Score = float64(calls)
+	item.AddProperty("title", "title_"+string(user.Id))
+	item.AddProperty("duration", 30)
+	return []*module.Item{item}
+}
+
+func (r *countRecall) CloneWithConfig(params map[string]interface{}) Recall {
+	return &countRecall{params: params}
+}
+
+func newTestCachedRecall(t *testing.T, recall Recall) *CachedRecall {
+	config := recconf.RecallConfig{
+		Name: "count_recall",
+		ResultCacheConf: recconf.RecallResultCacheConfig{
+			CacheAdapter: "localCache",
+			CacheConfig:  `{"defaultExpiration":600, "cleanupInterval":600}`,
+		},
+	}
+	cachedRecall, err := NewCachedRecall(config, recall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cachedRecall
+}
+
+func TestCachedRecall(t *testing.T) {
+	inner := &countRecall{}
+	recall := newTestCachedRecall(t, inner)
+	user := module.NewUser("u1")
+
+	items := recall.GetCandidateItems(user, context.NewRecommendContext())
+	if len(items) != 1 || items[0].Id != "u1_item" || items[0].RetrieveId != "count_recall" || items[0].ItemType != "video" || items[0].Score != 1 {
+		t.Fatalf("unexpected items %v", items)
+	}
+
+	items = recall.GetCandidateItems(user, context.NewRecommendContext())
+	if atomic.LoadInt32(&inner.calls) != 1 || len(items) != 1 || items[0].Score != 1 {
+		t.Errorf("expect the cached items, calls:%d, items:%v", inner.calls, items)
+	}
+	// the properties are cached with the items, the numbers are float64
+	if title := items[0].StringProperty("title"); title != "title_u1" {
+		t.Errorf("expect the cached property title_u1, got %s", title)
+	}
+	if duration, ok := items[0].GetProperty("duration").(float64); !ok || duration != 30 {
+		t.Errorf("expect the cached property 30, got %v", items[0].GetProperty("duration"))
+	}
+
+	// the stale items are returned and refreshed in the background
+	recall.cacheTime = 20 * time.Millisecond
+	recall.staleTime = time.Minute
+	recall.GetCandidateItems(module.NewUser("u2"), context.NewRecommendContext())
+	time.Sleep(30 * time.Millisecond)
+	items = recall.GetCandidateItems(module.NewUser("u2"), context.NewRecommendContext())
+	if len(items) != 1 || items[0].Score != 2 {
+		t.Fatalf("expect the stale items, got %v", items)
+	}
+	// the refreshed items are put into the cache by the background call
+	var score float64
+	for i := 0; i < 100 && score < 3; i++ {
+		time.Sleep(5 * time.Millisecond)
+		if data, ok := recall.cache.Get(recall.cacheKey(module.NewUser("u2"), context.NewRecommendContext())).([]byte); ok {
+			if _, items, err := decodeCachedItems(data); err == nil && len(items) == 1 {
+				score = items[0].Score
+			}
+		}
+	}
+	if score != 3 {
+		t.Errorf("expect the refreshed items of the score 3, got %v", score)
+	}
+}
+
+func TestCachedRecallSingleFlight(t *testing.T) {
+	inner := &countRecall{delay: 50 * time.Millisecond}
+	recall := newTestCachedRecall(t, inner)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if items := recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext()); len(items) != 1 {
+				t.Errorf("expect the items of the shared call, got %v", items)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&inner.calls); calls != 1 {
+		t.Errorf("expect the concurrent misses call the recall once, got %d", calls)
+	}
+}
+
+type sceneFeatureParam struct {
+	scene    string
+	features map[string]interface{}
+}
+
+func (p sceneFeatureParam) GetParameter(name string) interface{} {
+	switch name {
+	case "scene":
+		return p.scene
+	case "features":
+		return p.features
+	}
+	return nil
+}
+
+func TestCachedRecallKey(t *testing.T) {
+	inner := &countRecall{}
+	recall := newTestCachedRecall(t, inner)
+	recall.keyFeatures = []string{"city"}
+
+	newContext := func(scene, city string) *context.RecommendContext {
+		ctx := context.NewRecommendContext()
+		ctx.Param = sceneFeatureParam{scene: scene, features: map[string]interface{}{"city": city, "hour": 10}}
+		return ctx
+	}
+
+	recall.GetCandidateItems(module.NewUser("u1"), newContext("home", "beijing"))
+	recall.GetCandidateItems(module.NewUser("u1"), newContext("home", "beijing"))
+	if calls := atomic.LoadInt32(&inner.calls); calls != 1 {
+		t.Fatalf("expect the items cached by the same scene and features, calls:%d", calls)
+	}
+
+	recall.GetCandidateItems(module.NewUser("u1"), newContext("detail", "beijing"))
+	recall.GetCandidateItems(module.NewUser("u1"), newContext("home", "shanghai"))
+	if calls := atomic.LoadInt32(&inner.calls); calls != 3 {
+		t.Errorf("expect the items cached by the scene and the key features, calls:%d", calls)
+	}
+}
+
+func TestCachedRecallSingleFlightTimeout(t *testing.T) {
+	inner := &countRecall{delay: 200 * time.Millisecond}
+	recall := newTestCachedRecall(t, inner)
+
+	go recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+	time.Sleep(20 * time.Millisecond)
+
+	ctx := context.NewRecommendContext()
+	ctx.SetTimeout(20 * time.Millisecond)
+	defer ctx.Cancel()
+	start := time.Now()
+	if items := recall.GetCandidateItems(module.NewUser("u1"), ctx); len(items) != 0 {
+		t.Errorf("expect no item after the request timeout, got %v", items)
+	}
+	if cost := time.Since(start); cost > 150*time.Millisecond {
+		t.Errorf("expect the waiting caller returns at the request timeout, cost %v", cost)
+	}
+}
+
+func TestCachedRecallCloneWithConfig(t *testing.T) {
+	recall := newTestCachedRecall(t, &countRecall{})
+	recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+
+	clone, ok := recall.CloneWithConfig(map[string]interface{}{"count": 10}).(*CachedRecall)
+	if !ok {
+		t.Fatal("expect the clone of the cached recall")
+	}
+	inner := clone.Unwrap().(*countRecall)
+	if inner.params["count"] != 10 || clone.keyPrefix == recall.keyPrefix {
+		t.Fatalf("expect the clone of the params with its own keys, got %v %s", inner.params, clone.keyPrefix)
+	}
+
+	clone.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+	if inner.calls != 1 {
+		t.Errorf("expect the clone misses the cache of the original recall, calls:%d", inner.calls)
+	}
+
+	if clone := newTestCachedRecall(t, &MockRecall{}).CloneWithConfig(nil); clone != nil {
+		t.Errorf("expect nil clone of the recall without CloneWithConfig, got %v", clone)
+	}
+}
+
+func TestDecodeCachedItems(t *testing.T) {
+	item := module.NewItem("1")
+	item.RetrieveId = "r"
+	item.Score = 0.5
+	freshUntil := time.UnixMilli(time.Now().UnixMilli())
+	data := encodeCachedItems(freshUntil, []*module.Item{item})
+
+	until, items, err := decodeCachedItems(data)
+	if err != nil || !until.Equal(freshUntil) || len(items) != 1 || items[0].Id != "1" || items[0].RetrieveId != "r" || items[0].Score != 0.5 {
+		t.Fatalf("unexpected decoded items %v %v %v", until, items, err)
+	}
+
+	if _, _, err := decodeCachedItems(data[:len(data)-3]); err == nil {
+		t.Error("expect the error of the truncated data")
+	}
+	if _, _, err := decodeCachedItems([]byte{9}); err == nil {
+		t.Error("expect the error of the unknown version")
+	}
+}
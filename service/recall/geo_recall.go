@@ -0,0 +1,165 @@
+package recall
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const (
+	Default_Geo_Rings             = 1
+	Default_Geo_Latitude_Feature  = "latitude"
+	Default_Geo_Longitude_Feature = "longitude"
+)
+
+// GeoRecall recalls the items near the user, the location of the user is the request features or the user properties,
+// the items of the geohash cell of the user and the neighbor cells are ranked by the distance with the optional freshness decay
+type GeoRecall struct {
+	*BaseRecall
+	*refreshLoop
+	dao               module.GeoItemDao
+	precision         int
+	rings             int
+	latitudeFeature   string
+	longitudeFeature  string
+	maxDistance       float64
+	freshnessHalfLife float64
+}
+
+func NewGeoRecall(config recconf.RecallConfig) *GeoRecall {
+	recall := newGeoRecall(config, module.NewGeoItemDao(config.GeoConf))
+
+	// the local index is reloaded by the interval after the recall is registered
+	if memoryDao, ok := recall.dao.(*module.GeoItemMemoryDao); ok {
+		recall.refreshLoop = newRefreshLoop("GeoRecall", config.Name, time.Duration(config.GeoConf.RefreshInterval)*time.Second, func() {
+			if err := memoryDao.Load(); err != nil {
+				log.Error(fmt.Sprintf("module=GeoRecall\tname=%s\tevent=loadIndex\terror=%v", recall.modelName, err))
+			}
+		})
+	}
+
+	return recall
+}
+
+func newGeoRecall(config recconf.RecallConfig, dao module.GeoItemDao) *GeoRecall {
+	conf := config.GeoConf
+	recall := &GeoRecall{
+		BaseRecall:        NewBaseRecall(config),
+		dao:               dao,
+		precision:         conf.Precision,
+		rings:             conf.Rings,
+		latitudeFeature:   conf.LatitudeFeature,
+		longitudeFeature:  conf.LongitudeFeature,
+		maxDistance:       conf.MaxDistance,
+		freshnessHalfLife: float64(conf.FreshnessHalfLife),
+	}
+	if recall.precision <= 0 {
+		recall.precision = module.Default_Geo_Precision
+	}
+	if recall.rings <= 0 {
+		recall.rings = Default_Geo_Rings
+	}
+	if recall.latitudeFeature == "" {
+		recall.latitudeFeature = Default_Geo_Latitude_Feature
+	}
+	if recall.longitudeFeature == "" {
+		recall.longitudeFeature = Default_Geo_Longitude_Feature
+	}
+
+	return recall
+}
+
+// location returns the latitude and longitude of the request features, or the user properties when the features have no location,
+// the location of the request features not parsable is no location
+func (r *GeoRecall) location(user *module.User, context *context.RecommendContext) (latitude, longitude float64, ok bool) {
+	var features map[string]interface{}
+	if context.Param != nil {
+		features, _ = context.GetParameter("features").(map[string]interface{})
+	}
+	if features != nil {
+		latValue, latOk := features[r.latitudeFeature]
+		lngValue, lngOk := features[r.longitudeFeature]
+		if latOk && lngOk {
+			latitude = utils.ToFloat(latValue, math.NaN())
+			longitude = utils.ToFloat(lngValue, math.NaN())
+			if math.IsNaN(latitude) || math.IsNaN(longitude) {
+				log.Warning(fmt.Sprintf("requestId=%s\tmodule=GeoRecall\tname=%s\terror=location not parsable, latitude:%v, longitude:%v", context.RecommendId, r.modelName, latValue, lngValue))
+				return 0, 0, false
+			}
+			return latitude, longitude, true
+		}
+	}
+
+	var err error
+	if latitude, err = user.FloatProperty(r.latitudeFeature); err != nil {
+		return 0, 0, false
+	}
+	if longitude, err = user.FloatProperty(r.longitudeFeature); err != nil {
+		return 0, 0, false
+	}
+
+	return latitude, longitude, true
+}
+
+func (r *GeoRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
+	start := time.Now()
+
+	latitude, longitude, ok := r.location(user, context)
+	if !ok || math.IsNaN(latitude) || math.IsNaN(longitude) || latitude < utils.MIN_LATITUDE || latitude > utils.MAX_LATITUDE || longitude < utils.MIN_LONGITUDE || longitude > utils.MAX_LONGITUDE {
+		log.Info(fmt.Sprintf("requestId=%s\tmodule=GeoRecall\tname=%s\tmsg=user location invalid\tcount=0\tcost=%d", context.RecommendId, r.modelName, utils.CostTime(start)))
+		return
+	}
+
+	geohashes := utils.GeoHashNeighbors(latitude, longitude, r.precision, r.rings)
+	cells, err := r.dao.GeoItems(context.Context(), geohashes)
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=GeoRecall\tname=%s\terror=%v\tcost=%d", context.RecommendId, r.modelName, err, utils.CostTime(start)))
+		return
+	}
+
+	// the score is 1/(1+distance in km), decayed by the age of the item when FreshnessHalfLife is set
+	now := start.Unix()
+	exists := make(map[string]bool)
+	for _, geohash := range geohashes {
+		for _, geoItem := range cells[geohash] {
+			if exists[geoItem.Id] {
+				continue
+			}
+			exists[geoItem.Id] = true
+
+			distance := utils.GeoDistance(latitude, longitude, geoItem.Latitude, geoItem.Longitude)
+			if r.maxDistance > 0 && distance > r.maxDistance {
+				continue
+			}
+
+			score := 1 / (1 + distance/1000)
+			if r.freshnessHalfLife > 0 && geoItem.Timestamp > 0 && now > geoItem.Timestamp {
+				score *= math.Exp2(-float64(now-geoItem.Timestamp) / r.freshnessHalfLife)
+			}
+
+			item := module.NewItem(geoItem.Id)
+			item.RetrieveId = r.modelName
+			item.ItemType = r.itemType
+			item.Score = score
+			item.AddProperty("geo_distance", distance)
+			ret = append(ret, item)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Score > ret[j].Score
+	})
+	if len(ret) > r.recallCount {
+		ret = ret[:r.recallCount]
+	}
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=GeoRecall\tname=%s\tcells=%d\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(geohashes), len(ret), utils.CostTime(start)))
+	return
+}
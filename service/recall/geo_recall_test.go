@@ -0,0 +1,120 @@
+package recall
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type fakeFeatureParam map[string]interface{}
+
+func (p fakeFeatureParam) GetParameter(name string) interface{} {
+	if name == "features" {
+		return map[string]interface{}(p)
+	}
+	return nil
+}
+
+func TestGeoRecall(t *testing.T) {
+	now := time.Now().Unix()
+	file := filepath.Join(t.TempDir(), "geo_items.txt")
+	content := "near\t39.9235\t116.3910\n" +
+		"far\t39.9300\t116.4000\n" +
+		"fresh\t39.9240\t116.3915\t" + strconv.FormatInt(now, 10) + "\n" +
+		"stale\t39.9236\t116.3911\t" + strconv.FormatInt(now-7200, 10) + "\n" +
+		"other_city\t31.2304\t121.4737\n" +
+		"null_island\t0.0001\t0.0001\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := recconf.RecallConfig{
+		Name:        "geo_recall",
+		RecallCount: 10,
+		GeoConf: recconf.GeoRecallConfig{
+			InMemory:          true,
+			FilePath:          file,
+			MaxDistance:       1000,
+			FreshnessHalfLife: 3600,
+		},
+	}
+	recall := NewGeoRecall(config)
+
+	ctx := context.NewRecommendContext()
+	ctx.Param = fakeFeatureParam{"latitude": 39.92324, "longitude": "116.3906"}
+	items := recall.GetCandidateItems(module.NewUser("u1"), ctx)
+
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, string(item.Id))
+		if item.RetrieveId != "geo_recall" {
+			t.Errorf("expect the retrieve id geo_recall, got %s", item.RetrieveId)
+		}
+	}
+	// far is beyond the max distance, and stale is decayed by the freshness
+	if len(ids) != 3 || ids[0] != "near" || ids[1] != "fresh" || ids[2] != "stale" {
+		t.Fatalf("expect the items near, fresh and stale, got %v", ids)
+	}
+
+	user := module.NewUser("u2")
+	user.AddProperty("latitude", 31.2300)
+	user.AddProperty("longitude", 121.4740)
+	if items := recall.GetCandidateItems(user, context.NewRecommendContext()); len(items) != 1 || items[0].Id != "other_city" {
+		t.Errorf("expect the item of the user location, got %v", items)
+	}
+
+	if items := recall.GetCandidateItems(module.NewUser("u3"), context.NewRecommendContext()); len(items) != 0 {
+		t.Errorf("expect no item without the location, got %d", len(items))
+	}
+
+	// the location not parsable is not taken as 0
+	ctx = context.NewRecommendContext()
+	ctx.Param = fakeFeatureParam{"latitude": "unknown", "longitude": 0}
+	if items := recall.GetCandidateItems(module.NewUser("u4"), ctx); len(items) != 0 {
+		t.Errorf("expect no item of the location not parsable, got %v", items)
+	}
+}
+
+func TestGeoRecallLoadIndex(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "geo_items.txt")
+	if err := os.WriteFile(file, []byte("near\t39.9235\t116.3910\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := recconf.RecallConfig{
+		Name:        "geo_load_index_recall",
+		RecallCount: 10,
+		GeoConf: recconf.GeoRecallConfig{
+			InMemory:        true,
+			FilePath:        file,
+			RefreshInterval: 1,
+		},
+	}
+	recall := NewGeoRecall(config)
+	count := func() int {
+		ctx := context.NewRecommendContext()
+		ctx.Param = fakeFeatureParam{"latitude": 39.9235, "longitude": 116.3910}
+		return len(recall.GetCandidateItems(module.NewUser("u1"), ctx))
+	}
+
+	if err := os.WriteFile(file, []byte("near\t39.9235\t116.3910\nfresh\t39.9240\t116.3915\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(1200 * time.Millisecond)
+	if n := count(); n != 1 {
+		t.Fatalf("expect the index not reloaded before the recall is registered, got %d items", n)
+	}
+
+	RegisterRecall(config.Name, recall)
+	defer RegisterRecall(config.Name, NewMockRecall(config))
+	time.Sleep(1200 * time.Millisecond)
+	if n := count(); n != 2 {
+		t.Errorf("expect the index reloaded after the recall is registered, got %d items", n)
+	}
+}
@@ -0,0 +1,165 @@
+package recall
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+	"github.com/alibaba/pairec/v2/utils/hnsw"
+)
+
+// HNSWVectorRecall searches the user vector in the in-process hnsw index of the item vectors, the index is built in the background
+// after the recall is registered, and the recall returns no item until the first index is built. Then the index is rebuilt by the RefreshInterval,
+// the new index replaces the old one when it is built.
+type HNSWVectorRecall struct {
+	*BaseRecall
+	*refreshLoop
+	dao       module.VectorDao
+	itemDao   module.ItemVectorDao
+	config    hnsw.Config
+	efSearch  int
+	index     atomic.Pointer[hnsw.Index]
+	buildOnce sync.Once
+}
+
+func NewHNSWVectorRecall(config recconf.RecallConfig) *HNSWVectorRecall {
+	return newHNSWVectorRecall(config, module.NewVectorDao(config),
+		module.NewItemVectorDao(config.HNSWConf.FilePath, config.HNSWConf.ItemVectorDaoConf))
+}
+
+func newHNSWVectorRecall(config recconf.RecallConfig, dao module.VectorDao, itemDao module.ItemVectorDao) *HNSWVectorRecall {
+	recall := &HNSWVectorRecall{
+		BaseRecall: NewBaseRecall(config),
+		dao:        dao,
+		itemDao:    itemDao,
+		config: hnsw.Config{
+			M:              config.HNSWConf.M,
+			EfConstruction: config.HNSWConf.EfConstruction,
+			Metric:         strings.ToLower(config.HNSWConf.MetricType),
+		},
+		efSearch: config.HNSWConf.EfSearch,
+	}
+	if recall.efSearch <= 0 {
+		recall.efSearch = hnsw.Default_Ef_Search
+	}
+	recall.refreshLoop = newRefreshLoop("HNSWVectorRecall", config.Name, time.Duration(config.HNSWConf.RefreshInterval)*time.Second, recall.refreshIndex)
+
+	return recall
+}
+
+// buildIndex loads the item vectors and builds the new index, the index in use is replaced only when the new one is built
+func (r *HNSWVectorRecall) buildIndex() error {
+	start := time.Now()
+	vectors, err := r.itemDao.ItemVectors()
+	if err != nil {
+		return fmt.Errorf("load item vectors error, recall:%s, err:%w", r.modelName, err)
+	}
+	if len(vectors) == 0 {
+		return fmt.Errorf("item vectors empty, recall:%s", r.modelName)
+	}
+
+	index, err := hnsw.New(r.config)
+	if err != nil {
+		return err
+	}
+	for _, v := range vectors {
+		if err := index.Add(v.Id, v.Vector); err != nil {
+			return fmt.Errorf("build hnsw index error, recall:%s, err:%w", r.modelName, err)
+		}
+	}
+
+	r.index.Store(index)
+	log.Info(fmt.Sprintf("module=HNSWVectorRecall\tname=%s\tevent=buildIndex\tcount=%d\tcost=%d", r.modelName, index.Len(), utils.CostTime(start)))
+
+	return nil
+}
+
+// start builds the first index in the background, so the config is not blocked by the build of the large index,
+// and starts the refresh of the index
+func (r *HNSWVectorRecall) start() {
+	r.buildOnce.Do(func() {
+		go r.refreshIndex()
+	})
+	r.refreshLoop.start()
+}
+
+// refreshIndex rebuilds the index, the index in use is kept when it fails
+func (r *HNSWVectorRecall) refreshIndex() {
+	if err := r.buildIndex(); err != nil {
+		log.Error(fmt.Sprintf("module=HNSWVectorRecall\tname=%s\tevent=refreshIndex\terror=%v", r.modelName, err))
+	}
+}
+
+func (r *HNSWVectorRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
+	start := time.Now()
+
+	index := r.index.Load()
+	if index == nil {
+		log.Warning(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\terror=index not built", context.RecommendId, r.modelName))
+		return
+	}
+
+	value, err := module.VectorStringWithContext(context.Context(), r.dao, string(user.Id))
+	if err != nil {
+		if errors.Is(err, module.VectoryEmptyError) {
+			log.Info(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
+		} else {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\terr=%v\tcost=%d", context.RecommendId, r.modelName, err, utils.CostTime(start)))
+		}
+		return
+	}
+
+	// the user vectors are separated by |, each one is searched for the recall count divided by the count of the vectors
+	var vectors [][]float32
+	for _, vectorStr := range strings.Split(value, "|") {
+		if vector, err := utils.ParseVector(vectorStr); err == nil && len(vector) > 0 {
+			vectors = append(vectors, vector)
+		}
+	}
+	if len(vectors) == 0 {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\terror=user vector empty", context.RecommendId, r.modelName))
+		return
+	}
+
+	k := r.recallCount / len(vectors)
+	if k <= 0 {
+		k = 1
+	}
+	ef := r.efSearch
+	if ef < k {
+		ef = k
+	}
+
+	ret = make([]*module.Item, 0, r.recallCount)
+	exists := make(map[string]bool, r.recallCount)
+	for _, vector := range vectors {
+		if len(vector) != index.Dim() {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\terror=user vector dimension %d, expect %d", context.RecommendId, r.modelName, len(vector), index.Dim()))
+			continue
+		}
+
+		for _, result := range index.Search(vector, k, ef) {
+			if exists[result.Id] {
+				continue
+			}
+			exists[result.Id] = true
+
+			item := module.NewItem(result.Id)
+			item.RetrieveId = r.modelName
+			item.ItemType = r.itemType
+			item.Score = float64(result.Score)
+			ret = append(ret, item)
+		}
+	}
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=HNSWVectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
+	return
+}
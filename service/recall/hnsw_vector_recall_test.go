@@ -0,0 +1,85 @@
+package recall
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type fakeUserVectorDao map[string]string
+
+func (d fakeUserVectorDao) VectorString(id string) (string, error) {
+	if v, ok := d[id]; ok {
+		return v, nil
+	}
+	return "", module.VectoryEmptyError
+}
+
+type fakeItemVectorDao struct {
+	vectors []*module.ItemVector
+	err     error
+}
+
+func (d *fakeItemVectorDao) ItemVectors() ([]*module.ItemVector, error) {
+	return d.vectors, d.err
+}
+
+func TestHNSWVectorRecall(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "item_vectors.txt")
+	content := "1\t1,0\n2\t0.8,0.2\n3\t0,1\n\n4\t-1,0\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := recconf.RecallConfig{Name: "hnsw_recall", RecallCount: 2, HNSWConf: recconf.HNSWConfig{MetricType: "cosine"}}
+	itemDao := module.NewItemVectorFileDao(file)
+	recall := newHNSWVectorRecall(config, fakeUserVectorDao{"u1": "[1, 0]", "u2": "1,0,0"}, itemDao)
+	if items := recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext()); len(items) != 0 {
+		t.Errorf("expect no item before the index is built, got %d", len(items))
+	}
+
+	// the first index is built in the background after the recall is started
+	recall.start()
+	defer recall.stop()
+	for i := 0; i < 100 && recall.index.Load() == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if recall.index.Load() == nil {
+		t.Fatal("expect the index built after the recall is started")
+	}
+
+	items := recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+	if len(items) != 2 || items[0].Id != "1" || items[1].Id != "2" || items[0].RetrieveId != "hnsw_recall" {
+		t.Fatalf("expect the closest items 1 and 2, got %v", items)
+	}
+
+	if items := recall.GetCandidateItems(module.NewUser("u2"), context.NewRecommendContext()); len(items) != 0 {
+		t.Errorf("expect no item of the user vector of the wrong dimension, got %d", len(items))
+	}
+
+	// the index in use is kept when the rebuild fails
+	failedDao := &fakeItemVectorDao{err: errors.New("load error")}
+	recall.itemDao = failedDao
+	if err := recall.buildIndex(); err == nil {
+		t.Error("expect the load error")
+	}
+	if recall.index.Load().Len() != 4 {
+		t.Errorf("expect the index kept, got %d vectors", recall.index.Load().Len())
+	}
+
+	failedDao.err = nil
+	failedDao.vectors = []*module.ItemVector{{Id: "5", Vector: []float32{1, 0}}}
+	if err := recall.buildIndex(); err != nil {
+		t.Fatal(err)
+	}
+	items = recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+	if len(items) != 1 || items[0].Id != "5" {
+		t.Errorf("expect the items of the new index, got %v", items)
+	}
+}
@@ -102,7 +102,7 @@ func (r *HologresVectorRecallV2) GetCandidateItems(user *module.User, context *c
 		userEmbedding = value.(string)
 		user.AddProperty(r.modelName+"_embedding", userEmbedding)
 	} else {
-		emb, err := r.dao.VectorString(string(user.Id))
+		emb, err := module.VectorStringWithContext(context.Context(), r.dao, string(user.Id))
 		if err != nil {
 			if !errors.Is(err, module.VectoryEmptyError) {
 				context.LogError(fmt.Sprintf("get user vector failed. %s, err=%v", r.modelName, err))
@@ -78,7 +78,7 @@ func (r *I2IVectorRecall) GetCandidateItems(user *module.User, context *context.
 			return
 		}
 	}
-	value, err := r.dao.VectorString(string(itemId))
+	value, err := module.VectorStringWithContext(context.Context(), r.dao, string(itemId))
 	if err != nil {
 		if errors.Is(err, module.VectoryEmptyError) {
 			log.Info(fmt.Sprintf("requestId=%s\tmodule=HologresVectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
@@ -105,7 +105,7 @@ func (r *I2IVectorRecall) GetCandidateItems(user *module.User, context *context.
 		}
 	}
 
-	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	ctx, cancel := gocontext.WithTimeout(context.Context(), 100*time.Millisecond)
 	defer cancel()
 	rows, err := r.dbStmt.QueryContext(ctx, value)
 	if err != nil {
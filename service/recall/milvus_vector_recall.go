@@ -0,0 +1,185 @@
+//go:build milvus
+
+package recall
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alibaba/pairec/v2/algorithm"
+	"github.com/alibaba/pairec/v2/algorithm/milvus"
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+func init() {
+	RegisterRecallFactory("MilvusVectorRecall", func(config recconf.RecallConfig) Recall { return NewMilvusVectorRecall(config) })
+}
+
+// MilvusVectorRecall searches the items of the user vector in the milvus collection, the user vector is fetched by the VectorDao,
+// and the milvus server is the MILVUS algorithm of RecallAlgo
+type MilvusVectorRecall struct {
+	*BaseRecall
+	dao            module.VectorDao
+	collectionName string
+	vectorField    string
+	metricType     string
+	searchParams   map[string]interface{}
+	partitions     []string
+	expr           string
+}
+
+func NewMilvusVectorRecall(config recconf.RecallConfig) *MilvusVectorRecall {
+	conf := config.MilvusVectorConf
+	if conf.CollectionName == "" {
+		panic("MilvusVectorConf.CollectionName is empty")
+	}
+	if conf.VectorEmbeddingField == "" {
+		panic("MilvusVectorConf.VectorEmbeddingField is empty")
+	}
+
+	recall := &MilvusVectorRecall{
+		BaseRecall:     NewBaseRecall(config),
+		dao:            module.NewVectorDao(config),
+		collectionName: conf.CollectionName,
+		vectorField:    conf.VectorEmbeddingField,
+		metricType:     conf.MetricType,
+		searchParams:   conf.SearchParams,
+		partitions:     conf.Partitions,
+		expr:           conf.Expr,
+	}
+
+	return recall
+}
+
+func (r *MilvusVectorRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
+	start := time.Now()
+
+	if r.cache != nil {
+		key := r.cachePrefix + string(user.Id)
+		cacheRet := r.cache.Get(key)
+		if itemStr, ok := cacheRet.([]uint8); ok {
+			for _, id := range strings.Split(string(itemStr), ",") {
+				var item *module.Item
+				if strings.Contains(id, ":") {
+					vars := strings.Split(id, ":")
+					item = module.NewItem(vars[0])
+					f, _ := strconv.ParseFloat(vars[2], 64)
+					item.Score = f
+				} else {
+					item = module.NewItem(id)
+				}
+				item.RetrieveId = r.modelName
+				item.ItemType = r.itemType
+				ret = append(ret, item)
+			}
+			log.Info(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\thit cache\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
+			return
+		}
+	}
+
+	value, err := module.VectorStringWithContext(context.Context(), r.dao, string(user.Id))
+	if err != nil {
+		if errors.Is(err, module.VectoryEmptyError) {
+			log.Info(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
+		} else {
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\terr=%v\tcost=%d", context.RecommendId, r.modelName, err, utils.CostTime(start)))
+		}
+		return
+	}
+
+	request := r.newRequest(value)
+	if len(request.Vectors) == 0 {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\terror=user vector empty", context.RecommendId, r.modelName))
+		return
+	}
+
+	result, err := algorithm.RunWithContext(context.Context(), r.recallAlgo, request)
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\terror=%v", context.RecommendId, r.modelName, err))
+		return
+	}
+	responses, ok := result.([]*milvus.MilvusResponse)
+	if !ok {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\terror=invalid response type %T", context.RecommendId, r.modelName, result))
+		return
+	}
+
+	ret = r.mergeResponses(responses)
+
+	if r.cache != nil && len(ret) > 0 {
+		go func() {
+			key := r.cachePrefix + string(user.Id)
+			var itemIds string
+			for _, item := range ret {
+				itemIds += fmt.Sprintf("%s:%s:%v", string(item.Id), r.modelName, item.Score) + ","
+			}
+			itemIds = itemIds[:len(itemIds)-1]
+			if err := r.cache.Put(key, itemIds, time.Duration(r.cacheTime)*time.Second); err != nil {
+				log.Error(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\terror=%v", context.RecommendId, err))
+			}
+		}()
+	}
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=MilvusVectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
+	return
+}
+
+// newRequest creates the search request of the user vectors, the vectors are separated by |,
+// and each one is searched for the recall count divided by the count of the vectors
+func (r *MilvusVectorRecall) newRequest(value string) *milvus.MilvusRequest {
+	request := milvus.NewMilvusRequest()
+	request.CollectionName = r.collectionName
+	request.VectorField = r.vectorField
+	request.Partitions = r.partitions
+	request.Expr = r.expr
+	request.SetMetricType(r.metricType)
+	if r.searchParams != nil {
+		request.SetSearchParams(r.searchParams)
+	}
+
+	for _, vectorStr := range strings.Split(value, "|") {
+		if vector, err := utils.ParseVector(vectorStr); err == nil && len(vector) > 0 {
+			request.AppendVectors(vector)
+		}
+	}
+
+	if len(request.Vectors) > 0 {
+		request.TopK = r.recallCount / len(request.Vectors)
+		if request.TopK <= 0 {
+			request.TopK = 1
+		}
+	}
+
+	return request
+}
+
+// mergeResponses merges the items of the vectors, the item found by several vectors keeps the score of the first one
+func (r *MilvusVectorRecall) mergeResponses(responses []*milvus.MilvusResponse) []*module.Item {
+	items := make([]*module.Item, 0, r.recallCount)
+	exists := make(map[string]bool, r.recallCount)
+	for _, response := range responses {
+		for i, id := range response.Ids {
+			if exists[id] {
+				continue
+			}
+			exists[id] = true
+
+			item := module.NewItem(id)
+			item.RetrieveId = r.modelName
+			item.ItemType = r.itemType
+			if i < len(response.Scores) {
+				item.Score = float64(response.Scores[i])
+			}
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
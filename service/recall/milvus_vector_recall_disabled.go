@@ -0,0 +1,12 @@
+//go:build !milvus
+
+package recall
+
+import (
+	"github.com/alibaba/pairec/v2/algorithm"
+)
+
+// MilvusVectorRecall is built with the milvus tag only, the recall is rejected with the error of the config without it
+func init() {
+	registerDisabledRecallType("MilvusVectorRecall", algorithm.Milvus_Disabled_Error)
+}
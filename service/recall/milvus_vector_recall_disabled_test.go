@@ -0,0 +1,22 @@
+//go:build !milvus
+
+package recall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/algorithm"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func TestMilvusVectorRecallDisabled(t *testing.T) {
+	config := &recconf.RecommendConfig{
+		RecallConfs: []recconf.RecallConfig{{Name: "milvus_disabled_recall", RecallType: "MilvusVectorRecall"}},
+	}
+
+	_, errs := Prepare(config)
+	if len(errs) != 1 || !errors.Is(errs[0].Err, algorithm.Milvus_Disabled_Error) {
+		t.Fatalf("expect the error of the milvus recall not built, got %v", errs)
+	}
+}
@@ -0,0 +1,88 @@
+//go:build milvus
+
+package recall
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+
+	"github.com/alibaba/pairec/v2/algorithm"
+	"github.com/alibaba/pairec/v2/algorithm/milvus"
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type fakeVectorDao map[string]string
+
+func (d fakeVectorDao) VectorString(id string) (string, error) {
+	if v, ok := d[id]; ok {
+		return v, nil
+	}
+	return "", module.VectoryEmptyError
+}
+
+type fakeMilvusClient struct {
+	topK int
+}
+
+func (c *fakeMilvusClient) Search(ctx gocontext.Context, collName string, partitions []string, expr string, outputFields []string, vectors []entity.Vector,
+	vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error) {
+	c.topK = topK
+	results := make([]client.SearchResult, 0, len(vectors))
+	for i := range vectors {
+		// the item 2 is found by all the vectors
+		results = append(results, client.SearchResult{
+			ResultCount: 2,
+			IDs:         entity.NewColumnInt64("id", []int64{int64(i + 10), 2}),
+			Scores:      []float32{0.9, 0.5},
+		})
+	}
+	return results, nil
+}
+
+// fakeMilvusAlgorithm searches by the fake client without connecting the milvus server
+type fakeMilvusAlgorithm struct {
+	*milvus.MilvusModel
+}
+
+func (a *fakeMilvusAlgorithm) Init(conf *recconf.AlgoConfig) error {
+	return nil
+}
+
+func TestMilvusVectorRecall(t *testing.T) {
+	fakeClient := &fakeMilvusClient{}
+	algorithm.RegisterAlgorithmCreator("FAKE_MILVUS", func(conf recconf.AlgoConfig) algorithm.IAlgorithm {
+		return &fakeMilvusAlgorithm{MilvusModel: milvus.NewMilvusModelWithClient(conf.Name, fakeClient, time.Second)}
+	})
+	algorithm.AddAlgo(recconf.AlgoConfig{Name: "milvus_test", Type: "FAKE_MILVUS"})
+
+	config := recconf.RecallConfig{Name: "milvus_recall", RecallCount: 10, RecallAlgo: "milvus_test"}
+	recall := &MilvusVectorRecall{
+		BaseRecall:     NewBaseRecall(config),
+		dao:            fakeVectorDao{"u1": "0.1,0.2|0.3,0.4", "u2": "1:0.1 2:0.2"},
+		collectionName: "items",
+		vectorField:    "embedding",
+	}
+
+	items := recall.GetCandidateItems(module.NewUser("u1"), context.NewRecommendContext())
+	if len(items) != 3 || fakeClient.topK != 5 {
+		t.Fatalf("expect the items of the two vectors merged, got %d items, topK %d", len(items), fakeClient.topK)
+	}
+	if items[0].Id != "10" || items[0].RetrieveId != "milvus_recall" || items[0].Score < 0.89 {
+		t.Errorf("expect the item of the first vector, got %v", items[0])
+	}
+
+	items = recall.GetCandidateItems(module.NewUser("u2"), context.NewRecommendContext())
+	if len(items) != 2 || fakeClient.topK != 10 {
+		t.Errorf("expect the items of the vector in the index:value format, got %d items, topK %d", len(items), fakeClient.topK)
+	}
+
+	if items := recall.GetCandidateItems(module.NewUser("u3"), context.NewRecommendContext()); len(items) != 0 {
+		t.Errorf("expect no item without the user vector, got %d", len(items))
+	}
+}
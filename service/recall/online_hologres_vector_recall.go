@@ -119,7 +119,7 @@ func (r *OnlineHologresVectorRecall) GetCandidateItems(user *module.User, contex
 		algoGenerator.SetItemFeatures(nil)
 		algoGenerator.AddFeatures(mockItem, nil, user.MakeUserFeatures2())
 		algoData := algoGenerator.GeneratorAlgoData()
-		algoRet, err := algorithm.Run(r.recallAlgo, algoData.GetFeatures())
+		algoRet, err := algorithm.RunWithContext(context.Context(), r.recallAlgo, algoData.GetFeatures())
 		if err != nil {
 			context.LogError(fmt.Sprintf("requestId=%s\tmodule=OnlineHologresVectorRecall\tname=%s\terr=%v", context.RecommendId, r.modelName, err))
 		} else {
@@ -0,0 +1,242 @@
+package recall
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const (
+	Default_Online_I2I_Event_Field    = "event"
+	Default_Online_I2I_Neighbor_Count = 50
+	Default_Online_I2I_Record_Queue   = 10000
+)
+
+func init() {
+	recconf.RegisterSchemaEnum("OnlineI2IConfig.Algorithm", module.Covisit_Algorithm_Swing, module.Covisit_Algorithm_Covisit)
+}
+
+// onlineI2IRecalls are the recalls of the config in use fed by the callback items, they are replaced when the config is committed
+var (
+	onlineI2IRecalls   = make(map[string]*OnlineI2IRecall)
+	onlineI2IRecallsMu sync.RWMutex
+)
+
+// onlineI2IRecordCh queues the callback items recorded by the worker, so the callbacks are not blocked by the statistics
+var (
+	onlineI2IRecordCh   chan *onlineI2IRecord
+	onlineI2IRecordOnce sync.Once
+)
+
+type onlineI2IRecord struct {
+	scene     string
+	userId    string
+	items     []*module.Item
+	timestamp int64
+}
+
+// OnlineI2IRecall recalls the neighbors of the triggers of the user by the i2i statistics computed online from the callback items,
+// so the new items have the neighbors in minutes instead of after the batch job of the i2i table.
+// The triggers are fetched by the RealTimeUser2ItemDao, or the recent items of the user recorded by the statistics when it is not configured.
+type OnlineI2IRecall struct {
+	*BaseRecall
+	triggerDao    module.RealTimeUser2ItemDao
+	covisitDao    *module.ItemCovisitDao
+	neighborCount int
+	scenes        map[string]bool
+	eventField    string
+	events        map[string]bool
+}
+
+func NewOnlineI2IRecall(config recconf.RecallConfig) *OnlineI2IRecall {
+	var triggerDao module.RealTimeUser2ItemDao
+	if config.RealTimeUser2ItemDaoConf.UserTriggerDaoConf.AdapterType != "" {
+		triggerDao = module.NewRealTimeUser2ItemDao(config)
+	}
+
+	return newOnlineI2IRecall(config, triggerDao, module.NewItemCovisitDao(config.Name, config.OnlineI2IConf))
+}
+
+func newOnlineI2IRecall(config recconf.RecallConfig, triggerDao module.RealTimeUser2ItemDao, covisitDao *module.ItemCovisitDao) *OnlineI2IRecall {
+	conf := config.OnlineI2IConf
+	recall := &OnlineI2IRecall{
+		BaseRecall:    NewBaseRecall(config),
+		triggerDao:    triggerDao,
+		covisitDao:    covisitDao,
+		neighborCount: conf.NeighborCount,
+		scenes:        make(map[string]bool, len(conf.Scenes)),
+		eventField:    conf.EventField,
+		events:        make(map[string]bool, len(conf.Events)),
+	}
+	if recall.neighborCount <= 0 {
+		recall.neighborCount = Default_Online_I2I_Neighbor_Count
+	}
+	if recall.eventField == "" {
+		recall.eventField = Default_Online_I2I_Event_Field
+	}
+	for _, scene := range conf.Scenes {
+		recall.scenes[scene] = true
+	}
+	for _, event := range conf.Events {
+		recall.events[event] = true
+	}
+
+	return recall
+}
+
+// setOnlineI2IRecalls keeps the online i2i recalls registered by the names, so the recalls not in the config are not fed any more,
+// and the local statistics of the others are released
+func setOnlineI2IRecalls(names []string) {
+	recalls := make(map[string]*OnlineI2IRecall)
+	daos := make([]*module.ItemCovisitDao, 0, len(names))
+	for _, name := range names {
+		if recall, err := GetRecall(name); err == nil {
+			if onlineRecall, ok := unwrapRecall(recall).(*OnlineI2IRecall); ok {
+				recalls[name] = onlineRecall
+				daos = append(daos, onlineRecall.covisitDao)
+			}
+		}
+	}
+
+	onlineI2IRecallsMu.Lock()
+	onlineI2IRecalls = recalls
+	onlineI2IRecallsMu.Unlock()
+
+	module.RetainItemCovisitMemoryStorages(daos)
+}
+
+// RecordCallbackItems queues the callback items of the user to feed the statistics of the online i2i recalls of the scene,
+// the items are dropped when the queue is full
+func RecordCallbackItems(scene string, userId string, items []*module.Item) {
+	onlineI2IRecallsMu.RLock()
+	count := len(onlineI2IRecalls)
+	onlineI2IRecallsMu.RUnlock()
+	if count == 0 || len(items) == 0 {
+		return
+	}
+
+	onlineI2IRecordOnce.Do(func() {
+		onlineI2IRecordCh = make(chan *onlineI2IRecord, Default_Online_I2I_Record_Queue)
+		go runOnlineI2IRecord()
+	})
+
+	select {
+	case onlineI2IRecordCh <- &onlineI2IRecord{scene: scene, userId: userId, items: items, timestamp: time.Now().Unix()}:
+	default:
+		log.Warning(fmt.Sprintf("module=OnlineI2IRecall\tevent=record\tuid=%s\terror=record queue full", userId))
+	}
+}
+
+// runOnlineI2IRecord feeds the queued items to the online i2i recalls of the config in use
+func runOnlineI2IRecord() {
+	for record := range onlineI2IRecordCh {
+		onlineI2IRecallsMu.RLock()
+		recalls := make([]*OnlineI2IRecall, 0, len(onlineI2IRecalls))
+		for _, recall := range onlineI2IRecalls {
+			recalls = append(recalls, recall)
+		}
+		onlineI2IRecallsMu.RUnlock()
+
+		for _, recall := range recalls {
+			if err := recall.Record(record.scene, record.userId, record.items, record.timestamp); err != nil {
+				log.Error(fmt.Sprintf("module=OnlineI2IRecall\tname=%s\tevent=record\tuid=%s\terror=%v", recall.modelName, record.userId, err))
+			}
+		}
+	}
+}
+
+// Record adds the items of the configured scenes and events to the statistics
+func (r *OnlineI2IRecall) Record(scene string, userId string, items []*module.Item, timestamp int64) error {
+	if len(r.scenes) > 0 && !r.scenes[scene] {
+		return nil
+	}
+
+	itemIds := make([]string, 0, len(items))
+	for _, item := range items {
+		if len(r.events) > 0 && !r.events[item.StringProperty(r.eventField)] {
+			continue
+		}
+		itemIds = append(itemIds, string(item.Id))
+	}
+
+	return r.covisitDao.AddUserItems(userId, itemIds, timestamp)
+}
+
+func (r *OnlineI2IRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) (ret []*module.Item) {
+	start := time.Now()
+
+	triggers := r.triggers(user, context)
+	if len(triggers) == 0 {
+		log.Info(fmt.Sprintf("requestId=%s\tmodule=OnlineI2IRecall\tname=%s\tcount=0\tcost=%d", context.RecommendId, r.modelName, utils.CostTime(start)))
+		return
+	}
+
+	triggerIds := make([]string, 0, len(triggers))
+	for itemId := range triggers {
+		triggerIds = append(triggerIds, itemId)
+	}
+	neighbors, err := r.covisitDao.Neighbors(triggerIds, r.neighborCount, start.Unix())
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=OnlineI2IRecall\tname=%s\terror=%v", context.RecommendId, r.modelName, err))
+		return
+	}
+
+	// the score of the item is the sum of the trigger weights multiplied by the neighbor scores, the triggers themselves are excluded
+	scores := make(map[string]float64)
+	for triggerId, weight := range triggers {
+		for itemId, score := range neighbors[triggerId] {
+			if _, ok := triggers[itemId]; ok {
+				continue
+			}
+			scores[itemId] += weight * score
+		}
+	}
+
+	ret = make([]*module.Item, 0, len(scores))
+	for itemId, score := range scores {
+		item := module.NewItem(itemId)
+		item.RetrieveId = r.modelName
+		item.ItemType = r.itemType
+		item.Score = score
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score == ret[j].Score {
+			return ret[i].Id < ret[j].Id
+		}
+		return ret[i].Score > ret[j].Score
+	})
+	if len(ret) > r.recallCount {
+		ret = ret[:r.recallCount]
+	}
+
+	log.Info(fmt.Sprintf("requestId=%s\tmodule=OnlineI2IRecall\tname=%s\ttriggers=%d\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(triggers), len(ret), utils.CostTime(start)))
+	return
+}
+
+// triggers returns the trigger weights of the user, the recent items recorded by the statistics have the weight 1
+func (r *OnlineI2IRecall) triggers(user *module.User, context *context.RecommendContext) map[string]float64 {
+	if r.triggerDao != nil {
+		return r.triggerDao.GetTriggers(user, context)
+	}
+
+	events, err := r.covisitDao.UserItems(string(user.Id))
+	if err != nil {
+		log.Error(fmt.Sprintf("requestId=%s\tmodule=OnlineI2IRecall\tname=%s\terror=%v", context.RecommendId, r.modelName, err))
+		return nil
+	}
+
+	triggers := make(map[string]float64, len(events))
+	for _, event := range events {
+		triggers[event.ItemId] = 1
+	}
+
+	return triggers
+}
@@ -0,0 +1,102 @@
+package recall
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func newCallbackItem(id, event string) *module.Item {
+	item := module.NewItem(id)
+	item.AddProperty("event", event)
+	return item
+}
+
+func TestOnlineI2IRecall(t *testing.T) {
+	config := recconf.RecallConfig{
+		Name:        "online_i2i",
+		RecallCount: 2,
+		OnlineI2IConf: recconf.OnlineI2IConfig{
+			Scenes: []string{"home"},
+			Events: []string{"click"},
+		},
+	}
+	covisitDao := module.NewItemCovisitDaoWithStorage(module.NewItemCovisitMemoryStorage(config.OnlineI2IConf), config.OnlineI2IConf)
+	recall := newOnlineI2IRecall(config, nil, covisitDao)
+
+	now := time.Now().Unix()
+	records := []struct {
+		scene string
+		user  string
+		items []*module.Item
+	}{
+		{"home", "u1", []*module.Item{newCallbackItem("a", "click"), newCallbackItem("b", "click"), newCallbackItem("x", "expose")}},
+		{"home", "u2", []*module.Item{newCallbackItem("a", "click"), newCallbackItem("b", "click"), newCallbackItem("c", "click")}},
+		{"detail", "u3", []*module.Item{newCallbackItem("a", "click"), newCallbackItem("d", "click")}},
+		{"home", "u4", []*module.Item{newCallbackItem("a", "click")}},
+	}
+	for _, record := range records {
+		if err := recall.Record(record.scene, record.user, record.items, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := recall.GetCandidateItems(module.NewUser("u4"), context.NewRecommendContext())
+	if len(items) != 2 || items[0].Id != "b" || items[1].Id != "c" || items[0].RetrieveId != "online_i2i" {
+		t.Fatalf("expect the neighbors b and c of the trigger a, got %v", items)
+	}
+	if items[0].Score <= items[1].Score {
+		t.Errorf("expect b co-visited by more users scores higher, got %v and %v", items[0].Score, items[1].Score)
+	}
+
+	if items := recall.GetCandidateItems(module.NewUser("u5"), context.NewRecommendContext()); len(items) != 0 {
+		t.Errorf("expect no item of the user without triggers, got %d", len(items))
+	}
+}
+
+func TestOnlineI2IRecallCommit(t *testing.T) {
+	registered := func(name string) bool {
+		onlineI2IRecallsMu.RLock()
+		defer onlineI2IRecallsMu.RUnlock()
+		_, ok := onlineI2IRecalls[name]
+		return ok
+	}
+
+	config := &recconf.RecommendConfig{
+		RecallConfs: []recconf.RecallConfig{{Name: "online_i2i_commit", RecallType: "OnlineI2IRecall", RecallCount: 10}},
+	}
+	commit, errs := Prepare(config)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if registered("online_i2i_commit") {
+		t.Fatal("expect the recall not fed before the config is committed")
+	}
+
+	commit()
+	if !registered("online_i2i_commit") {
+		t.Fatal("expect the recall fed after the config is committed")
+	}
+
+	// the callback items are recorded by the worker in the background
+	RecordCallbackItems("home", "u1", []*module.Item{newCallbackItem("a", "click"), newCallbackItem("b", "click")})
+	RecordCallbackItems("home", "u2", []*module.Item{newCallbackItem("a", "click")})
+	recall, _ := GetRecall("online_i2i_commit")
+	onlineRecall := recall.(*OnlineI2IRecall)
+	var items []*module.Item
+	for i := 0; i < 100 && len(items) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		items = onlineRecall.GetCandidateItems(module.NewUser("u2"), context.NewRecommendContext())
+	}
+	if len(items) != 1 || items[0].Id != "b" {
+		t.Errorf("expect the neighbor b recorded by the committed recall, got %v", items)
+	}
+
+	Load(&recconf.RecommendConfig{})
+	if registered("online_i2i_commit") {
+		t.Error("expect the recall not fed after it is removed from the config")
+	}
+}
@@ -102,7 +102,7 @@ func (r *OnlineVectorRecall) GetCandidateItems(user *module.User, context *conte
 	algoGenerator.SetItemFeatures(nil)
 	algoGenerator.AddFeatures(nil, nil, user.MakeUserFeatures2())
 	algoData := algoGenerator.GeneratorAlgoData()
-	algoRet, err := algorithm.Run(r.recallAlgo, algoData.GetFeatures())
+	algoRet, err := algorithm.RunWithContext(context.Context(), r.recallAlgo, algoData.GetFeatures())
 	if err != nil {
 		context.LogError(fmt.Sprintf("requestId=%s\tmodule=OnlineVectorRecall\tname=%s\terr=%v", context.RecommendId, r.modelName, err))
 	} else {
@@ -2,7 +2,9 @@ package recall
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/alibaba/pairec/v2/context"
 	"github.com/alibaba/pairec/v2/log"
@@ -17,13 +19,126 @@ type Recall interface {
 }
 
 var recalls = make(map[string]Recall)
+
+// recallSigns keeps the sign of each recall registered from the config, the recalls not in it are registered by the code
 var recallSigns = make(map[string]string)
 
+// recallsMu guards recalls, the recalls are read by the requests and the refresh loops while the config is reloaded
+var recallsMu sync.RWMutex
+
+// recallTimeouts keeps the timeout(ms) of each recall config
+var recallTimeouts = make(map[string]int)
+var recallTimeoutsMu sync.RWMutex
+
+// RecallFactory creates the recall of the RecallType from the config
+type RecallFactory func(config recconf.RecallConfig) Recall
+
+// recallFactories are the factories of the recall types used by Load
+var recallFactories = make(map[string]RecallFactory)
+
+// RegisterRecallFactory registers the factory of the recall type, so the custom recall can be configured by RecallType like the built-in ones,
+// the factory of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterRecallFactory(recallType string, factory RecallFactory) {
+	if factory == nil {
+		panic("RecallFactory is nil, type:" + recallType)
+	}
+
+	recallFactories[recallType] = factory
+	recconf.RegisterSchemaEnum("RecallConfig.RecallType", recallType)
+}
+
+// disabledRecallTypes are the recall types not built in the server, the recall of them fails to construct with the error
+var disabledRecallTypes = make(map[string]error)
+
+// registerDisabledRecallType keeps the recall type valid in the config, while the recall of it is rejected with the err
+func registerDisabledRecallType(recallType string, err error) {
+	disabledRecallTypes[recallType] = err
+	recconf.RegisterSchemaEnum("RecallConfig.RecallType", recallType)
+}
+
+func init() {
+	RegisterRecallFactory("UserCollaborativeFilterRecall", func(config recconf.RecallConfig) Recall { return NewUserCollaborativeFilterRecall(config) })
+	RegisterRecallFactory("UserTopicRecall", func(config recconf.RecallConfig) Recall { return NewUserTopicRecall(config) })
+	RegisterRecallFactory("VectorRecall", func(config recconf.RecallConfig) Recall { return NewVectorRecall(config) })
+	RegisterRecallFactory("UserCustomRecall", func(config recconf.RecallConfig) Recall { return NewUserCustomRecall(config) })
+	RegisterRecallFactory("HologresVectorRecall", func(config recconf.RecallConfig) Recall { return NewHologresVectorRecall(config) })
+	RegisterRecallFactory("HologresVectorRecallV2", func(config recconf.RecallConfig) Recall { return NewHologresVectorRecallV2(config) })
+	RegisterRecallFactory("ItemCollaborativeFilterRecall", func(config recconf.RecallConfig) Recall { return NewItemCollaborativeFilterRecall(config) })
+	RegisterRecallFactory("UserGroupHotRecall", func(config recconf.RecallConfig) Recall { return NewUserGroupHotRecall(config) })
+	RegisterRecallFactory("UserGlobalHotRecall", func(config recconf.RecallConfig) Recall { return NewUserGlobalHotRecall(config) })
+	RegisterRecallFactory("I2IVectorRecall", func(config recconf.RecallConfig) Recall { return NewI2IVectorRecall(config) })
+	RegisterRecallFactory("ColdStartRecall", func(config recconf.RecallConfig) Recall { return NewColdStartRecall(config) })
+	RegisterRecallFactory("BeRecall", func(config recconf.RecallConfig) Recall { return NewBeRecall(config) })
+	RegisterRecallFactory("RealTimeU2IRecall", func(config recconf.RecallConfig) Recall { return NewRealTimeU2IRecall(config) })
+	RegisterRecallFactory("OnlineHologresVectorRecall", func(config recconf.RecallConfig) Recall { return NewOnlineHologresVectorRecall(config) })
+	RegisterRecallFactory("GraphRecall", func(config recconf.RecallConfig) Recall { return NewGraphRecall(config) })
+	RegisterRecallFactory("MockRecall", func(config recconf.RecallConfig) Recall { return NewMockRecall(config) })
+	RegisterRecallFactory("OpenSearchRecall", func(config recconf.RecallConfig) Recall { return NewOpenSearchRecall(config) })
+	RegisterRecallFactory("OnlineVectorRecall", func(config recconf.RecallConfig) Recall { return NewOnlineVectorRecall(config) })
+	RegisterRecallFactory("HNSWVectorRecall", func(config recconf.RecallConfig) Recall { return NewHNSWVectorRecall(config) })
+	RegisterRecallFactory("OnlineI2IRecall", func(config recconf.RecallConfig) Recall { return NewOnlineI2IRecall(config) })
+	RegisterRecallFactory("GeoRecall", func(config recconf.RecallConfig) Recall { return NewGeoRecall(config) })
+}
+
+// newRecall creates the recall by the factory, and wraps it by the cache of the items when ResultCacheConf is set
+func newRecall(factory RecallFactory, config recconf.RecallConfig) Recall {
+	recall := factory(config)
+	if config.ResultCacheConf.CacheAdapter == "" {
+		return recall
+	}
+
+	cachedRecall, err := NewCachedRecall(config, recall)
+	if err != nil {
+		panic(err)
+	}
+	return cachedRecall
+}
+
+// unwrapRecall returns the recall wrapped by the cache of the items
+func unwrapRecall(recall Recall) Recall {
+	if cachedRecall, ok := recall.(*CachedRecall); ok {
+		return cachedRecall.Unwrap()
+	}
+	return recall
+}
+
+// RegisterRecall registers the recall by name, the background refresh of the recall starts here
+// and the one of the replaced recall stops
 func RegisterRecall(name string, recall Recall) {
+	recallsMu.Lock()
+	old := recalls[name]
 	recalls[name] = recall
+	recallsMu.Unlock()
+
+	if old == recall {
+		return
+	}
+	if r, ok := unwrapRecall(old).(backgroundRecall); ok {
+		r.stop()
+	}
+	if r, ok := unwrapRecall(recall).(backgroundRecall); ok {
+		r.start()
+	}
+}
+// unregisterRecall removes the recall by name and stops its background refresh
+func unregisterRecall(name string) {
+	recallsMu.Lock()
+	old, ok := recalls[name]
+	delete(recalls, name)
+	recallsMu.Unlock()
+
+	if !ok {
+		return
+	}
+	if r, ok := unwrapRecall(old).(backgroundRecall); ok {
+		r.stop()
+	}
 }
+
 func GetRecall(name string) (Recall, error) {
+	recallsMu.RLock()
 	recall, ok := recalls[name]
+	recallsMu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("recall:not found, recall name:%s", name)
 	}
@@ -34,66 +149,88 @@ func GetRecall(name string) (Recall, error) {
 
 	return recall, nil
 }
-func Load(config *recconf.RecommendConfig) {
+
+// Prepare builds the changed recalls of the config without registering them, commit registers the recalls built
+// and removes the recalls of the previous config not in the config.
+// The construction errors of all the recalls are returned, and nothing should be committed when there are errors.
+func Prepare(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]Recall)
+	signs := make(map[string]string)
+	timeouts := make(map[string]int)
 	for _, conf := range config.RecallConfs {
-		if _, ok := recalls[conf.Name]; ok {
-			sign, _ := json.Marshal(&conf)
+		timeouts[conf.Name] = conf.Timeout
+
+		sign, _ := json.Marshal(&conf)
+		if _, err := GetRecall(conf.Name); err == nil {
 			if utils.Md5(string(sign)) == recallSigns[conf.Name] {
 				continue
 			}
 		}
 
-		var recall Recall
-		if conf.RecallType == "UserCollaborativeFilterRecall" {
-			recall = NewUserCollaborativeFilterRecall(conf)
-		} else if conf.RecallType == "UserTopicRecall" {
-			recall = NewUserTopicRecall(conf)
-		} else if conf.RecallType == "VectorRecall" {
-			recall = NewVectorRecall(conf)
-		} else if conf.RecallType == "UserCustomRecall" {
-			recall = NewUserCustomRecall(conf)
-		} else if conf.RecallType == "HologresVectorRecall" {
-			recall = NewHologresVectorRecall(conf)
-		} else if conf.RecallType == "HologresVectorRecallV2" {
-			recall = NewHologresVectorRecallV2(conf)
-		} else if conf.RecallType == "ItemCollaborativeFilterRecall" {
-			recall = NewItemCollaborativeFilterRecall(conf)
-		} else if conf.RecallType == "UserGroupHotRecall" {
-			recall = NewUserGroupHotRecall(conf)
-		} else if conf.RecallType == "UserGlobalHotRecall" {
-			recall = NewUserGlobalHotRecall(conf)
-		} else if conf.RecallType == "I2IVectorRecall" {
-			recall = NewI2IVectorRecall(conf)
-		} else if conf.RecallType == "ColdStartRecall" {
-			recall = NewColdStartRecall(conf)
-		} else if conf.RecallType == "MilvusVectorRecall" {
-			//recall = NewMilvusVectorRecall(conf)
-		} else if conf.RecallType == "BeRecall" {
-			recall = NewBeRecall(conf)
-		} else if conf.RecallType == "RealTimeU2IRecall" {
-			recall = NewRealTimeU2IRecall(conf)
-		} else if conf.RecallType == "OnlineHologresVectorRecall" {
-			recall = NewOnlineHologresVectorRecall(conf)
-		} else if conf.RecallType == "GraphRecall" {
-			recall = NewGraphRecall(conf)
-		} else if conf.RecallType == "MockRecall" {
-			recall = NewMockRecall(conf)
-		} else if conf.RecallType == "OpenSearchRecall" {
-			recall = NewOpenSearchRecall(conf)
-		} else if conf.RecallType == "OnlineVectorRecall" {
-			recall = NewOnlineVectorRecall(conf)
+		factory, ok := recallFactories[conf.RecallType]
+		if !ok {
+			err, disabled := disabledRecallTypes[conf.RecallType]
+			if !disabled {
+				err = errors.New("recall type not registered")
+			}
+			errs = append(errs, recconf.NewModuleError("recall", conf.Name, conf.RecallType, err))
+			continue
 		}
 
-		if recall == nil {
-			panic(fmt.Sprintf("recall empty, name:%s", conf.Name))
+		conf := conf
+		recall, err := recconf.BuildModule("recall", conf.Name, conf.RecallType, func() Recall { return newRecall(factory, conf) })
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		RegisterRecall(conf.Name, recall)
-		sign, _ := json.Marshal(&conf)
-		recallSigns[conf.Name] = utils.Md5(string(sign))
+		built[conf.Name] = recall
+		signs[conf.Name] = utils.Md5(string(sign))
+	}
+
+	commit = func() {
+		recallTimeoutsMu.Lock()
+		for name := range recallSigns {
+			if _, ok := timeouts[name]; !ok {
+				delete(recallTimeouts, name)
+			}
+		}
+		for name, timeout := range timeouts {
+			recallTimeouts[name] = timeout
+		}
+		recallTimeoutsMu.Unlock()
+
+		for name := range recallSigns {
+			if _, ok := timeouts[name]; !ok {
+				unregisterRecall(name)
+				delete(recallSigns, name)
+			}
+		}
+
+		for name, recall := range built {
+			RegisterRecall(name, recall)
+			recallSigns[name] = signs[name]
+		}
+
+		names := make([]string, 0, len(config.RecallConfs))
+		for _, conf := range config.RecallConfs {
+			names = append(names, conf.Name)
+		}
+		setOnlineI2IRecalls(names)
+	}
 
+	return commit, errs
+}
+
+// Load registers the changed recalls of the config, it panics with the errors of all the recalls when any recall fails to construct,
+// and no recall is registered in that case
+func Load(config *recconf.RecommendConfig) {
+	commit, errs := Prepare(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
 	}
 
+	commit()
 }
 
 type BaseRecall struct {
@@ -0,0 +1,226 @@
+package recall
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+const (
+	Normalization_MinMax = "minmax"
+	Normalization_ZScore = "zscore"
+	Normalization_Rank   = "rank"
+
+	Fusion_Sum = "sum"
+	Fusion_Max = "max"
+	Fusion_RRF = "rrf"
+
+	Default_RRF_K = 60
+)
+
+func init() {
+	recconf.RegisterSchemaEnum("RecallMergeConfig.Normalization", Normalization_MinMax, Normalization_ZScore, Normalization_Rank)
+	recconf.RegisterSchemaEnum("RecallMergeConfig.Fusion", Fusion_Sum, Fusion_Max, Fusion_RRF)
+}
+
+// RecallMerger merges the items of the recalls, the scores of each recall are normalized on its own scale
+// and weighted by the recall, then the scores of the duplicated items are fused into the score of the item
+type RecallMerger struct {
+	normalization string
+	fusion        string
+	rrfK          float64
+	weights       map[string]float64
+}
+
+func NewRecallMerger(config recconf.RecallMergeConfig) (*RecallMerger, error) {
+	merger := &RecallMerger{
+		normalization: config.Normalization,
+		fusion:        config.Fusion,
+		rrfK:          float64(config.RRFK),
+		weights:       config.RecallWeights,
+	}
+
+	switch merger.normalization {
+	case "", Normalization_MinMax, Normalization_ZScore, Normalization_Rank:
+	default:
+		return nil, fmt.Errorf("recall merge normalization not support, normalization:%s", merger.normalization)
+	}
+
+	switch merger.fusion {
+	case "":
+		merger.fusion = Fusion_Sum
+	case Fusion_Sum, Fusion_Max, Fusion_RRF:
+	default:
+		return nil, fmt.Errorf("recall merge fusion not support, fusion:%s", merger.fusion)
+	}
+
+	if merger.rrfK <= 0 {
+		merger.rrfK = Default_RRF_K
+	}
+
+	return merger, nil
+}
+
+// Merge returns the unique items ordered by the fused score, the item keeps the RetrieveId of its first occurrence,
+// and the original scores of the recalls are kept in RecallScores. The weights override the weights of the config by the recall name.
+func (m *RecallMerger) Merge(items []*module.Item, weights map[string]float64) []*module.Item {
+	if len(items) == 0 {
+		return items
+	}
+
+	// the items of each recall in the order of the scores, the duplicated item of the same recall is counted once
+	recallItems := make(map[string][]*module.Item)
+	var recallNames []string
+	seen := make(map[string]map[module.ItemId]bool)
+	for _, item := range items {
+		ids, ok := seen[item.RetrieveId]
+		if !ok {
+			ids = make(map[module.ItemId]bool)
+			seen[item.RetrieveId] = ids
+			recallNames = append(recallNames, item.RetrieveId)
+		}
+		if ids[item.Id] {
+			continue
+		}
+		ids[item.Id] = true
+		recallItems[item.RetrieveId] = append(recallItems[item.RetrieveId], item)
+	}
+
+	merged := make(map[module.ItemId]*module.Item, len(items))
+	fused := make(map[module.ItemId]float64, len(items))
+	ret := make([]*module.Item, 0, len(items))
+	for _, name := range recallNames {
+		list := recallItems[name]
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Score > list[j].Score
+		})
+
+		weight := m.weight(name, weights)
+		scores := m.normalize(list)
+		for i, item := range list {
+			var score float64
+			if m.fusion == Fusion_RRF {
+				score = weight / (m.rrfK + float64(i+1))
+			} else {
+				score = weight * scores[i]
+			}
+
+			exist, ok := merged[item.Id]
+			if !ok {
+				exist = item
+				merged[item.Id] = item
+				ret = append(ret, item)
+				if exist.RecallScores == nil {
+					exist.RecallScores = make(map[string]float64)
+				}
+				fused[item.Id] = score
+			} else {
+				exist.AddAlgoScores(item.GetAlgoScores())
+				if m.fusion == Fusion_Max {
+					fused[item.Id] = math.Max(fused[item.Id], score)
+				} else {
+					fused[item.Id] += score
+				}
+			}
+			exist.RecallScores[name] = item.Score
+		}
+	}
+
+	for _, item := range ret {
+		item.Score = fused[item.Id]
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Score > ret[j].Score
+	})
+
+	return ret
+}
+
+// ExperimentRecallWeights returns the recall weights of the experiment param key, such as category.RecallWeights,
+// nil is returned when the request has no experiment or the param is not set
+func ExperimentRecallWeights(context *context.RecommendContext, key string) map[string]float64 {
+	if context.ExperimentResult == nil {
+		return nil
+	}
+
+	params, ok := context.ExperimentResult.GetExperimentParams().Get(key, nil).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	weights := make(map[string]float64, len(params))
+	for name, value := range params {
+		switch v := value.(type) {
+		case float64:
+			weights[name] = v
+		case int:
+			weights[name] = float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weights[name] = f
+			}
+		}
+	}
+
+	return weights
+}
+
+func (m *RecallMerger) weight(name string, weights map[string]float64) float64 {
+	if w, ok := weights[name]; ok {
+		return w
+	}
+	if w, ok := m.weights[name]; ok {
+		return w
+	}
+
+	return 1
+}
+
+// normalize returns the normalized scores of the items ordered by the scores
+func (m *RecallMerger) normalize(items []*module.Item) []float64 {
+	scores := make([]float64, len(items))
+	for i, item := range items {
+		scores[i] = item.Score
+	}
+
+	switch m.normalization {
+	case Normalization_MinMax:
+		max, min := scores[0], scores[len(scores)-1]
+		for i := range scores {
+			if max == min {
+				scores[i] = 1
+			} else {
+				scores[i] = (scores[i] - min) / (max - min)
+			}
+		}
+	case Normalization_ZScore:
+		var sum, squareSum float64
+		for _, score := range scores {
+			sum += score
+		}
+		mean := sum / float64(len(scores))
+		for _, score := range scores {
+			squareSum += (score - mean) * (score - mean)
+		}
+		std := math.Sqrt(squareSum / float64(len(scores)))
+		for i := range scores {
+			if std == 0 {
+				scores[i] = 0
+			} else {
+				scores[i] = (scores[i] - mean) / std
+			}
+		}
+	case Normalization_Rank:
+		n := float64(len(scores))
+		for i := range scores {
+			scores[i] = (n - float64(i)) / n
+		}
+	}
+
+	return scores
+}
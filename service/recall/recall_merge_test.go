@@ -0,0 +1,98 @@
+package recall
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func newRecallItem(id, recallName string, score float64) *module.Item {
+	item := module.NewItem(id)
+	item.RetrieveId = recallName
+	item.Score = score
+	return item
+}
+
+func recallMergeItems() []*module.Item {
+	return []*module.Item{
+		// the cf scores are the similarities, and the hot scores are the counts
+		newRecallItem("1", "cf", 0.9),
+		newRecallItem("2", "cf", 0.5),
+		newRecallItem("3", "cf", 0.1),
+		newRecallItem("2", "hot", 1000),
+		newRecallItem("4", "hot", 500),
+		newRecallItem("5", "hot", 0),
+	}
+}
+
+func TestRecallMergerMinMax(t *testing.T) {
+	merger, err := NewRecallMerger(recconf.RecallMergeConfig{Normalization: Normalization_MinMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := merger.Merge(recallMergeItems(), nil)
+	if len(items) != 5 {
+		t.Fatalf("expect the duplicated item merged, got %d items", len(items))
+	}
+	// item 2 is 0.5 of cf and 1 of hot
+	if items[0].Id != "2" || math.Abs(items[0].Score-1.5) > 1e-6 {
+		t.Errorf("expect item 2 with the fused score 1.5, got %s %v", items[0].Id, items[0].Score)
+	}
+	if items[0].RecallScores["cf"] != 0.5 || items[0].RecallScores["hot"] != 1000 {
+		t.Errorf("expect the original scores of the recalls, got %v", items[0].RecallScores)
+	}
+
+	// the weight of the experiment overrides the weight of the config
+	merger, _ = NewRecallMerger(recconf.RecallMergeConfig{
+		Normalization: Normalization_MinMax,
+		Fusion:        Fusion_Max,
+		RecallWeights: map[string]float64{"cf": 2, "hot": 3},
+	})
+	items = merger.Merge(recallMergeItems(), map[string]float64{"hot": 0.1})
+	if items[0].Id != "1" || math.Abs(items[0].Score-2) > 1e-6 {
+		t.Errorf("expect item 1 with the weighted score 2, got %s %v", items[0].Id, items[0].Score)
+	}
+}
+
+func TestRecallMergerRRF(t *testing.T) {
+	merger, _ := NewRecallMerger(recconf.RecallMergeConfig{Fusion: Fusion_RRF, RRFK: 10})
+
+	items := merger.Merge(recallMergeItems(), nil)
+	// item 2 is the second of cf and the first of hot
+	expect := 1.0/12 + 1.0/11
+	if items[0].Id != "2" || math.Abs(items[0].Score-expect) > 1e-6 {
+		t.Errorf("expect item 2 with the rrf score %v, got %s %v", expect, items[0].Id, items[0].Score)
+	}
+}
+
+func TestRecallMergerNormalize(t *testing.T) {
+	testcases := []struct {
+		normalization string
+		expect        []float64
+	}{
+		{Normalization_ZScore, []float64{math.Sqrt(1.5), 0, -math.Sqrt(1.5)}},
+		{Normalization_Rank, []float64{1, 2.0 / 3, 1.0 / 3}},
+		{"", []float64{0.9, 0.5, 0.1}},
+	}
+
+	for _, testcase := range testcases {
+		merger, _ := NewRecallMerger(recconf.RecallMergeConfig{Normalization: testcase.normalization})
+		scores := merger.normalize(recallMergeItems()[:3])
+		for i, score := range scores {
+			if math.Abs(score-testcase.expect[i]) > 1e-6 {
+				t.Errorf("expect the %s scores %v, got %v", testcase.normalization, testcase.expect, scores)
+				break
+			}
+		}
+	}
+
+	if _, err := NewRecallMerger(recconf.RecallMergeConfig{Normalization: "unknown"}); err == nil {
+		t.Error("expect the normalization error")
+	}
+	if _, err := NewRecallMerger(recconf.RecallMergeConfig{Fusion: "unknown"}); err == nil {
+		t.Error("expect the fusion error")
+	}
+}
@@ -0,0 +1,123 @@
+package recall
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/service/metrics"
+)
+
+type recallResult struct {
+	index    int
+	items    []*module.Item
+	timeout  bool
+	duration time.Duration
+}
+
+// RunRecalls invokes the recalls concurrently and merges their items.
+// names must have the same length as recalls, the name is used to find the per-recall timeout and to record the dropped recall.
+// A recall which misses its own timeout, the budget of all recalls or the request deadline is dropped,
+// the items of the other recalls are returned.
+func RunRecalls(user *module.User, context *context.RecommendContext, names []string, recalls []Recall, budget time.Duration) (ret []*module.Item) {
+	start := time.Now()
+	ch := make(chan recallResult, len(recalls))
+
+	for i := 0; i < len(recalls); i++ {
+		go runRecall(ch, i, recalls[i], GetRecallTimeout(names[i]), user, context)
+	}
+
+	done := context.Context().Done()
+	if budget > 0 {
+		budgetCtx, cancel := context.WithTimeout(budget)
+		defer cancel()
+		done = budgetCtx.Done()
+	}
+
+	// ch is buffered, the recalls which are dropped will not block on it, so it is not closed here
+	finished := make([]bool, len(recalls))
+	for i := 0; i < len(recalls); i++ {
+		select {
+		case result := <-ch:
+			finished[result.index] = true
+			if result.timeout {
+				recordDroppedRecall(context, names[result.index], result.duration)
+				continue
+			}
+			ret = append(ret, result.items...)
+		case <-done:
+			for index, ok := range finished {
+				if !ok {
+					recordDroppedRecall(context, names[index], time.Since(start))
+				}
+			}
+			return
+		}
+	}
+
+	return
+}
+
+func runRecall(ch chan<- recallResult, index int, recall Recall, timeout time.Duration, user *module.User, context *context.RecommendContext) {
+	start := time.Now()
+	itemsCh := make(chan []*module.Item, 1)
+	go func() {
+		// when recall is panic, can recover it
+		defer func() {
+			if err := recover(); err != nil {
+				stack := string(debug.Stack())
+				log.Error(fmt.Sprintf("error=%v, stack=%s", err, strings.ReplaceAll(stack, "\n", "\t")))
+
+				var tmp []*module.Item
+				itemsCh <- tmp
+			}
+		}()
+
+		itemsCh <- recall.GetCandidateItems(user, context)
+	}()
+
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	select {
+	case items := <-itemsCh:
+		ch <- recallResult{index: index, items: items, duration: time.Since(start)}
+	case <-timer:
+		ch <- recallResult{index: index, timeout: true, duration: time.Since(start)}
+	}
+}
+
+func recordDroppedRecall(context *context.RecommendContext, name string, cost time.Duration) {
+	msg := fmt.Sprintf("module=recall\tname=%s\tevent=timeout\tcost=%d", name, cost.Milliseconds())
+	context.LogDebug(msg)
+	log.Warning(fmt.Sprintf("requestId=%s\t%s", context.RecommendId, msg))
+
+	if metrics.Enabled() {
+		metrics.RecallTimeoutTotal.WithLabelValues(originRecallName(name)).Inc()
+	}
+}
+
+// GetRecallTimeout returns the timeout of the recall config, the recall cloned by the experiment params
+// uses the timeout of the origin recall
+func GetRecallTimeout(name string) time.Duration {
+	recallTimeoutsMu.RLock()
+	defer recallTimeoutsMu.RUnlock()
+	return time.Duration(recallTimeouts[originRecallName(name)]) * time.Millisecond
+}
+
+// originRecallName returns the origin name of the recall cloned by the experiment params, which is named as "name#sign"
+func originRecallName(name string) string {
+	if index := strings.Index(name, "#"); index != -1 {
+		return name[:index]
+	}
+
+	return name
+}
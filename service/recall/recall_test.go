@@ -0,0 +1,116 @@
+package recall
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+type customRecall struct {
+	count int
+}
+
+func (r *customRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) []*module.Item {
+	return nil
+}
+
+func TestRegisterRecallFactory(t *testing.T) {
+	var created int
+	RegisterRecallFactory("CustomTestRecall", func(config recconf.RecallConfig) Recall {
+		created++
+		return &customRecall{count: config.RecallCount}
+	})
+
+	config := &recconf.RecommendConfig{
+		RecallConfs: []recconf.RecallConfig{{Name: "custom_test_recall", RecallType: "CustomTestRecall", RecallCount: 10}},
+	}
+	Load(config)
+	// the recall is not recreated when the config is not changed
+	Load(config)
+
+	recall, err := GetRecall("custom_test_recall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := recall.(*customRecall); !ok || r.count != 10 || created != 1 {
+		t.Errorf("expect the custom recall created once, got %v, created:%d", recall, created)
+	}
+
+	config.RecallConfs[0].RecallCount = 20
+	Load(config)
+	recall, _ = GetRecall("custom_test_recall")
+	if r := recall.(*customRecall); r.count != 20 || created != 2 {
+		t.Errorf("expect the custom recall recreated after the config changed, got %d, created:%d", r.count, created)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expect panic of the recall type not registered")
+		}
+	}()
+	Load(&recconf.RecommendConfig{RecallConfs: []recconf.RecallConfig{{Name: "unknown_recall", RecallType: "UnknownRecall"}}})
+}
+
+type refreshRecall struct {
+	customRecall
+	*refreshLoop
+	refreshed atomic.Int32
+}
+
+func newRefreshRecall(name string) *refreshRecall {
+	r := &refreshRecall{}
+	r.refreshLoop = newRefreshLoop("RefreshRecall", name, 5*time.Millisecond, func() { r.refreshed.Add(1) })
+	return r
+}
+
+func TestRegisterRecallRefresh(t *testing.T) {
+	old := newRefreshRecall("refresh_test_recall")
+	time.Sleep(20 * time.Millisecond)
+	if old.refreshed.Load() != 0 {
+		t.Fatal("expect no refresh before the recall is registered")
+	}
+
+	RegisterRecall("refresh_test_recall", old)
+	time.Sleep(20 * time.Millisecond)
+	if old.refreshed.Load() == 0 {
+		t.Fatal("expect the refresh after the recall is registered")
+	}
+
+	RegisterRecall("refresh_test_recall", newRefreshRecall("refresh_test_recall"))
+	time.Sleep(10 * time.Millisecond)
+	count := old.refreshed.Load()
+	time.Sleep(20 * time.Millisecond)
+	if old.refreshed.Load() != count {
+		t.Error("expect the refresh stopped after the recall is replaced")
+	}
+}
+
+func TestLoadRemoveRecall(t *testing.T) {
+	var loop *refreshLoop
+	RegisterRecallFactory("RemoveTestRecall", func(config recconf.RecallConfig) Recall {
+		r := newRefreshRecall(config.Name)
+		loop = r.refreshLoop
+		return r
+	})
+
+	Load(&recconf.RecommendConfig{
+		RecallConfs: []recconf.RecallConfig{{Name: "remove_test_recall", RecallType: "RemoveTestRecall"}},
+	})
+	if _, err := GetRecall("remove_test_recall"); err != nil {
+		t.Fatal(err)
+	}
+
+	Load(&recconf.RecommendConfig{})
+	if _, err := GetRecall("remove_test_recall"); err == nil {
+		t.Error("expect the recall removed after it is not in the config")
+	}
+	select {
+	case <-loop.done:
+	case <-time.After(time.Second):
+		t.Error("expect the refresh of the removed recall stopped")
+	}
+}
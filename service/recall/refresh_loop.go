@@ -0,0 +1,73 @@
+package recall
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alibaba/pairec/v2/log"
+)
+
+// backgroundRecall is the recall refreshing its data in the background, the refresh starts when the recall is registered
+// and stops when the recall of the name is replaced or removed, so the recall built by a rejected config never refreshes
+type backgroundRecall interface {
+	start()
+	stop()
+}
+
+// refreshLoop calls refresh by the interval after it is started until it is stopped, the nil loop or the loop of the interval
+// not positive does nothing
+type refreshLoop struct {
+	module    string
+	name      string
+	interval  time.Duration
+	refresh   func()
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+func newRefreshLoop(module, name string, interval time.Duration, refresh func()) *refreshLoop {
+	return &refreshLoop{
+		module:   module,
+		name:     name,
+		interval: interval,
+		refresh:  refresh,
+		done:     make(chan struct{}),
+	}
+}
+
+func (l *refreshLoop) start() {
+	if l == nil || l.interval <= 0 {
+		return
+	}
+
+	l.startOnce.Do(func() {
+		go l.run()
+	})
+}
+
+func (l *refreshLoop) stop() {
+	if l == nil {
+		return
+	}
+
+	l.stopOnce.Do(func() {
+		close(l.done)
+	})
+}
+
+func (l *refreshLoop) run() {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			log.Info(fmt.Sprintf("module=%s\tname=%s\tevent=stopRefresh", l.module, l.name))
+			return
+		case <-ticker.C:
+			l.refresh()
+		}
+	}
+}
@@ -56,7 +56,7 @@ func (r *VectorRecall) GetCandidateItems(user *module.User, context *context.Rec
 			return
 		}
 	}
-	value, err := r.dao.VectorString(string(user.Id))
+	value, err := module.VectorStringWithContext(context.Context(), r.dao, string(user.Id))
 	if err != nil {
 		if errors.Is(err, module.VectoryEmptyError) {
 			log.Info(fmt.Sprintf("requestId=%s\tmodule=VectorRecall\tname=%s\tcount=%d\tcost=%d", context.RecommendId, r.modelName, len(ret), utils.CostTime(start)))
@@ -85,7 +85,7 @@ func (r *VectorRecall) GetCandidateItems(user *module.User, context *context.Rec
 		return
 	}
 
-	result, err := algorithm.Run(r.recallAlgo, &request)
+	result, err := algorithm.RunWithContext(context.Context(), r.recallAlgo, &request)
 	if err != nil {
 		log.Error(fmt.Sprintf("requestId=%s\tmodule=VectorRecall\terror=%v", context.RecommendId, err))
 		return
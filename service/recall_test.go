@@ -0,0 +1,133 @@
+package service
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service/recall"
+)
+
+type testParam map[string]interface{}
+
+func (p testParam) GetParameter(name string) interface{} {
+	return p[name]
+}
+
+type sleepRecall struct {
+	name  string
+	sleep time.Duration
+}
+
+func (r *sleepRecall) GetCandidateItems(user *module.User, context *context.RecommendContext) []*module.Item {
+	time.Sleep(r.sleep)
+	item := module.NewItem(r.name + "_item")
+	item.RetrieveId = r.name
+	return []*module.Item{item}
+}
+
+func TestRecallServiceGetItemsWithDeadline(t *testing.T) {
+	recall.RegisterRecall("fast_recall", &sleepRecall{name: "fast_recall"})
+	recall.RegisterRecall("slow_recall", &sleepRecall{name: "slow_recall", sleep: time.Second})
+
+	Load(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"deadline_scene": {
+				"default": {RecallNames: []string{"fast_recall", "slow_recall"}},
+			},
+		},
+	})
+
+	ctx := context.NewRecommendContext()
+	ctx.Param = testParam{"scene": "deadline_scene", "category": "default"}
+	ctx.SetTimeout(50 * time.Millisecond)
+	defer ctx.Cancel()
+
+	start := time.Now()
+	items := (&RecallService{}).GetItems(module.NewUser("u1"), ctx)
+	if cost := time.Since(start); cost > 500*time.Millisecond {
+		t.Errorf("expect GetItems return before the slow recall finished, cost:%v", cost)
+	}
+	if len(items) != 1 || items[0].RetrieveId != "fast_recall" {
+		t.Errorf("expect only the items of fast_recall, got %v", items)
+	}
+}
+
+func TestRecallServiceGetItemsWithCanceledRequest(t *testing.T) {
+	recall.RegisterRecall("cancel_fast_recall", &sleepRecall{name: "cancel_fast_recall"})
+	recall.RegisterRecall("cancel_slow_recall", &sleepRecall{name: "cancel_slow_recall", sleep: time.Second})
+
+	Load(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"cancel_scene": {
+				"default": {RecallNames: []string{"cancel_fast_recall", "cancel_slow_recall"}},
+			},
+		},
+	})
+
+	requestCtx, cancel := gocontext.WithCancel(gocontext.Background())
+	ctx := context.NewRecommendContext()
+	ctx.Param = testParam{"scene": "cancel_scene", "category": "default"}
+	ctx.SetContext(requestCtx)
+	ctx.SetTimeout(10 * time.Second)
+	defer ctx.Cancel()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	items := (&RecallService{}).GetItems(module.NewUser("u1"), ctx)
+	if cost := time.Since(start); cost > 500*time.Millisecond {
+		t.Errorf("expect GetItems return when the request is canceled, cost:%v", cost)
+	}
+	if len(items) != 1 || items[0].RetrieveId != "cancel_fast_recall" {
+		t.Errorf("expect only the items of cancel_fast_recall, got %v", items)
+	}
+}
+
+func TestRecallServiceGetItemsWithRecallTimeout(t *testing.T) {
+	recall.Load(&recconf.RecommendConfig{
+		RecallConfs: []recconf.RecallConfig{
+			{Name: "timeout_recall", RecallType: "MockRecall", Timeout: 30},
+		},
+	})
+	recall.RegisterRecall("timeout_recall", &sleepRecall{name: "timeout_recall", sleep: time.Second})
+	recall.RegisterRecall("normal_recall", &sleepRecall{name: "normal_recall"})
+	recall.RegisterRecall("budget_recall", &sleepRecall{name: "budget_recall", sleep: time.Second})
+
+	recconf.Config.SceneConfs = map[string]map[string]recconf.CategoryConfig{
+		"budget_scene": {
+			"default": {RecallNames: []string{"normal_recall", "budget_recall"}, RecallTimeout: 30},
+		},
+	}
+	defer func() {
+		recconf.Config.SceneConfs = nil
+	}()
+	Load(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"timeout_scene": {
+				"default": {RecallNames: []string{"normal_recall", "timeout_recall"}},
+			},
+			"budget_scene": recconf.Config.SceneConfs["budget_scene"],
+		},
+	})
+
+	for _, scene := range []string{"timeout_scene", "budget_scene"} {
+		ctx := context.NewRecommendContext()
+		ctx.Param = testParam{"scene": scene, "category": "default"}
+
+		start := time.Now()
+		items := (&RecallService{}).GetItems(module.NewUser("u1"), ctx)
+		if cost := time.Since(start); cost > 500*time.Millisecond {
+			t.Errorf("scene:%s, expect the slow recall dropped, cost:%v", scene, cost)
+		}
+		if len(items) != 1 || items[0].RetrieveId != "normal_recall" {
+			t.Errorf("scene:%s, expect only the items of normal_recall, got %v", scene, items)
+		}
+	}
+}
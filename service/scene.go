@@ -2,12 +2,33 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service/recall"
 )
 
 var scenes = make(map[string]*Scene)
 
+// PrepareSceneConfig checks the categories of the scenes before they are loaded, the category of the invalid RecallMergeConf
+// is returned as the ModuleError, so the config is rejected instead of merging the recalls without it.
+// The categories are loaded by Load after the recalls are registered, so commit does nothing.
+func PrepareSceneConfig(conf *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	for sceneId, categoryConfs := range conf.SceneConfs {
+		for categoryName, categoryConf := range categoryConfs {
+			if categoryConf.RecallMergeConf == nil {
+				continue
+			}
+
+			if _, err := recall.NewRecallMerger(*categoryConf.RecallMergeConf); err != nil {
+				errs = append(errs, recconf.NewModuleError("category", sceneId+"#"+categoryName, "RecallMerge", err))
+			}
+		}
+	}
+
+	return func() {}, errs
+}
+
 func GetSence(sceneId string) (*Scene, error) {
 
 	scene, ok := scenes[sceneId]
@@ -17,6 +38,37 @@ func GetSence(sceneId string) (*Scene, error) {
 	return scene, nil
 }
 
+// GetSceneTimeout returns the request-level budget of the scene category config.
+// If the category is not configured, the default category is used. Zero means no budget.
+func GetSceneTimeout(sceneName, categoryName string) time.Duration {
+	categoryConf := getCategoryConfig(sceneName, categoryName)
+	return time.Duration(categoryConf.Timeout) * time.Millisecond
+}
+
+// GetSceneRecallTimeout returns the budget of all the recalls of the scene category config. Zero means no budget.
+func GetSceneRecallTimeout(sceneName, categoryName string) time.Duration {
+	categoryConf := getCategoryConfig(sceneName, categoryName)
+	return time.Duration(categoryConf.RecallTimeout) * time.Millisecond
+}
+
+// GetSceneCategoryConfig returns the category config of the scene, the default category is used if the category is not configured
+func GetSceneCategoryConfig(sceneName, categoryName string) recconf.CategoryConfig {
+	return getCategoryConfig(sceneName, categoryName)
+}
+
+func getCategoryConfig(sceneName, categoryName string) (categoryConf recconf.CategoryConfig) {
+	categoryConfs, ok := recconf.Config.SceneConfs[sceneName]
+	if !ok {
+		return
+	}
+	categoryConf, ok = categoryConfs[categoryName]
+	if !ok {
+		categoryConf = categoryConfs["default"]
+	}
+
+	return
+}
+
 func Load(conf *recconf.RecommendConfig) {
 
 	for sceneId, categoryConfs := range conf.SceneConfs {
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/service/feature"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const sharedUsersContextParam = "_shared_users"
+
+// SharedUsers shares the users with the loaded features between the requests of a batch,
+// the user features are loaded once for the same uid, request features and feature stages.
+type SharedUsers struct {
+	mu    sync.Mutex
+	users map[string]*sharedUser
+}
+
+type sharedUser struct {
+	done chan struct{}
+	user *module.User
+	err  error
+}
+
+func NewSharedUsers() *SharedUsers {
+	return &SharedUsers{
+		users: make(map[string]*sharedUser),
+	}
+}
+
+// WithSharedUsers makes the recommend of the context load the user from the shared users
+func WithSharedUsers(context *context.RecommendContext, users *SharedUsers) {
+	context.AddContextParam(sharedUsersContextParam, users)
+}
+
+// load returns the clone of the shared user, the first request of the key loads the user and the others wait for it
+func (s *SharedUsers) load(key string, context *context.RecommendContext, loadFunc func() *module.User) *module.User {
+	s.mu.Lock()
+	shared, ok := s.users[key]
+	if !ok {
+		shared = &sharedUser{done: make(chan struct{})}
+		s.users[key] = shared
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return shared.load(context, loadFunc)
+	}
+
+	select {
+	case <-shared.done:
+		if shared.err != nil {
+			// the shared user failed to load, load it by itself
+			log.Warning(fmt.Sprintf("requestId=%s\tmodule=SharedUsers\tkey=%s\terror=%v", context.RecommendId, key, shared.err))
+			return loadFunc()
+		}
+		return shared.user.Clone()
+	case <-context.Context().Done():
+		// the shared user is not ready in time, load it by itself
+		return loadFunc()
+	}
+}
+
+// load loads the user to share, the waiters are always released, a panic of the load is stored as the error and raised again
+func (shared *sharedUser) load(context *context.RecommendContext, loadFunc func() *module.User) *module.User {
+	defer close(shared.done)
+	defer func() {
+		if r := recover(); r != nil {
+			shared.err = fmt.Errorf("load user panic: %v", r)
+			panic(r)
+		}
+	}()
+
+	user := loadFunc()
+	if user == nil {
+		shared.err = errors.New("load user nil")
+		return user
+	}
+
+	// the features loaded asynchronously must be ready before the user is shared
+	if user.FeatureAsyncLoadCount() > 0 {
+		select {
+		case <-user.FeatureAsyncLoadCh():
+		case <-context.Context().Done():
+		}
+	}
+
+	// the user of this request may be modified by the later stages, so a clone is shared
+	shared.user = user.Clone()
+	return user
+}
+
+// loadUser creates the user of the request and loads the user features
+func loadUser(userId module.UID, context *context.RecommendContext, userFeatureService *feature.UserFeatureService) *module.User {
+	loadFunc := func() *module.User {
+		user := module.NewUserWithContext(userId, context)
+		userFeatureService.LoadUserFeatures(user, context)
+		return user
+	}
+
+	sharedUsers, ok := context.GetContextParam(sharedUsersContextParam).(*SharedUsers)
+	if !ok {
+		return loadFunc()
+	}
+
+	features, _ := json.Marshal(context.GetParameter("features"))
+	key := string(userId) + "#" + strings.Join(userFeatureService.GetLoadFeaturesStageNames(context), ",") + "#" + utils.Md5(string(features))
+	return sharedUsers.load(key, context, loadFunc)
+}
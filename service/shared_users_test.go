@@ -0,0 +1,83 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+)
+
+func TestSharedUsersLoad(t *testing.T) {
+	sharedUsers := NewSharedUsers()
+
+	var loadCount int32
+	loadFunc := func() *module.User {
+		atomic.AddInt32(&loadCount, 1)
+		time.Sleep(20 * time.Millisecond)
+		user := module.NewUser("u1")
+		user.AddProperty("age", 18)
+		return user
+	}
+
+	users := make([]*module.User, 5)
+	var wg sync.WaitGroup
+	for i := 0; i < len(users); i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			users[index] = sharedUsers.load("u1", context.NewRecommendContext(), loadFunc)
+		}(i)
+	}
+	wg.Wait()
+
+	if loadCount != 1 {
+		t.Errorf("expect the user loaded once, got %d", loadCount)
+	}
+	for i := 1; i < len(users); i++ {
+		if users[i] == users[0] || users[i].GetProperty("age") != 18 {
+			t.Errorf("expect the clone of the loaded user, got %v", users[i])
+		}
+	}
+
+	sharedUsers.load("u2", context.NewRecommendContext(), loadFunc)
+	if loadCount != 2 {
+		t.Errorf("expect the other user loaded, got %d", loadCount)
+	}
+}
+
+func TestSharedUsersLoadPanic(t *testing.T) {
+	sharedUsers := NewSharedUsers()
+
+	started := make(chan struct{})
+	panicked := make(chan interface{})
+	go func() {
+		defer func() {
+			panicked <- recover()
+		}()
+		sharedUsers.load("u1", context.NewRecommendContext(), func() *module.User {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			panic("load error")
+		})
+	}()
+
+	<-started
+	ctx := context.NewRecommendContext()
+	ctx.SetTimeout(time.Second)
+	defer ctx.Cancel()
+	user := sharedUsers.load("u1", ctx, func() *module.User {
+		return module.NewUser("u1")
+	})
+	if user == nil || user.Id != "u1" {
+		t.Errorf("expect the user loaded by the waiter, got %v", user)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expect the waiter released before the timeout, got %v", ctx.Err())
+	}
+	if r := <-panicked; r != "load error" {
+		t.Errorf("expect the panic raised to the loader, got %v", r)
+	}
+}
@@ -2,7 +2,8 @@ package service
 
 import (
 	"fmt"
-	"sync"
+	runtimedebug "runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/alibaba/pairec/v2/context"
@@ -54,12 +55,11 @@ func (r *UserRecommendService) Recommend(context *context.RecommendContext) []*m
 	}
 
 	userId := r.GetUID(context)
-	user := module.NewUserWithContext(userId, context)
 
 	//loadFeatureStart := time.Now()
 
-	// load user features
-	r.userFeatureService.LoadUserFeatures(user, context)
+	// load user features, the user is shared between the requests of a batch
+	user := loadUser(userId, context, r.userFeatureService)
 
 	//if metrics.Enabled() {
 	//metrics.LoadFeatureDurSecs.WithLabelValues(scene, expId, "before_recall").Observe(time.Since(loadFeatureStart).Seconds())
@@ -67,13 +67,9 @@ func (r *UserRecommendService) Recommend(context *context.RecommendContext) []*m
 
 	debugService := debug.NewDebugService(user, context)
 
-	var pipelineItems []*module.Item
-
-	var wg sync.WaitGroup
-	wg.Add(1)
+	pipelineCh := make(chan []*module.Item, 1)
 	go func() {
-		defer wg.Done()
-		pipelineItems = pipeline.Recommend(user, context, debugService)
+		pipelineCh <- pipeline.Recommend(user, context, debugService)
 	}()
 
 	recallStart := time.Now()
@@ -106,13 +102,16 @@ func (r *UserRecommendService) Recommend(context *context.RecommendContext) []*m
 
 	debugService.WriteFilterLog(user, items, context)
 
-	generalRankStart := time.Now()
+	// when the request deadline is exceeded, skip the rest stages until sort, the items recalled so far are returned
+	if !deadlineExceeded(context, "general_rank") {
+		generalRankStart := time.Now()
 
-	// general rank
-	items = r.generalRankService.Rank(user, items, context)
+		// general rank
+		items = r.generalRankService.Rank(user, items, context)
 
-	if metrics.Enabled() {
-		metrics.GeneralRankDurSecs.WithLabelValues(scene, expId).Observe(time.Since(generalRankStart).Seconds())
+		if metrics.Enabled() {
+			metrics.GeneralRankDurSecs.WithLabelValues(scene, expId).Observe(time.Since(generalRankStart).Seconds())
+		}
 	}
 
 	//debugService.WriteGeneralLog(user, items, context)
@@ -122,21 +121,30 @@ func (r *UserRecommendService) Recommend(context *context.RecommendContext) []*m
 	// load user or item features
 	// can load data from datasource(holo, ots, redis)
 	// after load data, use feature engine to create or modify features
-	items = r.featureService.LoadFeatures(user, items, context)
+	if !deadlineExceeded(context, "load_features") {
+		items = r.featureService.LoadFeatures(user, items, context)
+	}
 
 	//if metrics.Enabled() {
 	//metrics.LoadFeatureDurSecs.WithLabelValues(scene, expId, "before_rank").Observe(time.Since(loadFeatureStart).Seconds())
 	//}
 
-	rankStart := time.Now()
+	if !deadlineExceeded(context, "rank") {
+		rankStart := time.Now()
 
-	r.rankService.Rank(user, items, context)
+		r.rankService.Rank(user, items, context)
 
-	if metrics.Enabled() {
-		metrics.RankDurSecs.WithLabelValues(scene, expId).Observe(time.Since(rankStart).Seconds())
+		if metrics.Enabled() {
+			metrics.RankDurSecs.WithLabelValues(scene, expId).Observe(time.Since(rankStart).Seconds())
+		}
 	}
 
-	wg.Wait()
+	var pipelineItems []*module.Item
+	select {
+	case pipelineItems = <-pipelineCh:
+	case <-context.Context().Done():
+		log.Warning(fmt.Sprintf("requestId=%s\tmodule=recommend\tevent=timeout\tstage=pipeline\terror=%v", context.RecommendId, context.Err()))
+	}
 	items = r.mergePipelineItems(items, pipelineItems)
 
 	debugService.WriteRankLog(user, items, context)
@@ -178,6 +186,16 @@ func (r *UserRecommendService) Recommend(context *context.RecommendContext) []*m
 	return items
 }
 
+// deadlineExceeded reports whether the request deadline is exceeded before the stage begins
+func deadlineExceeded(context *context.RecommendContext, stage string) bool {
+	if err := context.Err(); err != nil {
+		log.Warning(fmt.Sprintf("requestId=%s\tmodule=recommend\tevent=timeout\tstage=%s\terror=%v", context.RecommendId, stage, err))
+		return true
+	}
+
+	return false
+}
+
 func (r *UserRecommendService) mergePipelineItems(items []*module.Item, pipelineItems []*module.Item) []*module.Item {
 	itemMap := make(map[module.ItemId]*module.Item, len(items))
 	for _, item := range items {
@@ -196,37 +214,80 @@ func (r *UserRecommendService) mergePipelineItems(items []*module.Item, pipeline
 }
 
 func (r *UserRecommendService) TryRecommendWithFallback(context *context.RecommendContext) []*module.Item {
+	items, _ := r.RecommendWithFallback(context)
+	return items
+}
+
+// RecommendWithFallback recommends with the fallback of the scene, the fallback serves the request when
+// the recommend is timeout or panic, and completes the items when not enough items are recommended if configured.
+// fallbackReason is empty when the fallback is not used.
+func (r *UserRecommendService) RecommendWithFallback(context *context.RecommendContext) (items []*module.Item, fallbackReason string) {
 	start := time.Now()
 	scene, _ := context.Param.GetParameter("scene").(string)
+	category, _ := context.Param.GetParameter("category").(string)
 
-	f := fallback.DefaultFallbackService().GetFallback(scene)
+	f := fallback.DefaultFallbackService().GetSceneFallback(scene, category)
 	if f == nil {
-		return r.Recommend(context)
+		return r.Recommend(context), ""
+	}
+
+	defer func() {
+		if fallbackReason != "" {
+			log.Warning(fmt.Sprintf("requestId=%s\tmodule=recommend\tevent=fallback\tcause=%s\tcost=%d", context.RecommendId, fallbackReason, utils.CostTime(start)))
+			if metrics.Enabled() {
+				metrics.FallbackTotal.WithLabelValues(scene, fallbackReason).Inc()
+			}
+		}
+	}()
+
+	// the fallback runs with its own budget, the request deadline may be exceeded when it is used
+	fallbackRecommend := func() []*module.Item {
+		fallbackContext := context.NewFallbackContext(fallback.Fallback_Recommend_Timeout)
+		defer fallbackContext.Cancel()
+		return f.Recommend(fallbackContext)
 	}
 
 	fallbackTimer := f.GetTimer()
 
-	tryResult := make(chan []*module.Item, 1)
+	type tryResult struct {
+		items []*module.Item
+		panic bool
+	}
+	tryCh := make(chan tryResult, 1)
 
 	go func() {
-		tryResult <- r.Recommend(context)
+		defer func() {
+			if err := recover(); err != nil {
+				stack := string(runtimedebug.Stack())
+				log.Error(fmt.Sprintf("requestId=%s\tmodule=recommend\terror=%v, stack=%s", context.RecommendId, err, strings.ReplaceAll(stack, "\n", "\t")))
+				tryCh <- tryResult{panic: true}
+			}
+		}()
+		tryCh <- tryResult{items: r.Recommend(context)}
 	}()
 
 	select {
 	case <-fallbackTimer.C:
-		fallbackResult := f.Recommend(context)
-		log.Warning(fmt.Sprintf("requestId=%s\tmodule=recommend\tevent=fallback\tcause=timeout\tcost=%d", context.RecommendId, utils.CostTime(start)))
-		return fallbackResult
-	case ret := <-tryResult:
+		return fallbackRecommend(), fallback.Fallback_Reason_Timeout
+	case result := <-tryCh:
 		fallbackTimer.Stop()
 
+		if result.panic {
+			return fallbackRecommend(), fallback.Fallback_Reason_Panic
+		}
+		// the request deadline is exceeded before the fallback timer, the pipeline returns with partial items
+		if context.Err() != nil && len(result.items) < context.Size {
+			return fallbackRecommend(), fallback.Fallback_Reason_Timeout
+		}
+
+		ret := result.items
 		if f.CompleteItemsIfNeed() && len(ret) < context.Size {
 			originRetMap := make(map[module.ItemId]bool)
 			for _, item := range ret {
 				originRetMap[item.Id] = true
 			}
 
-			fallbackResult := f.Recommend(context)
+			fallbackResult := fallbackRecommend()
 			for i := 0; i < len(fallbackResult); i++ {
 				fallbackItem := fallbackResult[i]
 
@@ -239,10 +300,13 @@ func (r *UserRecommendService) TryRecommendWithFallback(context *context.Recomme
 				}
 			}
 
-			log.Warning(fmt.Sprintf("requestId=%s\tmodule=recommend\tevent=fallback\tcause=itemNotEnough\tcost=%d", context.RecommendId, utils.CostTime(start)))
-			return ret
-		} else {
-			return ret
+			return ret, fallback.Fallback_Reason_ItemNotEnough
+		}
+
+		if saver, ok := f.(fallback.IResponseSaver); ok && len(ret) > 0 {
+			saver.SaveResponse(context, ret)
 		}
+
+		return ret, ""
 	}
 }
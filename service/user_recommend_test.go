@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service/fallback"
+	"github.com/alibaba/pairec/v2/service/recall"
+)
+
+type mockFallback struct {
+	timeout      time.Duration
+	completeItem bool
+}
+
+func (f *mockFallback) GetTimer() *time.Timer {
+	return time.NewTimer(f.timeout)
+}
+
+func (f *mockFallback) CompleteItemsIfNeed() bool {
+	return f.completeItem
+}
+
+func (f *mockFallback) Recommend(context *context.RecommendContext) []*module.Item {
+	// the fallback returns nothing with the context done, like the fallbacks of the datasources
+	if context.Err() != nil {
+		return nil
+	}
+	var ret []*module.Item
+	for _, id := range []string{"fallback_1", "fallback_2"} {
+		item := module.NewItem(id)
+		item.RetrieveId = "fallback"
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+func TestRecommendWithFallback(t *testing.T) {
+	recall.RegisterRecall("fallback_slow_recall", &sleepRecall{name: "fallback_slow_recall", sleep: time.Second})
+
+	Load(&recconf.RecommendConfig{
+		SceneConfs: map[string]map[string]recconf.CategoryConfig{
+			"fallback_scene": {
+				"default": {RecallNames: []string{"fallback_slow_recall"}},
+			},
+		},
+	})
+	fallback.RegisterFallback("fallback_scene", &mockFallback{timeout: 20 * time.Millisecond})
+	defer fallback.RemoveFallback("fallback_scene")
+
+	ctx := context.NewRecommendContext()
+	ctx.Size = 2
+	ctx.Config = &recconf.RecommendConfig{}
+	ctx.Param = testParam{"scene": "fallback_scene", "category": "default"}
+
+	items, reason := NewUserRecommendService().RecommendWithFallback(ctx)
+	if reason != fallback.Fallback_Reason_Timeout {
+		t.Errorf("expect fallback reason:%s, got:%s", fallback.Fallback_Reason_Timeout, reason)
+	}
+	if len(items) != 2 || items[0].RetrieveId != "fallback" {
+		t.Errorf("expect the items of fallback, got %v", items)
+	}
+
+	// the fallback serves the request whose deadline is exceeded
+	ctx = context.NewRecommendContext()
+	ctx.Size = 2
+	ctx.Config = &recconf.RecommendConfig{}
+	ctx.Param = testParam{"scene": "fallback_scene", "category": "default"}
+	ctx.SetTimeout(time.Millisecond)
+	defer ctx.Cancel()
+	items, reason = NewUserRecommendService().RecommendWithFallback(ctx)
+	if reason != fallback.Fallback_Reason_Timeout || len(items) != 2 {
+		t.Errorf("expect the items of fallback after the deadline, got %v, reason:%s", items, reason)
+	}
+
+	ctx = context.NewRecommendContext()
+	ctx.Size = 2
+	ctx.Config = &recconf.RecommendConfig{}
+	ctx.Param = testParam{"scene": "no_fallback_scene", "category": "default"}
+	if _, reason := NewUserRecommendService().RecommendWithFallback(ctx); reason != "" {
+		t.Errorf("expect no fallback, got reason:%s", reason)
+	}
+}
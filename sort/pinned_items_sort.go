@@ -0,0 +1,90 @@
+package sort
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/alibaba/pairec/v2/module"
+)
+
+// PinnedItemsSort puts the items of the request param pinned_items at their positions.
+// It is a built-in sort, applied as the final stage after the configured sorts of the scene and the pipeline.
+// The pinned item is moved when it is in the items, otherwise a new item is inserted.
+type PinnedItemsSort struct {
+}
+
+func NewPinnedItemsSort() *PinnedItemsSort {
+	return &PinnedItemsSort{}
+}
+
+func (s *PinnedItemsSort) Sort(sortData *SortData) error {
+	if _, ok := sortData.Data.([]*module.Item); !ok {
+		return errors.New("sort data type error")
+	}
+
+	return s.doSort(sortData)
+}
+
+func (s *PinnedItemsSort) doSort(sortData *SortData) error {
+	pinnedItems, _ := sortData.Context.GetParameter("pinned_items").([]module.PinnedItem)
+	if len(pinnedItems) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	pinnedItems = append([]module.PinnedItem(nil), pinnedItems...)
+	sort.SliceStable(pinnedItems, func(i, j int) bool {
+		return pinnedItems[i].Position < pinnedItems[j].Position
+	})
+
+	pinnedMap := make(map[module.ItemId]bool, len(pinnedItems))
+	for _, pinnedItem := range pinnedItems {
+		pinnedMap[module.ItemId(pinnedItem.ItemId)] = true
+	}
+
+	items := sortData.Data.([]*module.Item)
+	existItems := make(map[module.ItemId]*module.Item, len(pinnedItems))
+	result := make([]*module.Item, 0, len(items)+len(pinnedItems))
+	for _, item := range items {
+		if pinnedMap[item.Id] {
+			if _, ok := existItems[item.Id]; !ok {
+				existItems[item.Id] = item
+			}
+		} else {
+			result = append(result, item)
+		}
+	}
+
+	for _, pinnedItem := range pinnedItems {
+		itemId := module.ItemId(pinnedItem.ItemId)
+		if !pinnedMap[itemId] {
+			// the item is pinned more than once, the first position is used
+			continue
+		}
+		pinnedMap[itemId] = false
+
+		item, ok := existItems[itemId]
+		if !ok {
+			item = module.NewItem(pinnedItem.ItemId)
+			item.RetrieveId = "PinnedItem"
+		}
+
+		index := pinnedItem.Position - 1
+		if index < 0 {
+			index = 0
+		}
+		if index >= len(result) {
+			result = append(result, item)
+			continue
+		}
+
+		result = append(result, nil)
+		copy(result[index+1:], result[index:])
+		result[index] = item
+	}
+
+	sortData.Data = result
+	sortInfoLog(sortData, "PinnedItemsSort", len(result), start)
+	return nil
+}
@@ -0,0 +1,53 @@
+package sort
+
+import (
+	"testing"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+)
+
+type testParam map[string]interface{}
+
+func (p testParam) GetParameter(name string) interface{} {
+	return p[name]
+}
+
+func TestPinnedItemsSort(t *testing.T) {
+	var items []*module.Item
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		item := module.NewItem(id)
+		item.RetrieveId = "recall"
+		items = append(items, item)
+	}
+
+	ctx := context.NewRecommendContext()
+	ctx.Param = testParam{"pinned_items": []module.PinnedItem{
+		{ItemId: "promo", Position: 1},
+		{ItemId: "4", Position: 3},
+		{ItemId: "far", Position: 100},
+	}}
+	sortData := &SortData{Data: items, Context: ctx}
+	if err := NewPinnedItemsSort().Sort(sortData); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, item := range sortData.Data.([]*module.Item) {
+		ids = append(ids, string(item.Id))
+	}
+	expect := []string{"promo", "1", "4", "2", "3", "5", "far"}
+	if len(ids) != len(expect) {
+		t.Fatalf("expect %v, got %v", expect, ids)
+	}
+	for i := range expect {
+		if ids[i] != expect[i] {
+			t.Fatalf("expect %v, got %v", expect, ids)
+		}
+	}
+
+	result := sortData.Data.([]*module.Item)
+	if result[0].RetrieveId != "PinnedItem" || result[2].RetrieveId != "recall" {
+		t.Errorf("expect the new pinned item and the moved item, got %v %v", result[0], result[2])
+	}
+}
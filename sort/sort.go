@@ -2,6 +2,7 @@ package sort
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -19,9 +20,36 @@ var sortService *SortService
 
 // var sortMapping map[string]ISort
 
+// SortFactory creates the sort of the SortType from the config
+type SortFactory func(config recconf.SortConfig) ISort
+
+// sortFactories are the factories of the sort types used by RegisterSortWithConfig
+var sortFactories = make(map[string]SortFactory)
+
+// RegisterSortFactory registers the factory of the sort type, so the custom sort can be configured by SortType like the built-in ones,
+// the factory of the same type is replaced. It should be called before the config is loaded, such as in the init function.
+func RegisterSortFactory(sortType string, factory SortFactory) {
+	if factory == nil {
+		panic("SortFactory is nil, type:" + sortType)
+	}
+
+	sortFactories[sortType] = factory
+	recconf.RegisterSchemaEnum("SortConfig.SortType", sortType)
+}
+
 func init() {
 	sortService = &SortService{}
 	sortService.SortStrategies = make(map[string][]ISort, 0)
+
+	RegisterSortFactory("DPPSort", func(config recconf.SortConfig) ISort { return NewDPPSort(config.DPPConf) })
+	RegisterSortFactory("SSDSort", func(config recconf.SortConfig) ISort { return NewSSDSort(config.SSDConf) })
+	RegisterSortFactory("MultiRecallMixSort", func(config recconf.SortConfig) ISort { return NewMultiRecallMixSort(config) })
+	RegisterSortFactory("BoostScoreSort", func(config recconf.SortConfig) ISort { return NewBoostScoreSort(config) })
+	RegisterSortFactory("DiversityRuleSort", func(config recconf.SortConfig) ISort { return NewDiversityRuleSort(config) })
+	RegisterSortFactory("AlgoScoreSort", func(config recconf.SortConfig) ISort { return NewAlgoScoreSort(config) })
+	RegisterSortFactory("TrafficControlSort", func(config recconf.SortConfig) ISort { return NewTrafficControlSort(config) })
+	RegisterSortFactory("BoostScoreByWeight", func(config recconf.SortConfig) ISort { return NewBoostScoreByWeight(config) })
+	RegisterSortFactory("DistinctIdSort", func(config recconf.SortConfig) ISort { return NewDistinctIdSort(config) })
 }
 
 type SortData struct {
@@ -138,6 +166,11 @@ func Load(config *recconf.RecommendConfig) {
 
 func Sort(sortData *SortData, tag string) {
 	sortService.Sort(sortData, tag)
+
+	// the pinned items are put at the final stage, not the pre sort
+	if tag == "" {
+		NewPinnedItemsSort().Sort(sortData)
+	}
 }
 
 func RegisterSort(name string, s ISort) {
@@ -159,43 +192,54 @@ func GetSort(name string) (ISort, error) {
 	return sort, nil
 }
 
-func RegisterSortWithConfig(config *recconf.RecommendConfig) {
+// PrepareSortWithConfig builds the changed sorts of the config without registering them, commit registers the sorts built.
+// The construction errors of all the sorts are returned, and nothing should be committed when there are errors.
+func PrepareSortWithConfig(config *recconf.RecommendConfig) (commit func(), errs []*recconf.ModuleError) {
+	built := make(map[string]ISort)
+	signs := make(map[string]string)
 	for _, conf := range config.SortConfs {
+		sign, _ := json.Marshal(&conf)
 		if _, ok := sortMapping[conf.Name]; ok {
-			sign, _ := json.Marshal(&conf)
 			if utils.Md5(string(sign)) == sortSigns[conf.Name] {
 				continue
 			}
 		}
 
-		var s ISort
-		if conf.SortType == "DPPSort" {
-			s = NewDPPSort(conf.DPPConf)
-		} else if conf.SortType == "SSDSort" {
-			s = NewSSDSort(conf.SSDConf)
-		} else if conf.SortType == "MultiRecallMixSort" {
-			s = NewMultiRecallMixSort(conf)
-		} else if conf.SortType == "BoostScoreSort" {
-			s = NewBoostScoreSort(conf)
-		} else if conf.SortType == "DiversityRuleSort" {
-			s = NewDiversityRuleSort(conf)
-		} else if conf.SortType == "AlgoScoreSort" {
-			s = NewAlgoScoreSort(conf)
-		} else if conf.SortType == "TrafficControlSort" {
-			s = NewTrafficControlSort(conf)
-		} else if conf.SortType == "BoostScoreByWeight" {
-			s = NewBoostScoreByWeight(conf)
-		} else if conf.SortType == "DistinctIdSort" {
-			s = NewDistinctIdSort(conf)
+		factory, ok := sortFactories[conf.SortType]
+		if !ok {
+			errs = append(errs, recconf.NewModuleError("sort", conf.Name, conf.SortType, errors.New("sort type not registered")))
+			continue
 		}
 
-		if s == nil {
-			panic("Sort is nil, name:" + conf.Name)
+		conf := conf
+		s, err := recconf.BuildModule("sort", conf.Name, conf.SortType, func() ISort { return factory(conf) })
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
-		sign, _ := json.Marshal(&conf)
-		registerSortWithSign(conf.Name, s, utils.Md5(string(sign)))
+		built[conf.Name] = s
+		signs[conf.Name] = utils.Md5(string(sign))
+	}
+
+	commit = func() {
+		for name, s := range built {
+			registerSortWithSign(name, s, signs[name])
+		}
 	}
+
+	return commit, errs
+}
+
+// RegisterSortWithConfig registers the changed sorts of the config, it panics with the errors of all the sorts
+// when any sort fails to construct, and no sort is registered in that case
+func RegisterSortWithConfig(config *recconf.RecommendConfig) {
+	commit, errs := PrepareSortWithConfig(config)
+	if len(errs) > 0 {
+		panic(&recconf.ConfigError{Errors: errs})
+	}
+
+	commit()
 }
 func registerSortWithSign(name string, sort ISort, sign string) {
 	sortMapping[name] = sort
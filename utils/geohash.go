@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"math"
 )
 
 const (
@@ -10,6 +11,7 @@ const (
 	MIN_LATITUDE  float64 = -90
 	MAX_LONGITUDE float64 = 180
 	MIN_LONGITUDE float64 = -180
+	EARTH_RADIUS  float64 = 6371000 // 地球半径，单位米
 )
 
 var (
@@ -78,3 +80,52 @@ func GeoHashEncode(latitude, longitude float64, precision int) (string, *Box) {
 
 	return geohash.String(), b
 }
+
+// GeoHashNeighbors 返回坐标点所在区域及其周围 rings 圈区域的 geohash，所在区域排在第一位
+// rings 为 1 时返回 3x3 共 9 个区域，超出纬度范围的区域被忽略，经度超出范围时环绕
+func GeoHashNeighbors(latitude, longitude float64, precision, rings int) []string {
+	center, box := GeoHashEncode(latitude, longitude, precision)
+	if rings <= 0 {
+		return []string{center}
+	}
+
+	centerLat := (box.MinLat + box.MaxLat) / 2
+	centerLng := (box.MinLng + box.MaxLng) / 2
+	geohashes := make([]string, 0, (2*rings+1)*(2*rings+1))
+	geohashes = append(geohashes, center)
+	exists := map[string]bool{center: true}
+	for dy := -rings; dy <= rings; dy++ {
+		lat := centerLat + float64(dy)*box.Height()
+		if lat < MIN_LATITUDE || lat > MAX_LATITUDE {
+			continue
+		}
+		for dx := -rings; dx <= rings; dx++ {
+			lng := centerLng + float64(dx)*box.Width()
+			for lng < MIN_LONGITUDE {
+				lng += MAX_LONGITUDE - MIN_LONGITUDE
+			}
+			for lng > MAX_LONGITUDE {
+				lng -= MAX_LONGITUDE - MIN_LONGITUDE
+			}
+
+			geohash, _ := GeoHashEncode(lat, lng, precision)
+			if !exists[geohash] {
+				exists[geohash] = true
+				geohashes = append(geohashes, geohash)
+			}
+		}
+	}
+
+	return geohashes
+}
+
+// GeoDistance 返回两个坐标点之间的球面距离，单位米
+func GeoDistance(lat1, lng1, lat2, lng2 float64) float64 {
+	radLat1 := lat1 * math.Pi / 180
+	radLat2 := lat2 * math.Pi / 180
+	dLat := radLat2 - radLat1
+	dLng := (lng2 - lng1) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(radLat1)*math.Cos(radLat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EARTH_RADIUS * math.Asin(math.Sqrt(math.Min(1, a)))
+}
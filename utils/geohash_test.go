@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeoHashNeighbors(t *testing.T) {
+	center, box := GeoHashEncode(39.92324, 116.3906, 5)
+	if center != "wx4g0" {
+		t.Fatalf("expect geohash wx4g0, got %s", center)
+	}
+
+	geohashes := GeoHashNeighbors(39.92324, 116.3906, 5, 1)
+	if len(geohashes) != 9 || geohashes[0] != center {
+		t.Fatalf("expect 9 cells with the center first, got %v", geohashes)
+	}
+	exists := make(map[string]bool)
+	for _, geohash := range geohashes {
+		if exists[geohash] {
+			t.Errorf("geohash %s duplicated", geohash)
+		}
+		exists[geohash] = true
+	}
+	// the cell right above the center is a neighbor
+	north, _ := GeoHashEncode(box.MaxLat+box.Height()/2, (box.MinLng+box.MaxLng)/2, 5)
+	if !exists[north] {
+		t.Errorf("expect the north cell %s in %v", north, geohashes)
+	}
+
+	if geohashes := GeoHashNeighbors(39.92324, 116.3906, 5, 2); len(geohashes) != 25 {
+		t.Errorf("expect 25 cells of 2 rings, got %d", len(geohashes))
+	}
+	if geohashes := GeoHashNeighbors(39.92324, 116.3906, 5, 0); len(geohashes) != 1 {
+		t.Errorf("expect only the center of 0 ring, got %d", len(geohashes))
+	}
+	// the cells beyond the pole are ignored, and the longitude wraps around
+	if geohashes := GeoHashNeighbors(89.99, 179.99, 3, 1); len(geohashes) != 6 {
+		t.Errorf("expect 6 cells near the pole and the date line, got %v", geohashes)
+	}
+}
+
+func TestGeoDistance(t *testing.T) {
+	if d := GeoDistance(0, 0, 1, 0); math.Abs(d-111195) > 10 {
+		t.Errorf("expect 1 degree of latitude about 111195m, got %v", d)
+	}
+	if d := GeoDistance(39.9, 116.4, 39.9, 116.4); d != 0 {
+		t.Errorf("expect 0 of the same point, got %v", d)
+	}
+}
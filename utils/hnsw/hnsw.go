@@ -0,0 +1,361 @@
+package hnsw
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	Metric_IP     = "ip"
+	Metric_L2     = "l2"
+	Metric_Cosine = "cosine"
+
+	Default_M               = 16
+	Default_Ef_Construction = 200
+	Default_Ef_Search       = 64
+)
+
+// Config is the config of the hnsw index, the zero values are replaced by the defaults
+type Config struct {
+	// M is the max count of the neighbors of the node on the upper layers, the layer 0 has 2*M neighbors
+	M int
+	// EfConstruction is the size of the candidates searched when adding the node
+	EfConstruction int
+	// Metric is one of ip, l2 and cosine, ip by default
+	Metric string
+	// Seed is the seed of the random levels of the nodes, the index is reproducible with the same seed and the same order of the nodes
+	Seed int64
+}
+
+// Result is the item found by Search, Score is larger when the item is closer: the inner product of ip,
+// the cosine similarity of cosine, and the negative squared euclidean distance of l2
+type Result struct {
+	Id    string
+	Score float32
+}
+
+type node struct {
+	id      string
+	vector  []float32
+	friends [][]int32
+}
+
+// Index is the hierarchical navigable small world graph of the vectors. Add is not safe for concurrent use,
+// and Search is safe for concurrent use when the index is not modified, so the index should be built before searching.
+type Index struct {
+	m              int
+	mMax0          int
+	efConstruction int
+	levelMult      float64
+	metric         string
+	dim            int
+	nodes          []*node
+	ids            map[string]int32
+	entry          int32
+	maxLevel       int
+	rand           *rand.Rand
+}
+
+func New(config Config) (*Index, error) {
+	index := &Index{
+		m:              config.M,
+		efConstruction: config.EfConstruction,
+		metric:         config.Metric,
+		ids:            make(map[string]int32),
+		entry:          -1,
+		rand:           rand.New(rand.NewSource(config.Seed)),
+	}
+	if index.m <= 1 {
+		index.m = Default_M
+	}
+	if index.efConstruction <= 0 {
+		index.efConstruction = Default_Ef_Construction
+	}
+	if index.metric == "" {
+		index.metric = Metric_IP
+	}
+	switch index.metric {
+	case Metric_IP, Metric_L2, Metric_Cosine:
+	default:
+		return nil, fmt.Errorf("hnsw metric not support, metric:%s", index.metric)
+	}
+	index.mMax0 = 2 * index.m
+	index.levelMult = 1 / math.Log(float64(index.m))
+
+	return index, nil
+}
+
+// Len returns the count of the vectors
+func (h *Index) Len() int {
+	return len(h.nodes)
+}
+
+// Dim returns the dimension of the vectors, 0 when the index is empty
+func (h *Index) Dim() int {
+	return h.dim
+}
+
+// Add adds the vector of the id, the vectors should have the same dimension, and the duplicated id is an error
+func (h *Index) Add(id string, vector []float32) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("hnsw vector of %s is empty", id)
+	}
+	if h.dim == 0 {
+		h.dim = len(vector)
+	} else if len(vector) != h.dim {
+		return fmt.Errorf("hnsw vector dimension of %s is %d, expect %d", id, len(vector), h.dim)
+	}
+	if _, ok := h.ids[id]; ok {
+		return fmt.Errorf("hnsw id %s is duplicated", id)
+	}
+
+	vector = h.prepare(vector)
+	level := int(math.Floor(-math.Log(1-h.rand.Float64()) * h.levelMult))
+	n := &node{id: id, vector: vector, friends: make([][]int32, level+1)}
+	current := int32(len(h.nodes))
+	h.nodes = append(h.nodes, n)
+	h.ids[id] = current
+
+	if h.entry < 0 {
+		h.entry = current
+		h.maxLevel = level
+		return nil
+	}
+
+	entry := h.entry
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.greedySearch(vector, entry, l)
+	}
+
+	top := level
+	if top > h.maxLevel {
+		top = h.maxLevel
+	}
+	entries := []int32{entry}
+	for l := top; l >= 0; l-- {
+		candidates := h.searchLayer(vector, entries, h.efConstruction, l)
+		neighbors := candidates
+		if len(neighbors) > h.m {
+			neighbors = neighbors[:h.m]
+		}
+
+		n.friends[l] = make([]int32, 0, len(neighbors))
+		for _, neighbor := range neighbors {
+			n.friends[l] = append(n.friends[l], neighbor.index)
+			h.connect(neighbor.index, current, l)
+		}
+
+		entries = entries[:0]
+		for _, candidate := range candidates {
+			entries = append(entries, candidate.index)
+		}
+	}
+
+	if level > h.maxLevel {
+		h.entry = current
+		h.maxLevel = level
+	}
+
+	return nil
+}
+
+// connect adds the friend to the node on the layer, the farthest friends are dropped when the friends exceed the max count
+func (h *Index) connect(index, friend int32, layer int) {
+	n := h.nodes[index]
+	n.friends[layer] = append(n.friends[layer], friend)
+
+	maxFriends := h.m
+	if layer == 0 {
+		maxFriends = h.mMax0
+	}
+	if len(n.friends[layer]) <= maxFriends {
+		return
+	}
+
+	candidates := make([]candidate, 0, len(n.friends[layer]))
+	for _, f := range n.friends[layer] {
+		candidates = append(candidates, candidate{index: f, distance: h.distance(n.vector, h.nodes[f].vector)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	friends := make([]int32, 0, maxFriends)
+	for _, c := range candidates[:maxFriends] {
+		friends = append(friends, c.index)
+	}
+	n.friends[layer] = friends
+}
+
+// Search returns the k closest items of the vector in order, ef is the size of the candidates searched, it is at least k
+func (h *Index) Search(vector []float32, k, ef int) []Result {
+	if h.entry < 0 || k <= 0 || len(vector) != h.dim {
+		return nil
+	}
+	if ef < k {
+		ef = k
+	}
+
+	vector = h.prepare(vector)
+	entry := h.entry
+	for l := h.maxLevel; l > 0; l-- {
+		entry = h.greedySearch(vector, entry, l)
+	}
+
+	candidates := h.searchLayer(vector, []int32{entry}, ef, 0)
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+
+	results := make([]Result, 0, len(candidates))
+	for _, c := range candidates {
+		results = append(results, Result{Id: h.nodes[c.index].id, Score: -c.distance})
+	}
+
+	return results
+}
+
+// greedySearch moves to the closest friend on the layer until no friend is closer
+func (h *Index) greedySearch(vector []float32, entry int32, layer int) int32 {
+	distance := h.distance(vector, h.nodes[entry].vector)
+	for changed := true; changed; {
+		changed = false
+		for _, friend := range h.nodes[entry].friends[layer] {
+			if d := h.distance(vector, h.nodes[friend].vector); d < distance {
+				entry, distance = friend, d
+				changed = true
+			}
+		}
+	}
+
+	return entry
+}
+
+// searchLayer returns at most ef closest nodes on the layer in the order of the distance
+func (h *Index) searchLayer(vector []float32, entries []int32, ef int, layer int) []candidate {
+	visited := make(map[int32]bool, ef*4)
+	candidates := &minHeap{}
+	results := &maxHeap{}
+	for _, entry := range entries {
+		if visited[entry] {
+			continue
+		}
+		visited[entry] = true
+		c := candidate{index: entry, distance: h.distance(vector, h.nodes[entry].vector)}
+		heap.Push(candidates, c)
+		heap.Push(results, c)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(candidate)
+		if results.Len() >= ef && current.distance > (*results)[0].distance {
+			break
+		}
+
+		friends := h.nodes[current.index].friends
+		if layer >= len(friends) {
+			continue
+		}
+		for _, friend := range friends[layer] {
+			if visited[friend] {
+				continue
+			}
+			visited[friend] = true
+
+			d := h.distance(vector, h.nodes[friend].vector)
+			if results.Len() < ef || d < (*results)[0].distance {
+				c := candidate{index: friend, distance: d}
+				heap.Push(candidates, c)
+				heap.Push(results, c)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	ret := make([]candidate, results.Len())
+	for i := len(ret) - 1; i >= 0; i-- {
+		ret[i] = heap.Pop(results).(candidate)
+	}
+
+	return ret
+}
+
+// prepare returns the normalized copy of the vector for cosine, the vector itself for the others
+func (h *Index) prepare(vector []float32) []float32 {
+	if h.metric != Metric_Cosine {
+		return vector
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	norm = math.Sqrt(norm)
+
+	normalized := make([]float32, len(vector))
+	if norm == 0 {
+		return normalized
+	}
+	for i, v := range vector {
+		normalized[i] = float32(float64(v) / norm)
+	}
+
+	return normalized
+}
+
+// distance is smaller when the vectors are closer
+func (h *Index) distance(a, b []float32) float32 {
+	if h.metric == Metric_L2 {
+		var sum float32
+		for i := range a {
+			d := a[i] - b[i]
+			sum += d * d
+		}
+		return sum
+	}
+
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return -dot
+}
+
+type candidate struct {
+	index    int32
+	distance float32
+}
+
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].distance < h[j].distance }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,97 @@
+package hnsw
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func bruteForce(index *Index, vector []float32, k int) []string {
+	vector = index.prepare(vector)
+	type scored struct {
+		id       string
+		distance float32
+	}
+	all := make([]scored, 0, index.Len())
+	for _, n := range index.nodes {
+		all = append(all, scored{id: n.id, distance: index.distance(vector, n.vector)})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].distance < all[j].distance })
+
+	ids := make([]string, 0, k)
+	for _, s := range all[:k] {
+		ids = append(ids, s.id)
+	}
+	return ids
+}
+
+func TestIndexRecall(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomVector := func() []float32 {
+		vector := make([]float32, 16)
+		for i := range vector {
+			vector[i] = r.Float32()*2 - 1
+		}
+		return vector
+	}
+
+	for _, metric := range []string{Metric_IP, Metric_L2, Metric_Cosine} {
+		index, err := New(Config{Metric: metric, M: 8, EfConstruction: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2000; i++ {
+			if err := index.Add(strconv.Itoa(i), randomVector()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var found, total int
+		for q := 0; q < 50; q++ {
+			query := randomVector()
+			expect := make(map[string]bool)
+			for _, id := range bruteForce(index, query, 10) {
+				expect[id] = true
+			}
+
+			results := index.Search(query, 10, 100)
+			for i, result := range results {
+				if expect[result.Id] {
+					found++
+				}
+				if i > 0 && result.Score > results[i-1].Score {
+					t.Fatalf("expect the results in the order of the score, got %v", results)
+				}
+			}
+			total += len(expect)
+		}
+
+		if recall := float64(found) / float64(total); recall < 0.9 {
+			t.Errorf("expect the recall of %s at least 0.9, got %v", metric, recall)
+		}
+	}
+}
+
+func TestIndexError(t *testing.T) {
+	if _, err := New(Config{Metric: "unknown"}); err == nil {
+		t.Error("expect the metric error")
+	}
+
+	index, _ := New(Config{})
+	if results := index.Search([]float32{1, 0}, 10, 10); len(results) != 0 {
+		t.Errorf("expect no result of the empty index, got %v", results)
+	}
+	if err := index.Add("1", []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Add("1", []float32{0, 1}); err == nil {
+		t.Error("expect the duplicated id error")
+	}
+	if err := index.Add("2", []float32{0, 1, 0}); err == nil {
+		t.Error("expect the dimension error")
+	}
+	if results := index.Search([]float32{1, 0}, 10, 10); len(results) != 1 || results[0].Id != "1" || results[0].Score != 1 {
+		t.Errorf("expect the only item, got %v", results)
+	}
+}
@@ -3,15 +3,14 @@ package synclog
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"testing"
 	"time"
 
 	"fortio.org/assert"
 )
 
-func createSyncLog() (*SyncLog, error) {
-	syncLog := NewSyncLog("./tmp", func(b []byte) error {
+func createSyncLog(dir string) (*SyncLog, error) {
+	syncLog := NewSyncLog(dir, func(b []byte) error {
 		fmt.Println(string(b), len(b))
 		return nil
 	})
@@ -20,8 +19,7 @@ func createSyncLog() (*SyncLog, error) {
 	return syncLog, err
 }
 func TestOpenSyncLog(t *testing.T) {
-	os.RemoveAll("./tmp")
-	syncLog, err := createSyncLog()
+	syncLog, err := createSyncLog(t.TempDir())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -34,7 +32,7 @@ func TestOpenSyncLog(t *testing.T) {
 }
 
 func TestWriteSyncLog(t *testing.T) {
-	syncLog, err := createSyncLog()
+	syncLog, err := createSyncLog(t.TempDir())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,7 +55,7 @@ func TestWriteSyncLog(t *testing.T) {
 
 func TestWriteManySyncLog(t *testing.T) {
 	size := 0
-	syncLog := NewSyncLog("./tmp", func(b []byte) error {
+	syncLog := NewSyncLog(t.TempDir(), func(b []byte) error {
 		//fmt.Println(string(b), len(b))
 		size++
 		return nil
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseVector parses the vector of the float values separated by the comma or the space, the brackets are trimmed,
+// and the values in the index:value format are supported, such as the vectors stored in redis or the array text of hologres
+func ParseVector(value string) ([]float32, error) {
+	value = strings.Trim(strings.TrimSpace(value), "[]{}")
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	vector := make([]float32, 0, len(fields))
+	for _, field := range fields {
+		if i := strings.LastIndex(field, ":"); i >= 0 {
+			field = field[i+1:]
+		}
+		f, err := strconv.ParseFloat(field, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector value:%s", field)
+		}
+		vector = append(vector, float32(f))
+	}
+
+	return vector, nil
+}
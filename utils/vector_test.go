@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestParseVector(t *testing.T) {
+	testcases := map[string]int{
+		"0.1,0.2,0.3": 3,
+		"[0.1, 0.2]":  2,
+		"{0.1,0.2}":   2,
+		"1:0.1 2:0.2": 2,
+		"":            0,
+	}
+
+	for value, length := range testcases {
+		vector, err := ParseVector(value)
+		if err != nil || len(vector) != length {
+			t.Errorf("expect the vector length %d of %s, got %v %v", length, value, vector, err)
+		}
+	}
+
+	if _, err := ParseVector("0.1,invalid"); err == nil {
+		t.Error("expect the invalid value error")
+	}
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/recconf"
+	"github.com/alibaba/pairec/v2/service"
+	"github.com/alibaba/pairec/v2/utils"
+)
+
+const (
+	Default_Batch_Parallelism    int = 8
+	Default_Batch_Max_Batch_Size int = 100
+)
+
+type BatchRecommendResponse struct {
+	Response
+	Size    int                  `json:"size"`
+	Results []*RecommendResponse `json:"results"`
+}
+
+func (r *BatchRecommendResponse) ToString() string {
+	j, _ := json.Marshal(r)
+	return string(j)
+}
+
+// BatchRecommendController recommends for the array of RecommendParam in one call,
+// the requests run concurrently and the results are returned in the same order of the params.
+type BatchRecommendController struct {
+	Controller
+	params []*RecommendParam
+}
+
+func (c *BatchRecommendController) Process(w http.ResponseWriter, r *http.Request) {
+	c.Start = time.Now()
+	var err error
+	c.RequestBody, err = io.ReadAll(r.Body)
+	if err != nil {
+		c.SendError(w, ERROR_PARAMETER_CODE, "read parammeter error")
+		return
+	}
+	if len(c.RequestBody) == 0 {
+		c.SendError(w, ERROR_PARAMETER_CODE, "request body empty")
+		return
+	}
+	c.RequestId = utils.UUID()
+	c.LogRequestBeginWithSize(r, 4096)
+	if err := c.CheckParameter(); err != nil {
+		c.SendError(w, ERROR_PARAMETER_CODE, err.Error())
+		return
+	}
+	c.doProcess(w, r)
+	c.End = time.Now()
+	c.LogRequestEnd(r)
+}
+
+func (c *BatchRecommendController) CheckParameter() error {
+	if err := json.Unmarshal(c.RequestBody, &c.params); err != nil {
+		return err
+	}
+
+	if len(c.params) == 0 {
+		return fmt.Errorf("params not empty")
+	}
+
+	maxBatchSize := recconf.Config.BatchRecommendConf.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = Default_Batch_Max_Batch_Size
+	}
+	if len(c.params) > maxBatchSize {
+		return fmt.Errorf("params size exceed the max batch size:%d", maxBatchSize)
+	}
+
+	return nil
+}
+
+func (c *BatchRecommendController) doProcess(w http.ResponseWriter, r *http.Request) {
+	parallelism := recconf.Config.BatchRecommendConf.Parallelism
+	if parallelism <= 0 {
+		parallelism = Default_Batch_Parallelism
+	}
+
+	// the user features are loaded once for the requests of the same user
+	sharedUsers := service.NewSharedUsers()
+
+	results := make([]*RecommendResponse, len(c.params))
+	tokens := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, param := range c.params {
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(index int, param *RecommendParam) {
+			defer wg.Done()
+			defer func() { <-tokens }()
+			results[index] = c.recommend(r.Context(), fmt.Sprintf("%s_%d", c.RequestId, index), param, sharedUsers)
+		}(i, param)
+	}
+	wg.Wait()
+
+	response := BatchRecommendResponse{
+		Size:    len(results),
+		Results: results,
+		Response: Response{
+			RequestId: c.RequestId,
+			Code:      200,
+			Message:   "success",
+		},
+	}
+	io.WriteString(w, response.ToString())
+}
+
+// recommend runs one request of the batch, the error of the request is returned in its own response
+func (c *BatchRecommendController) recommend(ctx gocontext.Context, requestId string, param *RecommendParam, sharedUsers *service.SharedUsers) (response *RecommendResponse) {
+	if param == nil {
+		param = &RecommendParam{}
+	}
+	if err := checkRecommendParam(param); err != nil {
+		return &RecommendResponse{
+			Items:    make([]*ItemData, 0),
+			Response: Response{RequestId: requestId, Code: ERROR_PARAMETER_CODE, Message: err.Error()},
+		}
+	}
+
+	defer func() {
+		if err := recover(); err != nil {
+			stack := string(debug.Stack())
+			log.Error(fmt.Sprintf("requestId=%s\tmodule=BatchRecommend\terror=%v, stack=%s", requestId, err, strings.ReplaceAll(stack, "\n", "\t")))
+			response = &RecommendResponse{
+				Items:    make([]*ItemData, 0),
+				Response: Response{RequestId: requestId, Code: SERVER_ERROR_CODE, Message: CODE_MAPS[SERVER_ERROR_CODE]},
+			}
+		}
+	}()
+
+	controller := &RecommendController{param: *param, requestCtx: ctx}
+	controller.RequestId = requestId
+	controller.makeRecommendContext()
+	defer controller.context.Cancel()
+	service.WithSharedUsers(controller.context, sharedUsers)
+
+	return controller.recommendResponse()
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/alibaba/pairec/v2/module"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/service"
+	"github.com/alibaba/pairec/v2/service/recall"
 	"github.com/alibaba/pairec/v2/utils"
 	"github.com/aliyun/aliyun-pairec-config-go-sdk/v2/model"
 )
@@ -93,28 +94,33 @@ func (r *CallBackController) CheckParameter() error {
 		return err
 	}
 
-	if len(r.param.Uid) == 0 {
+	return checkCallBackParam(&r.param)
+}
+
+// checkCallBackParam checks the param and merges the complex type features into the features
+func checkCallBackParam(param *CallBackParam) error {
+	if len(param.Uid) == 0 {
 		return errors.New("uid not empty")
 	}
 
-	if len(r.param.RequestId) == 0 {
+	if len(param.RequestId) == 0 {
 		return errors.New("request_id not empty")
 	}
 
-	if len(r.param.SceneId) == 0 {
+	if len(param.SceneId) == 0 {
 		return errors.New("scene_id not empty")
 	}
 
-	if len(r.param.ItemList) == 0 {
+	if len(param.ItemList) == 0 {
 		return errors.New("recommend item list not empty")
 	}
 
-	if len(r.param.ComplexTypeFeatures.FeaturesMap) > 0 {
-		if r.param.Features == nil {
-			r.param.Features = make(map[string]interface{})
+	if len(param.ComplexTypeFeatures.FeaturesMap) > 0 {
+		if param.Features == nil {
+			param.Features = make(map[string]interface{})
 		}
-		for k, v := range r.param.ComplexTypeFeatures.FeaturesMap {
-			r.param.Features[k] = v
+		for k, v := range param.ComplexTypeFeatures.FeaturesMap {
+			param.Features[k] = v
 		}
 	}
 	return nil
@@ -154,6 +160,9 @@ func (c *CallBackController) doCallbackLog() {
 			item.AddProperty(k, v)
 		}
 	}
+	// feed the online i2i statistics
+	recall.RecordCallbackItems(c.param.SceneId, c.param.Uid, items)
+
 	// CallBackProcessFunc process
 	if f, ok := callBackProcessFuncMap[c.param.SceneId]; ok {
 		f(user, items, c.context)
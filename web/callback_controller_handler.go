@@ -8,6 +8,8 @@ var once sync.Once
 type CallBackControllerHandler struct {
 	controllerCh chan *CallBackController
 	poolSize     int
+	// wg counts the callbacks sent and not processed
+	wg sync.WaitGroup
 }
 
 func NewCallBackControllerHandler() *CallBackControllerHandler {
@@ -27,6 +29,7 @@ func (h *CallBackControllerHandler) start() {
 				select {
 				case controller := <-h.controllerCh:
 					controller.doCallbackLog()
+					h.wg.Done()
 				}
 			}
 
@@ -41,5 +44,13 @@ func Send(controller *CallBackController) {
 		})
 	}
 
+	callBackControllerHandler.wg.Add(1)
 	callBackControllerHandler.controllerCh <- controller
 }
+
+// WaitCallBack waits for the callbacks sent to be processed, it is used on shutdown after the requests are drained
+func WaitCallBack() {
+	if callBackControllerHandler != nil {
+		callBackControllerHandler.wg.Wait()
+	}
+}
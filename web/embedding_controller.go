@@ -119,11 +119,15 @@ func (r *EmbeddingController) decodeRequestBody() error {
 	return nil
 }
 func (r *EmbeddingController) CheckParameter() error {
+	return checkEmbeddingParam(&r.param)
+}
 
-	if r.param.Uid == "" && r.param.ItemId == "" {
+// checkEmbeddingParam checks the param
+func checkEmbeddingParam(param *EmbeddingParam) error {
+	if param.Uid == "" && param.ItemId == "" {
 		return errors.New("uid or item_id not empty")
 	}
-	if r.param.SceneId == "" {
+	if param.SceneId == "" {
 		return errors.New("scene_id not empty")
 	}
 
@@ -131,6 +135,12 @@ func (r *EmbeddingController) CheckParameter() error {
 }
 func (c *EmbeddingController) doProcess(w http.ResponseWriter, r *http.Request) {
 	c.makeRecommendContext()
+	response := c.embeddingResponse()
+	w.Write(response.ToBytes())
+}
+
+// embeddingResponse gets the embedding with the context made by makeRecommendContext
+func (c *EmbeddingController) embeddingResponse() *EmbeddingResponse {
 	embeddingService := service.NewEmbeddingService()
 	embeddings, err := embeddingService.Recommend(c.context)
 
@@ -138,18 +148,16 @@ func (c *EmbeddingController) doProcess(w http.ResponseWriter, r *http.Request)
 		fmt.Printf("requestId=%s\tembeddings=%v\terror=%v\n", c.RequestId, embeddings, err)
 	}
 	if err != nil {
-		response := EmbeddingResponse{
+		return &EmbeddingResponse{
 			Response: Response{
 				RequestId: c.RequestId,
 				Code:      500,
 				Message:   err.Error(),
 			},
 		}
-		w.Write(response.ToBytes())
-		return
 	}
 
-	response := EmbeddingResponse{
+	return &EmbeddingResponse{
 		Embedding: embeddings,
 		Response: Response{
 			RequestId: c.RequestId,
@@ -157,7 +165,6 @@ func (c *EmbeddingController) doProcess(w http.ResponseWriter, r *http.Request)
 			Message:   "success",
 		},
 	}
-	w.Write(response.ToBytes())
 }
 
 func (c *EmbeddingController) makeRecommendContext() {
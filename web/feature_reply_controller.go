@@ -110,39 +110,52 @@ func (r *FeatureReplyController) CheckParameter() error {
 		return err
 	}
 
-	if len(r.param.FeatureData.UserId) == 0 {
+	if err := checkFeatureReplyParam(&r.param); err != nil {
+		return err
+	}
+
+	r.RequestId = r.param.FeatureData.RequestId
+	return nil
+}
+
+// checkFeatureReplyParam checks the param
+func checkFeatureReplyParam(param *FeatureReplyParam) error {
+	if len(param.FeatureData.UserId) == 0 {
 		return errors.New("user_id not empty")
 	}
-	if len(r.param.FeatureData.ItemIds) == 0 {
+	if len(param.FeatureData.ItemIds) == 0 {
 		return errors.New("item_ids not empty")
 	}
-	if len(r.param.FeatureData.ItemIds) != len(r.param.FeatureData.ItemFeatures) {
+	if len(param.FeatureData.ItemIds) != len(param.FeatureData.ItemFeatures) {
 		return errors.New("item_ids length is not equal item_features")
 	}
 
-	if r.param.FeatureData.RequestId == "" {
+	if param.FeatureData.RequestId == "" {
 		return errors.New("request_id not empty")
 	}
 
-	r.RequestId = r.param.FeatureData.RequestId
 	return nil
 }
 func (c *FeatureReplyController) doProcess(w http.ResponseWriter, r *http.Request) {
 	c.makeRecommendContext()
+	response := c.featureReplyResponse()
+	io.WriteString(w, response.ToString())
+}
+
+// featureReplyResponse replies the features with the context made by makeRecommendContext
+func (c *FeatureReplyController) featureReplyResponse() *FeatureReplyResponse {
 	featureReplyService := service.NewFeatureReplyService()
 
 	featureReplyService.FeatureReply(c.param.FeatureData.UserFeatures, c.param.FeatureData.ItemFeatures,
 		c.param.FeatureData.ItemIds, c.context)
 
-	response := FeatureReplyResponse{
+	return &FeatureReplyResponse{
 		Response: Response{
 			RequestId: c.RequestId,
 			Code:      200,
 			Message:   "success",
 		},
 	}
-
-	io.WriteString(w, response.ToString())
 }
 
 func (c *FeatureReplyController) makeRecommendContext() {
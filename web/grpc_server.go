@@ -0,0 +1,322 @@
+package web
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/utils"
+	"github.com/alibaba/pairec/v2/web/pairec_web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Grpc_Request_Id_Key is the metadata key of the request id, it is read from the request and returned in the response header
+const Grpc_Request_Id_Key = "request_id"
+
+type grpcRequestIdContextKey struct{}
+
+// GrpcRequestId returns the request id set by GrpcRequestIdInterceptor
+func GrpcRequestId(ctx gocontext.Context) string {
+	if requestId, ok := ctx.Value(grpcRequestIdContextKey{}).(string); ok {
+		return requestId
+	}
+
+	return utils.UUID()
+}
+
+// GrpcRequestIdInterceptor sets the request id of the request and logs the begin and the end of the request with the method and the request size.
+// The request id is read from the metadata request_id, then the request_id field of the request, otherwise a new one is generated.
+func GrpcRequestIdInterceptor(ctx gocontext.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	var requestId string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Grpc_Request_Id_Key); len(values) > 0 {
+			requestId = values[0]
+		}
+	}
+	if requestId == "" {
+		if r, ok := req.(interface{ GetRequestId() string }); ok {
+			requestId = r.GetRequestId()
+		}
+	}
+	if requestId == "" {
+		requestId = utils.UUID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(Grpc_Request_Id_Key, requestId))
+
+	var address string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		address = p.Addr.String()
+	}
+	// only the size of the request is logged, the body is not encoded again and its credentials are not logged
+	var size int
+	if m, ok := req.(proto.Message); ok {
+		size = proto.Size(m)
+	}
+	log.Info(fmt.Sprintf("requestId=%s\tevent=begin\turi=%s\taddress=%s\tsize=%d", requestId, info.FullMethod, address, size))
+
+	resp, err := handler(gocontext.WithValue(ctx, grpcRequestIdContextKey{}, requestId), req)
+
+	log.Info(fmt.Sprintf("requestId=%s\tevent=end\turi=%s\tcost=%d", requestId, info.FullMethod, utils.CostTime(start)))
+	return resp, err
+}
+
+// GrpcRecoveryInterceptor recovers the panic of the request and returns the internal error
+func GrpcRecoveryInterceptor(ctx gocontext.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			stack := string(debug.Stack())
+			log.Error(fmt.Sprintf("requestId=%s\turi=%s\terror=%v, stack=%s", GrpcRequestId(ctx), info.FullMethod, e, strings.ReplaceAll(stack, "\n", "\t")))
+			err = status.Error(codes.Internal, CODE_MAPS[SERVER_ERROR_CODE])
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// GrpcServer implements the pairec_web.PairecServiceServer, the requests are processed by the same services as the http controllers
+type GrpcServer struct {
+	pairec_web.UnimplementedPairecServiceServer
+}
+
+func NewGrpcServer() *GrpcServer {
+	return &GrpcServer{}
+}
+
+func (s *GrpcServer) Recommend(ctx gocontext.Context, req *pairec_web.RecommendRequest) (*pairec_web.RecommendReply, error) {
+	param := RecommendParam{
+		SceneId:      req.GetSceneId(),
+		Category:     req.GetCategory(),
+		Uid:          req.GetUid(),
+		Size:         int(req.GetSize()),
+		Debug:        req.GetDebug(),
+		Timeout:      int(req.GetTimeout()),
+		Page:         int(req.GetPage()),
+		SessionId:    req.GetSessionId(),
+		ExcludeItems: req.GetExcludeItems(),
+		Features:     structToMap(req.GetFeatures()),
+	}
+	for _, pinnedItem := range req.GetPinnedItems() {
+		param.PinnedItems = append(param.PinnedItems, module.PinnedItem{ItemId: pinnedItem.GetItemId(), Position: int(pinnedItem.GetPosition())})
+	}
+
+	requestId := GrpcRequestId(ctx)
+	if err := checkRecommendParam(&param); err != nil {
+		return &pairec_web.RecommendReply{Code: int32(ERROR_PARAMETER_CODE), Msg: err.Error(), RequestId: requestId}, nil
+	}
+
+	// the deadline of the grpc request is used as the timeout if the timeout is not set
+	if deadline, ok := ctx.Deadline(); ok && param.Timeout <= 0 {
+		param.Timeout = int(time.Until(deadline).Milliseconds())
+	}
+
+	controller := &RecommendController{param: param, requestCtx: ctx}
+	controller.RequestId = requestId
+	controller.makeRecommendContext()
+	defer controller.context.Cancel()
+
+	response := controller.recommendResponse()
+	reply := &pairec_web.RecommendReply{
+		Code:           int32(response.Code),
+		Msg:            response.Message,
+		RequestId:      response.RequestId,
+		Size:           int32(response.Size),
+		Fallback:       response.Fallback,
+		FallbackReason: response.FallbackReason,
+		Items:          make([]*pairec_web.ItemData, 0, len(response.Items)),
+	}
+	for _, item := range response.Items {
+		var score float64
+		if item.Score != nil {
+			score = *item.Score
+		}
+		reply.Items = append(reply.Items, &pairec_web.ItemData{
+			ItemId:       item.ItemId,
+			ItemType:     item.ItemType,
+			RetrieveId:   item.RetrieveId,
+			Score:        score,
+			RecallScores: item.RecallScores,
+			AlgoScores:   item.AlgoScores,
+			Properties:   mapToStruct(item.Properties),
+		})
+	}
+
+	return reply, nil
+}
+
+func (s *GrpcServer) Recall(ctx gocontext.Context, req *pairec_web.RecallRequest) (*pairec_web.RecallReply, error) {
+	param := UserRecallParam{
+		SceneId:  req.GetSceneId(),
+		Category: req.GetCategory(),
+		Uid:      req.GetUid(),
+		Size:     int(req.GetSize()),
+	}
+
+	requestId := GrpcRequestId(ctx)
+	if err := checkUserRecallParam(&param); err != nil {
+		return &pairec_web.RecallReply{Code: int32(ERROR_PARAMETER_CODE), Msg: err.Error(), RequestId: requestId}, nil
+	}
+
+	controller := &UserRecallController{param: param}
+	controller.RequestId = requestId
+	controller.makeRecommendContext()
+
+	response := controller.recallResponse()
+	reply := &pairec_web.RecallReply{
+		Code:      int32(response.Code),
+		Msg:       response.Message,
+		RequestId: response.RequestId,
+		Size:      int32(response.Size),
+		Items:     make([]*pairec_web.RecallItemData, 0, len(response.Items)),
+	}
+	for _, item := range response.Items {
+		reply.Items = append(reply.Items, &pairec_web.RecallItemData{
+			ItemId:     item.ItemId,
+			RetrieveId: item.RetrieveId,
+		})
+	}
+
+	return reply, nil
+}
+
+func (s *GrpcServer) CallBack(ctx gocontext.Context, req *pairec_web.CallBackRequest) (*pairec_web.CallBackReply, error) {
+	param := CallBackParam{
+		SceneId:     req.GetSceneId(),
+		RequestId:   req.GetRequestId(),
+		Uid:         req.GetUid(),
+		Features:    structToMap(req.GetFeatures()),
+		RequestInfo: structToMap(req.GetRequestInfo()),
+		Debug:       req.GetDebug(),
+	}
+	for _, item := range req.GetItemList() {
+		param.ItemList = append(param.ItemList, item.AsMap())
+	}
+
+	requestId := GrpcRequestId(ctx)
+	if err := checkCallBackParam(&param); err != nil {
+		return &pairec_web.CallBackReply{Code: int32(ERROR_PARAMETER_CODE), Msg: err.Error(), RequestId: requestId}, nil
+	}
+
+	controller := &CallBackController{param: param}
+	controller.RequestId = requestId
+	// write log async
+	Send(controller)
+
+	return &pairec_web.CallBackReply{Code: int32(SUCCESS_CODE), Msg: "success", RequestId: requestId}, nil
+}
+
+func (s *GrpcServer) Embedding(ctx gocontext.Context, req *pairec_web.EmbeddingRequest) (*pairec_web.EmbeddingReply, error) {
+	param := EmbeddingParam{
+		RequestId:    req.GetRequestId(),
+		SceneId:      req.GetSceneId(),
+		Uid:          req.GetUid(),
+		Debug:        req.GetDebug(),
+		UserFeatures: structToMap(req.GetUserFeatures()),
+		ItemId:       req.GetItemId(),
+		ItemFeatures: structToMap(req.GetItemFeatures()),
+	}
+
+	requestId := GrpcRequestId(ctx)
+	if err := checkEmbeddingParam(&param); err != nil {
+		return &pairec_web.EmbeddingReply{Code: int32(ERROR_PARAMETER_CODE), Msg: err.Error(), RequestId: requestId}, nil
+	}
+
+	controller := &EmbeddingController{param: param}
+	controller.RequestId = requestId
+	controller.makeRecommendContext()
+
+	response := controller.embeddingResponse()
+	return &pairec_web.EmbeddingReply{
+		Code:      int32(response.Code),
+		Msg:       response.Message,
+		RequestId: response.RequestId,
+		Embedding: response.Embedding,
+	}, nil
+}
+
+func (s *GrpcServer) FeatureReply(ctx gocontext.Context, req *pairec_web.FeatureReplyRequest) (*pairec_web.FeatureReplyReply, error) {
+	datasource := req.GetDatasource()
+	data := req.GetItems()
+	param := FeatureReplyParam{
+		DataSource: DataSourceInfo{
+			Type:       datasource.GetType(),
+			AccessId:   datasource.GetAccessId(),
+			AccessKey:  datasource.GetAccessKey(),
+			Endpoint:   datasource.GetEndpoint(),
+			Project:    datasource.GetProject(),
+			Topic:      datasource.GetTopic(),
+			VpcAddress: datasource.GetVpcAddress(),
+			Token:      datasource.GetToken(),
+			JobId:      int(datasource.GetJobId()),
+		},
+		FeatureData: FeatureReplyData{
+			Scene:        data.GetScene(),
+			RequestId:    data.GetRequestId(),
+			UserId:       data.GetUserId(),
+			UserFeatures: data.GetUserFeatures(),
+			ItemIds:      data.GetItemIds(),
+			ItemFeatures: data.GetItemFeatures(),
+		},
+	}
+
+	if err := checkFeatureReplyParam(&param); err != nil {
+		return &pairec_web.FeatureReplyReply{Code: int32(ERROR_PARAMETER_CODE), Msg: err.Error(), RequestId: GrpcRequestId(ctx)}, nil
+	}
+
+	controller := &FeatureReplyController{param: param}
+	controller.RequestId = param.FeatureData.RequestId
+	controller.makeRecommendContext()
+
+	response := controller.featureReplyResponse()
+	return &pairec_web.FeatureReplyReply{
+		Code:      int32(response.Code),
+		Msg:       response.Message,
+		RequestId: response.RequestId,
+	}, nil
+}
+
+// structToMap converts the struct to the map as the json decoded params, the nil struct returns nil
+func structToMap(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+
+	return s.AsMap()
+}
+
+// mapToStruct converts the properties to the struct, the values not supported by structpb are converted through json
+func mapToStruct(m map[string]interface{}) *structpb.Struct {
+	if len(m) == 0 {
+		return nil
+	}
+
+	if s, err := structpb.NewStruct(m); err == nil {
+		return s
+	}
+
+	s := &structpb.Struct{}
+	data, err := json.Marshal(m)
+	if err != nil {
+		log.Error(fmt.Sprintf("event=mapToStruct\terror=%v", err))
+		return nil
+	}
+	if err := protojson.Unmarshal(data, s); err != nil {
+		log.Error(fmt.Sprintf("event=mapToStruct\terror=%v", err))
+		return nil
+	}
+
+	return s
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/web/pairec_web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcInterceptors(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pairec.web.PairecService/Recommend"}
+
+	var requestId string
+	handler := func(ctx gocontext.Context, req interface{}) (interface{}, error) {
+		requestId = GrpcRequestId(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(gocontext.Background(), metadata.Pairs(Grpc_Request_Id_Key, "request1"))
+	GrpcRequestIdInterceptor(ctx, &pairec_web.RecommendRequest{}, info, handler)
+	if requestId != "request1" {
+		t.Errorf("expect the request id of the metadata, got %s", requestId)
+	}
+
+	GrpcRequestIdInterceptor(gocontext.Background(), &pairec_web.CallBackRequest{RequestId: "request2"}, info, handler)
+	if requestId != "request2" {
+		t.Errorf("expect the request id of the request, got %s", requestId)
+	}
+
+	GrpcRequestIdInterceptor(gocontext.Background(), &pairec_web.RecommendRequest{}, info, handler)
+	if requestId == "" {
+		t.Error("expect the request id generated")
+	}
+
+	_, err := GrpcRecoveryInterceptor(gocontext.Background(), nil, info, func(ctx gocontext.Context, req interface{}) (interface{}, error) {
+		panic("test panic")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expect the internal error, got %v", err)
+	}
+}
+
+func TestGrpcServerCheckParameter(t *testing.T) {
+	server := NewGrpcServer()
+
+	reply, err := server.Recommend(gocontext.Background(), &pairec_web.RecommendRequest{SceneId: "home"})
+	if err != nil || reply.Code != int32(ERROR_PARAMETER_CODE) || reply.Msg != "uid not empty" {
+		t.Errorf("expect the parameter error, got %v, %v", reply, err)
+	}
+
+	callbackReply, err := server.CallBack(gocontext.Background(), &pairec_web.CallBackRequest{Uid: "1", RequestId: "request1", SceneId: "home"})
+	if err != nil || callbackReply.Code != int32(ERROR_PARAMETER_CODE) {
+		t.Errorf("expect the parameter error, got %v, %v", callbackReply, err)
+	}
+}
+
+func TestMapToStruct(t *testing.T) {
+	if mapToStruct(nil) != nil {
+		t.Error("expect nil struct of the empty map")
+	}
+
+	// []string is not supported by structpb, it is converted through json
+	s := mapToStruct(map[string]interface{}{"title": "hello", "price": 10, "tags": []string{"a", "b"}})
+	m := s.AsMap()
+	if m["title"] != "hello" || m["price"] != float64(10) {
+		t.Errorf("expect the properties converted, got %v", m)
+	}
+	if tags, ok := m["tags"].([]interface{}); !ok || len(tags) != 2 || tags[0] != "a" {
+		t.Errorf("expect the tags converted, got %v", m["tags"])
+	}
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	gocontext "context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +11,13 @@ import (
 
 	"github.com/alibaba/pairec/v2/abtest"
 	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/filter"
 	"github.com/alibaba/pairec/v2/log"
+	"github.com/alibaba/pairec/v2/module"
 	"github.com/alibaba/pairec/v2/recconf"
 	"github.com/alibaba/pairec/v2/service"
+	"github.com/alibaba/pairec/v2/service/pagination"
+	"github.com/alibaba/pairec/v2/sort"
 	"github.com/alibaba/pairec/v2/utils"
 	"github.com/aliyun/aliyun-pairec-config-go-sdk/v2/model"
 )
@@ -22,12 +27,17 @@ const (
 )
 
 type RecommendParam struct {
-	SceneId  string                 `json:"scene_id"`
-	Category string                 `json:"category"`
-	Uid      string                 `json:"uid"`  // user id
-	Size     int                    `json:"size"` // get recommend items size
-	Debug    bool                   `json:"debug"`
-	Features map[string]interface{} `json:"features"`
+	SceneId      string                 `json:"scene_id"`
+	Category     string                 `json:"category"`
+	Uid          string                 `json:"uid"`  // user id
+	Size         int                    `json:"size"` // get recommend items size
+	Debug        bool                   `json:"debug"`
+	Timeout      int                    `json:"timeout"`       // request timeout in milliseconds
+	Page         int                    `json:"page"`          // page of the session, starts from 1
+	SessionId    string                 `json:"session_id"`    // the ranked list of the session is cached for the later pages
+	ExcludeItems []string               `json:"exclude_items"` // items must not appear in the response
+	PinnedItems  []module.PinnedItem    `json:"pinned_items"`  // items must appear at the positions, position starts from 1
+	Features     map[string]interface{} `json:"features"`
 }
 
 func (r *RecommendParam) GetParameter(name string) interface{} {
@@ -42,6 +52,16 @@ func (r *RecommendParam) GetParameter(name string) interface{} {
 		return "default"
 	} else if name == "features" {
 		return r.Features
+	} else if name == "timeout" {
+		return r.Timeout
+	} else if name == "page" {
+		return r.Page
+	} else if name == "session_id" {
+		return r.SessionId
+	} else if name == "exclude_items" {
+		return r.ExcludeItems
+	} else if name == "pinned_items" {
+		return r.PinnedItems
 	}
 
 	return nil
@@ -49,13 +69,19 @@ func (r *RecommendParam) GetParameter(name string) interface{} {
 
 type RecommendResponse struct {
 	Response
-	Size  int         `json:"size"`
-	Items []*ItemData `json:"items"`
+	Size           int         `json:"size"`
+	Items          []*ItemData `json:"items"`
+	Fallback       bool        `json:"fallback,omitempty"`
+	FallbackReason string      `json:"fallback_reason,omitempty"`
 }
 type ItemData struct {
-	ItemId     string `json:"item_id"`
-	ItemType   string `json:"item_type"`
-	RetrieveId string `json:"retrieve_id"`
+	ItemId       string                 `json:"item_id"`
+	ItemType     string                 `json:"item_type"`
+	RetrieveId   string                 `json:"retrieve_id"`
+	Score        *float64               `json:"score,omitempty"` // nil when the scores are not returned, so a score of 0 is kept
+	RecallScores map[string]float64     `json:"recall_scores,omitempty"`
+	AlgoScores   map[string]float64     `json:"algo_scores,omitempty"`
+	Properties   map[string]interface{} `json:"properties,omitempty"`
 }
 
 func (r *RecommendResponse) ToString() string {
@@ -67,6 +93,8 @@ type RecommendController struct {
 	Controller
 	param   RecommendParam
 	context *context.RecommendContext
+	// requestCtx is the context of the incoming request, the client cancellation is propagated by it
+	requestCtx gocontext.Context
 }
 
 func (c *RecommendController) Process(w http.ResponseWriter, r *http.Request) {
@@ -96,65 +124,168 @@ func (r *RecommendController) CheckParameter() error {
 		return err
 	}
 
-	if len(r.param.Uid) == 0 {
+	return checkRecommendParam(&r.param)
+}
+
+// checkRecommendParam checks the param and sets the default values
+func checkRecommendParam(param *RecommendParam) error {
+	if len(param.Uid) == 0 {
 		return errors.New("uid not empty")
 	}
-	if r.param.Size <= 0 {
-		r.param.Size = Default_Size
+	if param.Size <= 0 {
+		param.Size = Default_Size
 	}
-	if r.param.SceneId == "" {
-		r.param.SceneId = "default_scene"
+	if param.SceneId == "" {
+		param.SceneId = "default_scene"
 	}
-	if r.param.Category == "" {
-		r.param.Category = "default"
+	if param.Category == "" {
+		param.Category = "default"
 	}
 
 	return nil
 }
 func (c *RecommendController) doProcess(w http.ResponseWriter, r *http.Request) {
+	c.requestCtx = r.Context()
 	c.makeRecommendContext()
-	userRecommendService := service.NewUserRecommendService()
-	items := userRecommendService.Recommend(c.context)
+	defer c.context.Cancel()
+	response := c.recommendResponse()
+	io.WriteString(w, response.ToString())
+}
+
+// recommendResponse recommends with the context made by makeRecommendContext
+func (c *RecommendController) recommendResponse() *RecommendResponse {
+	items, fallbackReason := c.recommend()
+	categoryConf := service.GetSceneCategoryConfig(c.param.SceneId, c.param.Category)
 	data := make([]*ItemData, 0)
 	for _, item := range items {
 		if c.param.Debug {
 			fmt.Println(item)
 		}
 
-		idata := &ItemData{
-			ItemId:     string(item.Id),
-			ItemType:   item.ItemType,
-			RetrieveId: item.RetrieveId,
-		}
+		idata := newItemData(item, categoryConf)
 
 		data = append(data, idata)
 	}
 
 	if len(data) < c.param.Size {
-		response := RecommendResponse{
-			Size:  len(data),
-			Items: data,
+		return &RecommendResponse{
+			Size:           len(data),
+			Items:          data,
+			Fallback:       fallbackReason != "",
+			FallbackReason: fallbackReason,
 			Response: Response{
 				RequestId: c.RequestId,
 				Code:      299,
 				Message:   "items size not enough",
 			},
 		}
-		io.WriteString(w, response.ToString())
-		return
 	}
 
-	response := RecommendResponse{
-		Size:  len(data),
-		Items: data,
+	return &RecommendResponse{
+		Size:           len(data),
+		Items:          data,
+		Fallback:       fallbackReason != "",
+		FallbackReason: fallbackReason,
 		Response: Response{
 			RequestId: c.RequestId,
 			Code:      200,
 			Message:   "success",
 		},
 	}
-	io.WriteString(w, response.ToString())
 }
+
+// newItemData makes the item of the response, the scores and the properties are returned as the category config
+func newItemData(item *module.Item, conf recconf.CategoryConfig) *ItemData {
+	idata := &ItemData{
+		ItemId:     string(item.Id),
+		ItemType:   item.ItemType,
+		RetrieveId: item.RetrieveId,
+	}
+
+	if conf.ReturnScores {
+		score := item.Score
+		idata.Score = &score
+		idata.RecallScores = item.RecallScores
+	}
+
+	if conf.ReturnAlgoScores {
+		if algoScores := item.CloneAlgoScores(); len(algoScores) > 0 {
+			idata.AlgoScores = algoScores
+		}
+	}
+
+	if len(conf.ReturnFields) > 0 {
+		idata.Properties = make(map[string]interface{}, len(conf.ReturnFields))
+		for _, field := range conf.ReturnFields {
+			if field == "*" {
+				for k, v := range item.GetCloneFeatures() {
+					idata.Properties[k] = v
+				}
+			} else if value := item.GetProperty(field); value != nil {
+				idata.Properties[field] = value
+			}
+		}
+	}
+
+	return idata
+}
+
+// recommend serves the page of the session from the cached ranked list when the pagination is configured,
+// otherwise the whole pipeline is invoked
+func (c *RecommendController) recommend() ([]*module.Item, string) {
+	userRecommendService := service.NewUserRecommendService()
+
+	p := pagination.GetPagination(c.param.SceneId, c.param.Category)
+	if p == nil || c.param.SessionId == "" {
+		items, fallbackReason := userRecommendService.RecommendWithFallback(c.context)
+		if fallbackReason != "" {
+			items = c.applyRequestItems(items)
+		}
+		return items, fallbackReason
+	}
+
+	if c.param.Page > 1 {
+		if items, ok := p.GetPage(c.context, c.param.SessionId, c.param.Page, c.param.Size); ok {
+			items = c.applyRequestItems(items)
+			log.Info(fmt.Sprintf("requestId=%s\tmodule=pagination\tsession_id=%s\tpage=%d\tcount=%d", c.RequestId, c.param.SessionId, c.param.Page, len(items)))
+			return items, ""
+		}
+	}
+
+	// rank the full list, the later pages of the session are served from it
+	c.context.Size = p.ListSize(c.param.Page, c.param.Size)
+	items, fallbackReason := userRecommendService.RecommendWithFallback(c.context)
+	c.context.Size = c.param.Size
+
+	// the degraded list is not cached, the next page recomputes the ranked list
+	if fallbackReason == "" {
+		p.Put(c.context, c.param.SessionId, items)
+	}
+
+	items = pagination.Page(items, c.param.Page, c.param.Size)
+	if fallbackReason != "" {
+		items = c.applyRequestItems(items)
+	}
+
+	return items, fallbackReason
+}
+
+// applyRequestItems removes the exclude_items and places the pinned_items of the request, it is used for the items
+// not served by the pipeline, such as the cached pages and the fallback items
+func (c *RecommendController) applyRequestItems(items []*module.Item) []*module.Item {
+	filterData := filter.FilterData{Uid: module.UID(c.param.Uid), Data: items, Context: c.context}
+	filter.NewExcludeItemsFilter().Filter(&filterData)
+
+	sortData := sort.SortData{Data: filterData.Data, Context: c.context}
+	sort.NewPinnedItemsSort().Sort(&sortData)
+
+	items = sortData.Data.([]*module.Item)
+	if len(items) > c.param.Size {
+		items = items[:c.param.Size]
+	}
+	return items
+}
+
 func (c *RecommendController) makeRecommendContext() {
 	c.context = context.NewRecommendContext()
 	c.context.Size = c.param.Size
@@ -162,6 +293,15 @@ func (c *RecommendController) makeRecommendContext() {
 	c.context.Param = &c.param
 	c.context.RecommendId = c.RequestId
 	c.context.Config = recconf.Config
+	if c.requestCtx != nil {
+		c.context.SetContext(c.requestCtx)
+	}
+
+	timeout := time.Duration(c.param.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = service.GetSceneTimeout(c.param.SceneId, c.param.Category)
+	}
+	c.context.SetTimeout(timeout)
 
 	abcontext := model.ExperimentContext{
 		Uid:          c.param.Uid,
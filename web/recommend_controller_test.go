@@ -0,0 +1,67 @@
+package web
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alibaba/pairec/v2/context"
+	"github.com/alibaba/pairec/v2/module"
+	"github.com/alibaba/pairec/v2/recconf"
+)
+
+func TestNewItemData(t *testing.T) {
+	item := module.NewItem("item1")
+	item.RetrieveId = "recall1"
+	item.Score = 0.8
+	item.RecallScores = map[string]float64{"recall1": 0.5, "recall2": 0.3}
+	item.AddAlgoScore("ctr", 0.1)
+	item.AddProperty("title", "hello")
+	item.AddProperty("price", 10)
+
+	data, _ := json.Marshal(newItemData(item, recconf.CategoryConfig{}))
+	if string(data) != `{"item_id":"item1","item_type":"","retrieve_id":"recall1"}` {
+		t.Errorf("expect only the item id without the return config, got %s", data)
+	}
+
+	idata := newItemData(item, recconf.CategoryConfig{ReturnFields: []string{"title", "not_exist"}, ReturnScores: true, ReturnAlgoScores: true})
+	if idata.Score == nil || *idata.Score != 0.8 || len(idata.RecallScores) != 2 || idata.AlgoScores["ctr"] != 0.1 {
+		t.Errorf("expect the scores returned, got %v", idata)
+	}
+	if len(idata.Properties) != 1 || idata.Properties["title"] != "hello" {
+		t.Errorf("expect the title returned, got %v", idata.Properties)
+	}
+
+	idata = newItemData(item, recconf.CategoryConfig{ReturnFields: []string{"*"}})
+	if len(idata.Properties) != 2 || idata.Score != nil || idata.AlgoScores != nil {
+		t.Errorf("expect all the properties returned without the scores, got %v", idata)
+	}
+
+	item.Score = 0
+	data, _ = json.Marshal(newItemData(item, recconf.CategoryConfig{ReturnScores: true}))
+	if string(data) != `{"item_id":"item1","item_type":"","retrieve_id":"recall1","score":0,"recall_scores":{"recall1":0.5,"recall2":0.3}}` {
+		t.Errorf("expect the score of 0 returned, got %s", data)
+	}
+}
+
+func TestApplyRequestItems(t *testing.T) {
+	c := &RecommendController{
+		param: RecommendParam{
+			Size:         3,
+			ExcludeItems: []string{"item2"},
+			PinnedItems:  []module.PinnedItem{{ItemId: "item9", Position: 1}},
+		},
+		context: context.NewRecommendContext(),
+	}
+	c.context.Param = &c.param
+
+	items := []*module.Item{module.NewItem("item1"), module.NewItem("item2"), module.NewItem("item3"), module.NewItem("item4")}
+	items = c.applyRequestItems(items)
+
+	var ids []module.ItemId
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+	if len(ids) != 3 || ids[0] != "item9" || ids[1] != "item1" || ids[2] != "item3" {
+		t.Errorf("expect the excluded item removed and the pinned item placed, got %v", ids)
+	}
+}
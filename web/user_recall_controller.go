@@ -83,23 +83,34 @@ func (r *UserRecallController) CheckParameter() error {
 		return err
 	}
 
-	if len(r.param.Uid) == 0 {
+	return checkUserRecallParam(&r.param)
+}
+
+// checkUserRecallParam checks the param and sets the default values
+func checkUserRecallParam(param *UserRecallParam) error {
+	if len(param.Uid) == 0 {
 		return errors.New("uid not empty")
 	}
-	if r.param.Size <= 0 {
-		r.param.Size = Default_Size
+	if param.Size <= 0 {
+		param.Size = Default_Size
 	}
-	if r.param.SceneId == "" {
-		r.param.SceneId = "default_scene"
+	if param.SceneId == "" {
+		param.SceneId = "default_scene"
 	}
-	if r.param.Category == "" {
-		r.param.Category = "default_category"
+	if param.Category == "" {
+		param.Category = "default_category"
 	}
 
 	return nil
 }
 func (c *UserRecallController) doProcess(w http.ResponseWriter, r *http.Request) {
 	c.makeRecommendContext()
+	response := c.recallResponse()
+	io.WriteString(w, response.ToString())
+}
+
+// recallResponse recalls with the context made by makeRecommendContext
+func (c *UserRecallController) recallResponse() *UserRecallResponse {
 	userRecallService := service.NewUserRecallService()
 	items := userRecallService.Recommend(c.context)
 	data := make([]*UserRecallItemData, 0)
@@ -115,7 +126,7 @@ func (c *UserRecallController) doProcess(w http.ResponseWriter, r *http.Request)
 
 	errs := make([]error, 0)
 	if len(data) < c.param.Size {
-		response := UserRecallResponse{
+		return &UserRecallResponse{
 			Size:   len(data),
 			Items:  data,
 			Errors: errs,
@@ -125,11 +136,9 @@ func (c *UserRecallController) doProcess(w http.ResponseWriter, r *http.Request)
 				Message:   "items size not enough",
 			},
 		}
-		io.WriteString(w, response.ToString())
-		return
 	}
 
-	response := UserRecallResponse{
+	return &UserRecallResponse{
 		Size:   len(data),
 		Items:  data,
 		Errors: errs,
@@ -139,7 +148,6 @@ func (c *UserRecallController) doProcess(w http.ResponseWriter, r *http.Request)
 			Message:   "success",
 		},
 	}
-	io.WriteString(w, response.ToString())
 }
 func (c *UserRecallController) makeRecommendContext() {
 	c.context = context.NewRecommendContext()